@@ -1,17 +1,30 @@
 package main
 
 import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/berkantay/colog/v2/internal/docker"
+	"github.com/berkantay/colog/v2/internal/sdk"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 	"github.com/gorilla/mux"
@@ -22,14 +35,16 @@ import (
 
 // Docker types (copied from main package)
 type Container struct {
-	ID     string
-	Name   string
-	Image  string
-	Status string
+	ID      string
+	Name    string
+	Image   string
+	ImageID string
+	Status  string
 }
 
 type DockerService struct {
 	client *client.Client
+	logs   *docker.DockerService // reads logs over client like colog's own panes and SDK
 }
 
 type DockerEndpoint struct {
@@ -45,6 +60,20 @@ type LogEntry struct {
 	Timestamp   time.Time
 	Message     string
 	Stream      string
+	Host        string // label of the Docker host, empty with a single host
+	Seq         int64  // per-container cursor, nanoseconds since epoch of Timestamp
+}
+
+// logEntryFrom converts an entry read by internal/docker, keyed for cursor paging
+func logEntryFrom(entry docker.LogEntry) LogEntry {
+	return LogEntry{
+		ContainerID: entry.ContainerID,
+		Timestamp:   entry.Timestamp,
+		Message:     entry.Message,
+		Stream:      entry.Stream,
+		Host:        entry.Host,
+		Seq:         entry.Timestamp.UnixNano(),
+	}
 }
 
 // MCPServer represents the Model Context Protocol server for Docker logs
@@ -57,6 +86,42 @@ type MCPServer struct {
 	host        string
 	auth        *AuthConfig
 	ctx         context.Context
+	stats       *ServerStats
+	logRequests bool
+	logLevel    string
+	allowedContainers []string // name/ID patterns tools may touch, empty allows all
+	readOnly    bool         // hide and reject tools that mutate containers
+	allowControl bool        // expose restart_container/kill_container (COLOG_MCP_ALLOW_CONTROL)
+	statsInterval time.Duration // default interval for live stats notifications
+}
+
+// ContainerStats holds a point-in-time resource usage sample for a container
+type ContainerStats struct {
+	ContainerID   string  `json:"container_id"`
+	Name          string  `json:"name"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryUsage   uint64  `json:"memory_usage"`
+	MemoryLimit   uint64  `json:"memory_limit"`
+	MemoryPercent float64 `json:"memory_percent"`
+}
+
+// mutatingTools lists tools that change container state and are disabled in read-only mode
+var mutatingTools = map[string]bool{
+	"restart_container": true,
+	"kill_container":    true,
+	"stop_container":    true,
+	"exec_container":    true,
+}
+
+// ServerStats accumulates request counters reported by the health endpoint
+type ServerStats struct {
+	StartTime   time.Time
+	Requests    int
+	Errors      int
+	ToolCalls   map[string]int
+	ToolErrors  map[string]int
+	LastRequest time.Time
+	mutex       sync.RWMutex
 }
 
 // Session represents an MCP session with SSE support
@@ -70,6 +135,8 @@ type Session struct {
 	Context     context.Context
 	Cancel      context.CancelFunc
 	RequestChan chan MCPRequest
+	Requests    int
+	statsCancel context.CancelFunc // stops the live stats push, nil when not subscribed
 	mutex       sync.RWMutex
 }
 
@@ -78,6 +145,7 @@ type AuthConfig struct {
 	APIKey        string
 	AllowedOrigins []string
 	RequireAuth   bool
+	AllowQueryKey bool // accept the deprecated api_key query parameter
 }
 
 // MCPRequest represents an incoming MCP request
@@ -125,17 +193,24 @@ func NewMCPServer(port, host string, auth *AuthConfig) (*MCPServer, error) {
 		}
 	}
 
+	origins, err := validateOrigins(auth.AllowedOrigins)
+	if err != nil {
+		return nil, err
+	}
+	auth.AllowedOrigins = origins
+
 	return &MCPServer{
 		dockerService: nil, // Initialize lazily when needed
 		sessions: make(map[string]*Session),
 		upgrader: websocket.Upgrader{
 			CheckOrigin: func(r *http.Request) bool {
 				origin := r.Header.Get("Origin")
-				if len(auth.AllowedOrigins) == 0 || auth.AllowedOrigins[0] == "*" {
+				// Non-browser clients don't send an Origin header
+				if origin == "" || allowsAnyOrigin(auth.AllowedOrigins) {
 					return true
 				}
 				for _, allowed := range auth.AllowedOrigins {
-					if origin == allowed {
+					if strings.EqualFold(origin, allowed) {
 						return true
 					}
 				}
@@ -146,6 +221,12 @@ func NewMCPServer(port, host string, auth *AuthConfig) (*MCPServer, error) {
 		host: host,
 		auth: auth,
 		ctx:  ctx,
+		stats: &ServerStats{
+			StartTime:  time.Now(),
+			ToolCalls:  make(map[string]int),
+			ToolErrors: make(map[string]int),
+		},
+		statsInterval: 5 * time.Second,
 	}, nil
 }
 
@@ -159,12 +240,14 @@ func (s *MCPServer) Start() error {
 	router.HandleFunc("/health", s.handleHealth).Methods("GET")
 	router.HandleFunc("/capabilities", s.handleCapabilities).Methods("GET")
 
-	// Setup CORS
+	// Setup CORS. Credentials can't be combined with a wildcard origin, so they
+	// are only allowed when specific origins are configured; the matched origin
+	// is then echoed back instead of "*".
 	c := cors.New(cors.Options{
 		AllowedOrigins: s.auth.AllowedOrigins,
 		AllowedMethods: []string{"GET", "POST", "OPTIONS"},
 		AllowedHeaders: []string{"*"},
-		AllowCredentials: true,
+		AllowCredentials: !allowsAnyOrigin(s.auth.AllowedOrigins),
 	})
 
 	handler := c.Handler(router)
@@ -174,6 +257,11 @@ func (s *MCPServer) Start() error {
 		handler = s.authMiddleware(handler)
 	}
 
+	// Request logging wraps everything so rejected requests are logged too
+	if s.logRequests {
+		handler = s.loggingMiddleware(handler)
+	}
+
 	addr := fmt.Sprintf("%s:%s", s.host, s.port)
 	log.Printf("🚀 MCP Docker Log Server starting on http://%s", addr)
 	log.Printf("🔧 Health check: http://%s/health", addr)
@@ -184,6 +272,12 @@ func (s *MCPServer) Start() error {
 
 // handleMCPConnection handles initial MCP connection with SSE support
 func (s *MCPServer) handleMCPConnection(w http.ResponseWriter, r *http.Request) {
+	// WebSocket clients get a bidirectional connection that can stream logs
+	if websocket.IsWebSocketUpgrade(r) {
+		s.handleWebSocket(w, r)
+		return
+	}
+
 	sessionID := r.URL.Query().Get("sessionId")
 	if sessionID == "" {
 		sessionID = xid.New().String()
@@ -193,7 +287,6 @@ func (s *MCPServer) handleMCPConnection(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
-	w.Header().Set("Access-Control-Allow-Origin", "*")
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -227,8 +320,13 @@ func (s *MCPServer) handleMCPConnection(w http.ResponseWriter, r *http.Request)
 		Params: s.getCapabilities(),
 	})
 
-	// Keep connection alive and handle cleanup
+	// Keep connection alive and handle cleanup. Cancelling the session context
+	// also stops any live stats push derived from it; marking the session inactive
+	// under its mutex keeps pushes from writing to w once the handler returns.
 	defer func() {
+		session.mutex.Lock()
+		session.SSEActive = false
+		session.mutex.Unlock()
 		cancel()
 		s.sessionsMux.Lock()
 		delete(s.sessions, sessionID)
@@ -252,6 +350,174 @@ func (s *MCPServer) handleMCPConnection(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// wsConn serializes writes to a websocket connection, which allows one writer at a time
+type wsConn struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+func (c *wsConn) send(message interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.conn.WriteJSON(message)
+}
+
+// handleWebSocket serves JSON-RPC requests over a websocket. Besides the regular
+// methods it supports the stream_container_logs tool, which pushes log lines as
+// notifications. Streams are cancelled when the client disconnects.
+func (s *MCPServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already replied with an HTTP error
+	}
+	defer conn.Close()
+
+	// A hijacked connection's request context isn't cancelled on disconnect, the
+	// failing read below is what notices the client went away
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	ws := &wsConn{conn: conn}
+	streams := make(map[string]context.CancelFunc) // container ID → its log stream
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req MCPRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			ws.send(MCPResponse{Error: &MCPError{Code: -32700, Message: "Parse error"}})
+			continue
+		}
+
+		var response MCPResponse
+		if isToolCall(&req, "stream_container_logs") {
+			params, _ := req.Params.(map[string]interface{})
+			args, _ := params["arguments"].(map[string]interface{})
+			response = s.handleStreamLogsTool(ctx, ws, streams, req.ID, args)
+		} else {
+			response = s.handleRequest(&req)
+		}
+		s.stats.record(&req, response)
+
+		if err := ws.send(response); err != nil {
+			return
+		}
+	}
+}
+
+// isToolCall reports whether req is a tools/call request for the named tool
+func isToolCall(req *MCPRequest, name string) bool {
+	if req.Method != "tools/call" {
+		return false
+	}
+	params, _ := req.Params.(map[string]interface{})
+	toolName, _ := params["name"].(string)
+	return toolName == name
+}
+
+// handleStreamLogsTool starts (or with stop=true, stops) pushing a container's log
+// lines to the websocket as notifications/log notifications
+func (s *MCPServer) handleStreamLogsTool(ctx context.Context, ws *wsConn, streams map[string]context.CancelFunc, id interface{}, args map[string]interface{}) MCPResponse {
+	containerID, ok := args["container_id"].(string)
+	if !ok {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Missing required parameter: container_id",
+			},
+		}
+	}
+
+	if err := s.checkToolScope(args); err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32001,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	if cancel, exists := streams[containerID]; exists {
+		cancel()
+		delete(streams, containerID)
+	}
+	if stop, _ := args["stop"].(bool); stop {
+		return MCPResponse{
+			ID:     id,
+			Result: map[string]interface{}{"container_id": containerID, "streaming": false},
+		}
+	}
+
+	tail := 10
+	if t, ok := args["tail"].(float64); ok && t >= 0 {
+		tail = int(t)
+	}
+
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Docker connection failed: " + err.Error(),
+			},
+		}
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	logCh := make(chan LogEntry, 100)
+	if err := dockerService.StreamLogs(streamCtx, containerID, tail, logCh); err != nil {
+		cancel()
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to stream logs: " + err.Error(),
+			},
+		}
+	}
+	streams[containerID] = cancel
+
+	go func() {
+		defer cancel()
+		for entry := range logCh {
+			err := ws.send(MCPNotification{
+				Method: "notifications/log",
+				Params: map[string]interface{}{
+					"container_id": containerID,
+					"timestamp":    entry.Timestamp.Format(time.RFC3339Nano),
+					"message":      entry.Message,
+					"stream":       entry.Stream,
+					"seq":          entry.Seq,
+				},
+			})
+			if err != nil {
+				return
+			}
+		}
+		// The container stopped (or the stream was cancelled): tell the client
+		if streamCtx.Err() == nil {
+			ws.send(MCPNotification{
+				Method: "notifications/log_stream_end",
+				Params: map[string]interface{}{"container_id": containerID},
+			})
+		}
+	}()
+
+	return MCPResponse{
+		ID: id,
+		Result: map[string]interface{}{
+			"container_id": containerID,
+			"streaming":    true,
+			"tail":         tail,
+		},
+	}
+}
+
 // handleMCPRequest handles MCP requests via HTTP POST
 func (s *MCPServer) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
 	sessionID := r.Header.Get("X-Session-ID")
@@ -265,12 +531,25 @@ func (s *MCPServer) handleMCPRequest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := s.handleRequest(&req)
+	var response MCPResponse
+	switch req.Method {
+	case "stats/subscribe", "stats/unsubscribe":
+		// Subscriptions push over the session's SSE stream, so they need the session
+		response = s.handleStatsSubscription(sessionID, &req)
+	default:
+		response = s.handleRequest(&req)
+	}
+	s.stats.record(&req, response)
 
 	// If we have an active session, also send via SSE
 	if sessionID != "" {
 		s.sessionsMux.RLock()
-		if session, exists := s.sessions[sessionID]; exists && session.SSEActive {
+		if session, exists := s.sessions[sessionID]; exists {
+			session.mutex.Lock()
+			session.LastAccess = time.Now()
+			session.Requests++
+			session.mutex.Unlock()
+
 			s.sendSSEMessage(session, response)
 		}
 		s.sessionsMux.RUnlock()
@@ -339,6 +618,35 @@ func (s *MCPServer) handleToolCall(req *MCPRequest) MCPResponse {
 
 	args, _ := params["arguments"].(map[string]interface{})
 
+	if s.readOnly && mutatingTools[toolName] {
+		return MCPResponse{
+			ID: req.ID,
+			Error: &MCPError{
+				Code:    -32002,
+				Message: fmt.Sprintf("Tool %s is disabled: server is running in read-only mode", toolName),
+			},
+		}
+	}
+	if !s.controlEnabled() && mutatingTools[toolName] {
+		return MCPResponse{
+			ID: req.ID,
+			Error: &MCPError{
+				Code:    -32002,
+				Message: fmt.Sprintf("Tool %s is disabled: set COLOG_MCP_ALLOW_CONTROL=true to allow container control", toolName),
+			},
+		}
+	}
+
+	if err := s.checkToolScope(args); err != nil {
+		return MCPResponse{
+			ID: req.ID,
+			Error: &MCPError{
+				Code:    -32001,
+				Message: err.Error(),
+			},
+		}
+	}
+
 	switch toolName {
 	case "list_containers":
 		return s.handleContainersListTool(req.ID, args)
@@ -346,8 +654,24 @@ func (s *MCPServer) handleToolCall(req *MCPRequest) MCPResponse {
 		return s.handleContainerLogsTool(req.ID, args)
 	case "export_logs_llm":
 		return s.handleExportLogsTool(req.ID, args)
+	case "export_logs_archive":
+		return s.handleExportArchiveTool(req.ID, args)
+	case "summarize_errors":
+		return s.handleSummarizeErrorsTool(req.ID, args)
 	case "filter_containers":
 		return s.handleFilterContainersTool(req.ID, args)
+	case "restart_container":
+		return s.handleControlContainerTool(req.ID, args, "restart")
+	case "kill_container":
+		return s.handleControlContainerTool(req.ID, args, "kill")
+	case "stream_container_logs":
+		return MCPResponse{
+			ID: req.ID,
+			Error: &MCPError{
+				Code:    -32600,
+				Message: "stream_container_logs needs a websocket connection to GET /mcp",
+			},
+		}
 	default:
 		return MCPResponse{
 			ID: req.ID,
@@ -359,6 +683,122 @@ func (s *MCPServer) handleToolCall(req *MCPRequest) MCPResponse {
 	}
 }
 
+// handleStatsSubscription starts or stops periodic container stats notifications for a session
+func (s *MCPServer) handleStatsSubscription(sessionID string, req *MCPRequest) MCPResponse {
+	s.sessionsMux.RLock()
+	session, exists := s.sessions[sessionID]
+	s.sessionsMux.RUnlock()
+	if exists {
+		session.mutex.RLock()
+		exists = session.SSEActive
+		session.mutex.RUnlock()
+	}
+	if !exists {
+		return MCPResponse{
+			ID: req.ID,
+			Error: &MCPError{
+				Code:    -32602,
+				Message: "Stats subscriptions require an active SSE session (X-Session-ID)",
+			},
+		}
+	}
+
+	// Replace any existing subscription
+	session.mutex.Lock()
+	if session.statsCancel != nil {
+		session.statsCancel()
+		session.statsCancel = nil
+	}
+	session.mutex.Unlock()
+
+	if req.Method == "stats/unsubscribe" {
+		return MCPResponse{
+			ID:     req.ID,
+			Result: map[string]interface{}{"subscribed": false},
+		}
+	}
+
+	params, _ := req.Params.(map[string]interface{})
+	if err := s.checkToolScope(params); err != nil {
+		return MCPResponse{
+			ID: req.ID,
+			Error: &MCPError{
+				Code:    -32001,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	interval := s.statsInterval
+	if seconds, ok := params["interval_seconds"].(float64); ok && seconds >= 1 {
+		interval = time.Duration(seconds * float64(time.Second))
+	}
+
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return MCPResponse{
+			ID: req.ID,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Docker connection failed: " + err.Error(),
+			},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(session.Context)
+	session.mutex.Lock()
+	session.statsCancel = cancel
+	session.mutex.Unlock()
+
+	go s.pushStats(ctx, session, dockerService, params, interval)
+
+	return MCPResponse{
+		ID: req.ID,
+		Result: map[string]interface{}{
+			"subscribed":       true,
+			"interval_seconds": interval.Seconds(),
+		},
+	}
+}
+
+// pushStats samples container stats every interval until the subscription is cancelled
+func (s *MCPServer) pushStats(ctx context.Context, session *Session, dockerService *DockerService, params map[string]interface{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		containers, err := s.selectExportContainers(dockerService, params)
+		if err == nil {
+			var samples []ContainerStats
+			for _, container := range containers {
+				stats, err := dockerService.GetContainerStats(ctx, container.ID)
+				if err != nil {
+					continue
+				}
+				stats.Name = container.Name
+				samples = append(samples, *stats)
+			}
+
+			if ctx.Err() != nil {
+				return
+			}
+			s.sendSSEMessage(session, MCPNotification{
+				Method: "notifications/stats",
+				Params: map[string]interface{}{
+					"timestamp":  time.Now().Format(time.RFC3339),
+					"containers": samples,
+				},
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // Docker service implementation (copied from main package)
 func NewDockerServiceWithSelection(interactive bool) (*DockerService, error) {
 	endpoints := discoverDockerEndpoints()
@@ -520,7 +960,8 @@ func connectToDockerEndpoint(endpoint DockerEndpoint) (*DockerService, error) {
 	cli, err := client.NewClientWithOpts(
 		client.WithHost(endpoint.Host),
 		client.WithAPIVersionNegotiation(),
-		client.WithTimeout(5*time.Second),
+		// No client-wide timeout: it would also cut off followed log streams. The
+		// ping below is bounded by its context instead.
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client for %s: %w", endpoint.Name, err)
@@ -536,7 +977,7 @@ func connectToDockerEndpoint(endpoint DockerEndpoint) (*DockerService, error) {
 	}
 	
 	log.Printf("✓ Connected to Docker via %s (%s)", endpoint.Name, endpoint.Description)
-	return &DockerService{client: cli}, nil
+	return &DockerService{client: cli, logs: docker.NewDockerServiceFromClient(cli)}, nil
 }
 
 func (ds *DockerService) Close() error {
@@ -551,156 +992,198 @@ func (ds *DockerService) ListRunningContainers(ctx context.Context) ([]Container
 
 	var result []Container
 	for _, ctr := range containers {
-		name := strings.TrimPrefix(ctr.Names[0], "/")
-		result = append(result, Container{
-			ID:     ctr.ID[:12],
-			Name:   name,
-			Image:  ctr.Image,
-			Status: ctr.Status,
-		})
+		result = append(result, containerFromSummary(ctr))
 	}
 
 	return result, nil
 }
 
+// containerFromSummary converts a listed container. It keeps the full ID, which
+// the allowlist is matched against the same way as for an inspected container;
+// output shortens it for display.
+func containerFromSummary(ctr container.Summary) Container {
+	name := ctr.Image
+	if len(ctr.Names) > 0 && ctr.Names[0] != "" {
+		name = strings.TrimPrefix(ctr.Names[0], "/")
+	} else if ctr.ID != "" {
+		// Some runtimes list containers without names
+		name = truncateContainerID(ctr.ID)
+	}
+	return Container{
+		ID:      ctr.ID,
+		Name:    name,
+		Image:   ctr.Image,
+		ImageID: ctr.ImageID,
+		Status:  ctr.Status,
+	}
+}
+
 func (ds *DockerService) GetRecentLogs(ctx context.Context, containerID string, tail int) ([]LogEntry, error) {
-	// Use Docker SDK - this works regardless of PATH issues
-	options := container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
+	return ds.getLogs(ctx, containerID, docker.LogStreamOptions{Tail: tail, Timestamps: true})
+}
+
+// GetLogsAfter returns the oldest tail log entries with a Seq greater than afterSeq,
+// so a caller paging with the last Seq it got misses nothing
+func (ds *DockerService) GetLogsAfter(ctx context.Context, containerID string, afterSeq int64, tail int) ([]LogEntry, error) {
+	// Docker's tail keeps the newest lines, so read everything since the cursor
+	logs, err := ds.getLogs(ctx, containerID, docker.LogStreamOptions{
+		Since:      time.Unix(0, afterSeq),
+		Tail:       -1,
 		Timestamps: true,
-		Tail:       fmt.Sprintf("%d", tail),
-	}
-	
-	out, err := ds.client.ContainerLogs(ctx, containerID, options)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get logs for container %s: %w", containerID, err)
+		return nil, err
 	}
-	defer out.Close()
-	
-	var logs []LogEntry
-	
-	// Docker API returns logs with a special header format
-	// Read raw bytes and handle the multiplexed stream
-	buf := make([]byte, 8) // Header is 8 bytes
-	var logData []byte
-	
-	for {
-		// Read header
-		n, err := out.Read(buf)
-		if err != nil || n == 0 {
-			break // End of stream
-		}
-		
-		// Extract payload size from header (bytes 4-7, big-endian)
-		if n >= 8 {
-			payloadSize := int(buf[4])<<24 | int(buf[5])<<16 | int(buf[6])<<8 | int(buf[7])
-			
-			// Read payload
-			payload := make([]byte, payloadSize)
-			n, err := out.Read(payload)
-			if err != nil || n != payloadSize {
-				break
-			}
-			
-			logData = append(logData, payload...)
+	return entriesAfter(logs, afterSeq, tail), nil
+}
+
+// entriesAfter keeps the oldest tail entries with a Seq greater than afterSeq (all
+// of them when tail isn't positive). Entries sharing the last kept Seq are kept too,
+// since a cursor can't point between them.
+func entriesAfter(logs []LogEntry, afterSeq int64, tail int) []LogEntry {
+	// Docker's since filter is inclusive, drop entries the caller already has
+	var newer []LogEntry
+	for _, entry := range logs {
+		if entry.Seq > afterSeq {
+			newer = append(newer, entry)
 		}
 	}
-	
-	// Parse the collected log data
-	lines := strings.Split(string(logData), "\\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		
-		logEntry := parseLogEntry(containerID, line)
-		if !logEntry.Timestamp.IsZero() {
-			logs = append(logs, logEntry)
-		}
+	if tail <= 0 || len(newer) <= tail {
+		return newer
 	}
-	
-	return logs, nil
+	end := tail
+	for end < len(newer) && newer[end].Seq == newer[tail-1].Seq {
+		end++
+	}
+	return newer[:end]
 }
 
-// RestartContainer restarts a running container
-func (ds *DockerService) RestartContainer(ctx context.Context, containerID string) error {
-	return ds.client.ContainerRestart(ctx, containerID, container.StopOptions{})
+// cursorArg reads a since_seq cursor given as a string (exact) or a number (a
+// JSON number can't hold every nanosecond cursor exactly); ok when present and valid
+func cursorArg(args map[string]interface{}, name string) (int64, bool) {
+	switch value := args[name].(type) {
+	case string:
+		seq, err := strconv.ParseInt(value, 10, 64)
+		return seq, err == nil && seq >= 0
+	case float64:
+		return int64(value), value >= 0
+	}
+	return 0, false
 }
 
-// KillContainer forcefully kills a running container
-func (ds *DockerService) KillContainer(ctx context.Context, containerID string) error {
-	return ds.client.ContainerKill(ctx, containerID, "SIGKILL")
+// getLogs reads a container's logs once through internal/docker
+func (ds *DockerService) getLogs(ctx context.Context, containerID string, opts docker.LogStreamOptions) ([]LogEntry, error) {
+	entries, err := ds.logs.GetLogs(ctx, containerID, opts)
+	logs := make([]LogEntry, 0, len(entries))
+	for _, entry := range entries {
+		logs = append(logs, logEntryFrom(entry))
+	}
+	return logs, err
+}
+
+// StreamLogs follows a container's logs, starting with the last tail lines, and sends
+// each entry to logCh until ctx is cancelled or the container stops. logCh is closed
+// when the stream ends.
+func (ds *DockerService) StreamLogs(ctx context.Context, containerID string, tail int, logCh chan<- LogEntry) error {
+	entries := make(chan docker.LogEntry, 100)
+	opts := docker.LogStreamOptions{Tail: tail, Follow: true, Timestamps: true}
+	if err := ds.logs.StreamLogs(ctx, containerID, opts, entries); err != nil {
+		return err
+	}
+
+	go func() {
+		defer close(logCh)
+		for entry := range entries {
+			select {
+			case logCh <- logEntryFrom(entry):
+			case <-ctx.Done():
+			}
+		}
+	}()
+
+	return nil
 }
 
-func parseLogEntry(containerID, line string) LogEntry {
-	if len(line) == 0 {
-		return LogEntry{}
+// GetContainerStats takes a single CPU/memory sample for a container
+func (ds *DockerService) GetContainerStats(ctx context.Context, containerID string) (*ContainerStats, error) {
+	// A non-streaming read includes the previous sample, which CPU percent needs
+	reader, err := ds.client.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats for container %s: %w", containerID, err)
 	}
+	defer reader.Body.Close()
 
-	originalLine := line
-	
-	// Check if this line has the Docker multiplexed header
-	if len(line) >= 8 {
-		// First byte indicates stream type: 1 for stdout, 2 for stderr
-		firstByte := byte(line[0])
-		if firstByte == 1 || firstByte == 2 {
-			// Skip the 8-byte header
-			line = line[8:]
-		}
+	var raw container.StatsResponse
+	if err := json.NewDecoder(reader.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode stats for container %s: %w", containerID, err)
 	}
-	
-	// Trim whitespace after header removal
-	line = strings.TrimSpace(line)
-	if len(line) == 0 {
-		return LogEntry{}
+
+	stats := &ContainerStats{
+		ContainerID: containerID,
+		MemoryUsage: raw.MemoryStats.Usage,
+		MemoryLimit: raw.MemoryStats.Limit,
 	}
-	
-	// Try to parse timestamp if present
-	parts := strings.SplitN(line, " ", 2)
-	var timestamp time.Time
-	var message string
-	
-	if len(parts) >= 2 {
-		// Try multiple timestamp formats
-		timestampFormats := []string{
-			time.RFC3339Nano,
-			time.RFC3339,
-			"2006-01-02T15:04:05.000000000Z",
-			"2006-01-02T15:04:05.000Z",
-		}
-		
-		parsed := false
-		for _, format := range timestampFormats {
-			if ts, err := time.Parse(format, parts[0]); err == nil {
-				timestamp = ts
-				message = parts[1]
-				parsed = true
-				break
-			}
-		}
-		
-		if !parsed {
-			// No valid timestamp found, treat entire line as message
-			timestamp = time.Now()
-			message = line
-		}
-	} else {
-		timestamp = time.Now()
-		message = line
+
+	cpuDelta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(raw.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(raw.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * onlineCPUs * 100
 	}
+	if stats.MemoryLimit > 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+	}
+
+	return stats, nil
+}
+
+// RestartContainer restarts a running container
+func (ds *DockerService) RestartContainer(ctx context.Context, containerID string) error {
+	return ds.client.ContainerRestart(ctx, containerID, container.StopOptions{})
+}
 
-	// If message is still empty, use the original line as fallback
-	if message == "" {
-		message = strings.TrimSpace(originalLine)
+// KillContainer forcefully kills a running container
+func (ds *DockerService) KillContainer(ctx context.Context, containerID string) error {
+	return ds.client.ContainerKill(ctx, containerID, "SIGKILL")
+}
+
+// ImageDigest returns the repo digest ("nginx@sha256:…") of a container's image, or
+// "" for images that were built locally and never pushed or pulled
+func (ds *DockerService) ImageDigest(ctx context.Context, image, imageID string) (string, error) {
+	info, err := ds.client.ImageInspect(ctx, imageID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", imageID, err)
 	}
+	return pickRepoDigest(image, info.RepoDigests), nil
+}
 
-	return LogEntry{
-		ContainerID: containerID,
-		Timestamp:   timestamp,
-		Message:     message,
-		Stream:      "stdout",
+// pickRepoDigest picks the digest of image's repository from an image's repo digests
+func pickRepoDigest(image string, digests []string) string {
+	repo, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i] // drop the tag, but not a registry port
+	}
+	for _, digest := range digests {
+		if strings.HasPrefix(digest, repo+"@") {
+			return digest
+		}
+	}
+	if len(digests) > 0 {
+		return digests[0]
 	}
+	return ""
+}
+
+// imageDetails formats a container's image ID and digest for export headers
+func imageDetails(ctx context.Context, dockerService *DockerService, container Container) string {
+	details := fmt.Sprintf("- Image ID: %s\n", container.ImageID)
+	if digest, _ := dockerService.ImageDigest(ctx, container.Image, container.ImageID); digest != "" {
+		details += fmt.Sprintf("- Image Digest: %s\n", digest)
+	}
+	return details
 }
 
 // Helper method to get Docker service with lazy initialization
@@ -715,6 +1198,92 @@ func (s *MCPServer) getDockerService() (*DockerService, error) {
 	return s.dockerService, nil
 }
 
+// listAllowedContainers lists running containers that fall within the allowlist
+func (s *MCPServer) listAllowedContainers(dockerService *DockerService) ([]Container, error) {
+	containers, err := dockerService.ListRunningContainers(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.filterAllowedContainers(containers), nil
+}
+
+// filterAllowedContainers keeps the containers that fall within the allowlist
+func (s *MCPServer) filterAllowedContainers(containers []Container) []Container {
+	if len(s.allowedContainers) == 0 {
+		return containers
+	}
+
+	var allowed []Container
+	for _, container := range containers {
+		if s.isContainerAllowed(container.ID, container.Name) {
+			allowed = append(allowed, container)
+		}
+	}
+	return allowed
+}
+
+// isContainerAllowed matches a container's name or ID against the allowlist patterns
+func (s *MCPServer) isContainerAllowed(id, name string) bool {
+	if len(s.allowedContainers) == 0 {
+		return true
+	}
+	for _, pattern := range s.allowedContainers {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+		if matched, _ := path.Match(pattern, id); matched {
+			return true
+		}
+		if len(pattern) >= 12 && strings.HasPrefix(id, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkContainerAccess resolves an ID or name and rejects containers outside the allowlist
+func (s *MCPServer) checkContainerAccess(containerID string) error {
+	if len(s.allowedContainers) == 0 {
+		return nil
+	}
+
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return fmt.Errorf("Docker connection failed: %w", err)
+	}
+
+	info, err := dockerService.client.ContainerInspect(s.ctx, containerID)
+	if err != nil || !s.isContainerAllowed(info.ID, strings.TrimPrefix(info.Name, "/")) {
+		// Don't reveal whether an out-of-scope container exists
+		return fmt.Errorf("Access denied: container %s is outside the allowed scope", containerID)
+	}
+	return nil
+}
+
+// checkToolScope enforces the allowlist on any container arguments of a tool call
+func (s *MCPServer) checkToolScope(args map[string]interface{}) error {
+	var ids []string
+	if id, ok := args["container_id"].(string); ok {
+		ids = append(ids, id)
+	}
+	for _, key := range []string{"container_ids", "containers"} {
+		if list, ok := args[key].([]interface{}); ok {
+			for _, item := range list {
+				if id, ok := item.(string); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+
+	for _, id := range ids {
+		if err := s.checkContainerAccess(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Tool implementations
 func (s *MCPServer) handleContainersListTool(id interface{}, args map[string]interface{}) MCPResponse {
 	dockerService, err := s.getDockerService()
@@ -728,7 +1297,7 @@ func (s *MCPServer) handleContainersListTool(id interface{}, args map[string]int
 		}
 	}
 
-	containers, err := dockerService.ListRunningContainers(s.ctx)
+	containers, err := s.listAllowedContainers(dockerService)
 	if err != nil {
 		return MCPResponse{
 			ID: id,
@@ -742,15 +1311,23 @@ func (s *MCPServer) handleContainersListTool(id interface{}, args map[string]int
 	// Format containers for display
 	var containerList []string
 	for _, container := range containers {
-		status := container.Status
-		if len(status) > 20 {
-			status = status[:20] + "..."
-		}
-		containerList = append(containerList, fmt.Sprintf("• %s (%s) - %s", container.Name, container.ID[:12], status))
+		status := truncate(container.Status, 23)
+		containerList = append(containerList, fmt.Sprintf("• %s (%s) - %s", container.Name, truncateContainerID(container.ID), status))
 	}
 
 	response := fmt.Sprintf("Found %d containers:\n\n%s", len(containers), strings.Join(containerList, "\n"))
 
+	resource, err := containersResource(containers)
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to encode containers: " + err.Error(),
+			},
+		}
+	}
+
 	return MCPResponse{
 		ID: id,
 		Result: map[string]interface{}{
@@ -759,11 +1336,48 @@ func (s *MCPServer) handleContainersListTool(id interface{}, args map[string]int
 					"type": "text",
 					"text": response,
 				},
+				resource,
 			},
 		},
 	}
 }
 
+// containerJSON is a container as list_containers returns it in its JSON resource
+type containerJSON struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	ImageID string `json:"image_id"`
+	Status  string `json:"status"`
+}
+
+// containersResource returns a resource content block carrying the containers as a
+// JSON array, so clients can use typed data instead of parsing the text summary
+func containersResource(containers []Container) (map[string]interface{}, error) {
+	list := make([]containerJSON, 0, len(containers))
+	for _, container := range containers {
+		list = append(list, containerJSON{
+			ID:      truncateContainerID(container.ID),
+			Name:    container.Name,
+			Image:   container.Image,
+			ImageID: container.ImageID,
+			Status:  container.Status,
+		})
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"type": "resource",
+		"resource": map[string]interface{}{
+			"uri":      "colog://containers",
+			"mimeType": "application/json",
+			"text":     string(data),
+		},
+	}, nil
+}
+
 func (s *MCPServer) handleContainerLogsTool(id interface{}, args map[string]interface{}) MCPResponse {
 	containerID, ok := args["container_id"].(string)
 	if !ok {
@@ -792,8 +1406,30 @@ func (s *MCPServer) handleContainerLogsTool(id interface{}, args map[string]inte
 		}
 	}
 
-	// Get recent logs directly
-	logs, err := dockerService.GetRecentLogs(s.ctx, containerID, tail)
+	// since_seq (or since_timestamp) turns the call into an incremental read
+	sinceSeq := int64(-1)
+	if seq, ok := cursorArg(args, "since_seq"); ok {
+		sinceSeq = seq
+	} else if ts, ok := args["since_timestamp"].(string); ok && ts != "" {
+		since, err := time.Parse(time.RFC3339Nano, ts)
+		if err != nil {
+			return MCPResponse{
+				ID: id,
+				Error: &MCPError{
+					Code:    -32602,
+					Message: "Invalid since_timestamp, expected RFC3339: " + err.Error(),
+				},
+			}
+		}
+		sinceSeq = since.UnixNano()
+	}
+
+	var logs []LogEntry
+	if sinceSeq >= 0 {
+		logs, err = dockerService.GetLogsAfter(s.ctx, containerID, sinceSeq, tail)
+	} else {
+		logs, err = dockerService.GetRecentLogs(s.ctx, containerID, tail)
+	}
 	if err != nil {
 		return MCPResponse{
 			ID: id,
@@ -810,9 +1446,337 @@ func (s *MCPServer) handleContainerLogsTool(id interface{}, args map[string]inte
 		timestamp := log.Timestamp.Format("15:04:05")
 		logLines = append(logLines, fmt.Sprintf("[%s] %s", timestamp, log.Message))
 	}
-
-	response := fmt.Sprintf("Retrieved %d log entries from container %s:\n\n%s", 
-		len(logs), truncateContainerID(containerID), strings.Join(logLines, "\n"))
+
+	// The cursor stays put when nothing new arrived so polling can continue from it.
+	// Entries come oldest first, so it ends at the last one delivered.
+	nextSeq := sinceSeq
+	for _, log := range logs {
+		if log.Seq > nextSeq {
+			nextSeq = log.Seq
+		}
+	}
+
+	response := fmt.Sprintf("Retrieved %d log entries from container %s:\n\n%s", 
+		len(logs), truncateContainerID(containerID), strings.Join(logLines, "\n"))
+	if nextSeq >= 0 {
+		response += fmt.Sprintf("\n\nnext_seq: %d", nextSeq)
+	}
+
+	return MCPResponse{
+		ID: id,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": response,
+				},
+			},
+			"next_seq": strconv.FormatInt(nextSeq, 10),
+		},
+	}
+}
+
+func (s *MCPServer) handleExportLogsTool(id interface{}, args map[string]interface{}) MCPResponse {
+	tail := 50
+	if t, ok := args["tail"].(float64); ok {
+		tail = int(t)
+	}
+
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Docker connection failed: " + err.Error(),
+			},
+		}
+	}
+
+	containers, err := s.selectExportContainers(dockerService, args)
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to list containers: " + err.Error(),
+			},
+		}
+	}
+
+	// Generate markdown export
+	output := "# Docker Container Logs Summary\n\n"
+	output += fmt.Sprintf("Generated at: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+
+	for _, container := range containers {
+		logs, err := dockerService.GetRecentLogs(s.ctx, container.ID, tail)
+		if err != nil {
+			continue // Skip containers with log errors
+		}
+
+		if len(logs) > 0 {
+			output += fmt.Sprintf("## Container: %s\n", container.Name)
+			output += fmt.Sprintf("- Image: %s\n", container.Image)
+			output += imageDetails(s.ctx, dockerService, container)
+			output += fmt.Sprintf("- Status: %s\n\n", container.Status)
+			
+			output += "```\n"
+			output += formatExportLogs(logs)
+			output += "```\n\n"
+		}
+	}
+
+	return MCPResponse{
+		ID: id,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": output,
+				},
+			},
+		},
+	}
+}
+
+// handleSummarizeErrorsTool ranks the allowed running containers by the errors and
+// warnings in their recent logs, detecting levels like the SDK does
+func (s *MCPServer) handleSummarizeErrorsTool(id interface{}, args map[string]interface{}) MCPResponse {
+	tail := 200
+	if t, ok := args["tail"].(float64); ok {
+		tail = int(t)
+	}
+
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Docker connection failed: " + err.Error(),
+			},
+		}
+	}
+
+	containers, err := s.listAllowedContainers(dockerService)
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to list containers: " + err.Error(),
+			},
+		}
+	}
+
+	report := &sdk.LogSummary{GeneratedAt: time.Now(), Containers: []sdk.ContainerLogSummary{}}
+	matcher := docker.NewLevelMatcher()
+	for _, container := range containers {
+		info := sdk.ContainerInfo{ID: truncateContainerID(container.ID), Name: container.Name, Image: container.Image, ImageID: container.ImageID, Status: container.Status}
+		logs, err := dockerService.GetRecentLogs(s.ctx, container.ID, tail)
+		entries := make([]docker.LogEntry, 0, len(logs))
+		for _, log := range logs {
+			entries = append(entries, docker.LogEntry{ContainerID: log.ContainerID, Timestamp: log.Timestamp, Message: log.Message, Stream: log.Stream})
+		}
+		summary := sdk.SummarizeEntries(info, entries, matcher)
+		if err != nil {
+			summary.Error = err.Error()
+		}
+		report.Containers = append(report.Containers, summary)
+		report.Errors += summary.Errors
+		report.Warnings += summary.Warnings
+	}
+	report.RankByErrors()
+
+	var text strings.Builder
+	sdk.WriteErrorSummary(&text, report)
+	resource, err := errorSummaryResource(report)
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to encode summary: " + err.Error(),
+			},
+		}
+	}
+
+	return MCPResponse{
+		ID: id,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": text.String(),
+				},
+				resource,
+			},
+		},
+	}
+}
+
+// errorSummaryResource returns a resource content block carrying the ranked summary
+// as JSON, like containersResource
+func errorSummaryResource(report *sdk.LogSummary) (map[string]interface{}, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"type": "resource",
+		"resource": map[string]interface{}{
+			"uri":      "colog://errors",
+			"mimeType": "application/json",
+			"text":     string(data),
+		},
+	}, nil
+}
+
+// archiveInlineLimit is the default size up to which archives are returned inline as base64
+const archiveInlineLimit = 1 << 20
+
+func (s *MCPServer) handleExportArchiveTool(id interface{}, args map[string]interface{}) MCPResponse {
+	tail := 100
+	if t, ok := args["tail"].(float64); ok {
+		tail = int(t)
+	}
+
+	inlineLimit := archiveInlineLimit
+	if l, ok := args["max_inline_bytes"].(float64); ok {
+		inlineLimit = int(l)
+	}
+
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Docker connection failed: " + err.Error(),
+			},
+		}
+	}
+
+	containers, err := s.selectExportContainers(dockerService, args)
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to list containers: " + err.Error(),
+			},
+		}
+	}
+
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	now := time.Now()
+
+	var files []string
+	for _, container := range containers {
+		logs, err := dockerService.GetRecentLogs(s.ctx, container.ID, tail)
+		if err != nil {
+			continue // Skip containers with log errors
+		}
+
+		// Each file starts with the image it came from, for reproducing an incident
+		imageHeader := fmt.Sprintf("# %s (%s)\n- Image: %s\n%s\n", container.Name, truncateContainerID(container.ID), container.Image, imageDetails(s.ctx, dockerService, container))
+		content := []byte(imageHeader + formatExportLogs(logs))
+		name := fmt.Sprintf("%s_%s.log", container.Name, truncateContainerID(container.ID))
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: now,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return MCPResponse{
+				ID: id,
+				Error: &MCPError{
+					Code:    -32603,
+					Message: "Failed to write archive: " + err.Error(),
+				},
+			}
+		}
+		if _, err := tarWriter.Write(content); err != nil {
+			return MCPResponse{
+				ID: id,
+				Error: &MCPError{
+					Code:    -32603,
+					Message: "Failed to write archive: " + err.Error(),
+				},
+			}
+		}
+		files = append(files, fmt.Sprintf("• %s (%d lines)", name, len(logs)))
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to write archive: " + err.Error(),
+			},
+		}
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to write archive: " + err.Error(),
+			},
+		}
+	}
+
+	filename := fmt.Sprintf("colog_logs_%d.tar.gz", now.Unix())
+	summary := fmt.Sprintf("Archived logs from %d containers (%d bytes):\n\n%s", len(files), buf.Len(), strings.Join(files, "\n"))
+
+	// Small archives are returned inline, larger ones are written to disk
+	if buf.Len() <= inlineLimit {
+		return MCPResponse{
+			ID: id,
+			Result: map[string]interface{}{
+				"content": []map[string]interface{}{
+					{
+						"type": "text",
+						"text": summary,
+					},
+					{
+						"type": "resource",
+						"resource": map[string]interface{}{
+							"uri":      "colog://archives/" + filename,
+							"mimeType": "application/gzip",
+							"blob":     base64.StdEncoding.EncodeToString(buf.Bytes()),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// CreateTemp picks an unused name and makes the file readable by this user only
+	archive, err := os.CreateTemp("", "colog_logs_*.tar.gz")
+	if err == nil {
+		_, err = archive.Write(buf.Bytes())
+		if closeErr := archive.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(archive.Name())
+		}
+	}
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: "Failed to write archive: " + err.Error(),
+			},
+		}
+	}
+	archivePath := archive.Name()
 
 	return MCPResponse{
 		ID: id,
@@ -820,62 +1784,97 @@ func (s *MCPServer) handleContainerLogsTool(id interface{}, args map[string]inte
 			"content": []map[string]interface{}{
 				{
 					"type": "text",
-					"text": response,
+					"text": summary + "\n\nArchive written to " + archivePath,
+				},
+				{
+					"type": "resource",
+					"resource": map[string]interface{}{
+						"uri":      "file://" + archivePath,
+						"mimeType": "application/gzip",
+					},
 				},
 			},
 		},
 	}
 }
 
-func (s *MCPServer) handleExportLogsTool(id interface{}, args map[string]interface{}) MCPResponse {
-	tail := 50
-	if t, ok := args["tail"].(float64); ok {
-		tail = int(t)
+// selectExportContainers returns the containers named in container_ids, or all allowed running containers
+func (s *MCPServer) selectExportContainers(dockerService *DockerService, args map[string]interface{}) ([]Container, error) {
+	containers, err := s.listAllowedContainers(dockerService)
+	if err != nil {
+		return nil, err
 	}
 
-	dockerService, err := s.getDockerService()
-	if err != nil {
+	requested, _ := args["container_ids"].([]interface{})
+	if len(requested) == 0 {
+		return containers, nil
+	}
+
+	var selected []Container
+	for _, container := range containers {
+		for _, item := range requested {
+			ref, _ := item.(string)
+			if ref != "" && (ref == container.Name || strings.HasPrefix(ref, container.ID) || strings.HasPrefix(container.ID, ref)) {
+				selected = append(selected, container)
+				break
+			}
+		}
+	}
+	return selected, nil
+}
+
+// formatExportLogs renders log entries one per line with timestamps
+func formatExportLogs(logs []LogEntry) string {
+	var output strings.Builder
+	for _, log := range logs {
+		timestamp := log.Timestamp.Format("2006-01-02 15:04:05")
+		output.WriteString(fmt.Sprintf("[%s] %s\n", timestamp, log.Message))
+	}
+	return output.String()
+}
+
+// handleControlContainerTool restarts or kills the container named by container_id
+func (s *MCPServer) handleControlContainerTool(id interface{}, args map[string]interface{}, action string) MCPResponse {
+	containerID, ok := args["container_id"].(string)
+	if !ok || containerID == "" {
 		return MCPResponse{
 			ID: id,
 			Error: &MCPError{
-				Code:    -32603,
-				Message: "Docker connection failed: " + err.Error(),
+				Code:    -32602,
+				Message: "container_id is required",
 			},
 		}
 	}
 
-	containers, err := dockerService.ListRunningContainers(s.ctx)
+	dockerService, err := s.getDockerService()
 	if err != nil {
 		return MCPResponse{
 			ID: id,
 			Error: &MCPError{
 				Code:    -32603,
-				Message: "Failed to list containers: " + err.Error(),
+				Message: "Docker connection failed: " + err.Error(),
 			},
 		}
 	}
 
-	// Generate markdown export
-	output := "# Docker Container Logs Summary\n\n"
-	output += fmt.Sprintf("Generated at: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
-
-	for _, container := range containers {
-		logs, err := dockerService.GetRecentLogs(s.ctx, container.ID, tail)
-		if err != nil {
-			continue // Skip containers with log errors
-		}
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
 
-		if len(logs) > 0 {
-			output += fmt.Sprintf("## Container: %s\n", container.Name)
-			output += fmt.Sprintf("- Image: %s\n", container.Image)
-			output += fmt.Sprintf("- Status: %s\n\n", container.Status)
-			
-			output += "```\n"
-			for _, log := range logs {
-				timestamp := log.Timestamp.Format("2006-01-02 15:04:05")
-				output += fmt.Sprintf("[%s] %s\n", timestamp, log.Message)
-			}
-			output += "```\n\n"
+	var done string
+	if action == "kill" {
+		err = dockerService.KillContainer(ctx, containerID)
+		done = "Killed"
+	} else {
+		err = dockerService.RestartContainer(ctx, containerID)
+		done = "Restarted"
+	}
+	if err != nil {
+		return MCPResponse{
+			ID: id,
+			Error: &MCPError{
+				Code:    -32603,
+				Message: fmt.Sprintf("Failed to %s container %s: %v", action, containerID, err),
+			},
 		}
 	}
 
@@ -885,7 +1884,7 @@ func (s *MCPServer) handleExportLogsTool(id interface{}, args map[string]interfa
 			"content": []map[string]interface{}{
 				{
 					"type": "text",
-					"text": output,
+					"text": fmt.Sprintf("%s container %s", done, containerID),
 				},
 			},
 		},
@@ -904,7 +1903,7 @@ func (s *MCPServer) handleFilterContainersTool(id interface{}, args map[string]i
 		}
 	}
 
-	containers, err := dockerService.ListRunningContainers(s.ctx)
+	containers, err := s.listAllowedContainers(dockerService)
 	if err != nil {
 		return MCPResponse{
 			ID: id,
@@ -942,11 +1941,8 @@ func (s *MCPServer) handleFilterContainersTool(id interface{}, args map[string]i
 	// Format filtered containers for display
 	var containerList []string
 	for _, container := range filtered {
-		status := container.Status
-		if len(status) > 20 {
-			status = status[:20] + "..."
-		}
-		containerList = append(containerList, fmt.Sprintf("• %s (%s) - %s", container.Name, container.ID[:12], status))
+		status := truncate(container.Status, 23)
+		containerList = append(containerList, fmt.Sprintf("• %s (%s) - %s", container.Name, truncateContainerID(container.ID), status))
 	}
 	
 	filtersUsed := []string{}
@@ -983,7 +1979,7 @@ func (s *MCPServer) handleContainersList(req *MCPRequest) MCPResponse {
 		}
 	}
 
-	containers, err := dockerService.ListRunningContainers(s.ctx)
+	containers, err := s.listAllowedContainers(dockerService)
 	if err != nil {
 		return MCPResponse{
 			ID: req.ID,
@@ -1023,14 +2019,16 @@ func (s *MCPServer) handleContainerExport(req *MCPRequest) MCPResponse {
 }
 
 // Helper methods
+// sendSSEMessage writes message to the session's event stream. The SSE handler
+// clears SSEActive under the same mutex before it returns, so nothing writes to
+// its ResponseWriter afterwards.
 func (s *MCPServer) sendSSEMessage(session *Session, message interface{}) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
 	if !session.SSEActive {
 		return
 	}
 
-	session.mutex.Lock()
-	defer session.mutex.Unlock()
-
 	data, err := json.Marshal(message)
 	if err != nil {
 		return
@@ -1064,7 +2062,7 @@ func (s *MCPServer) authMiddleware(next http.Handler) http.Handler {
 		}
 
 		apiKey := r.Header.Get("X-API-Key")
-		if apiKey == "" {
+		if apiKey == "" && s.auth.AllowQueryKey {
 			apiKey = r.URL.Query().Get("api_key")
 		}
 
@@ -1073,24 +2071,238 @@ func (s *MCPServer) authMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Strip the key so downstream handlers never see it
+		if r.URL.Query().Has("api_key") {
+			query := r.URL.Query()
+			query.Del("api_key")
+			r = r.Clone(r.Context())
+			r.URL.RawQuery = query.Encode()
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
 
+// validateOrigins normalizes the configured CORS origins and rejects malformed entries.
+// An empty list or "*" allows any origin; otherwise each entry must be a scheme://host[:port] origin.
+func validateOrigins(origins []string) ([]string, error) {
+	var result []string
+	for _, origin := range origins {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			result = append(result, origin)
+			continue
+		}
+
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid allowed origin %q: expected scheme://host[:port]", origin)
+		}
+		if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+			return nil, fmt.Errorf("invalid allowed origin %q: origins must not include a path, query or fragment", origin)
+		}
+		result = append(result, parsed.Scheme+"://"+parsed.Host)
+	}
+
+	if len(result) == 0 {
+		return []string{"*"}, nil
+	}
+	if len(result) > 1 && allowsAnyOrigin(result) {
+		return nil, fmt.Errorf("allowed origins cannot mix \"*\" with specific origins")
+	}
+	return result, nil
+}
+
+// allowsAnyOrigin reports whether the origin list is the wildcard
+func allowsAnyOrigin(origins []string) bool {
+	for _, origin := range origins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveQueryParams lists query parameters that must never be logged in plaintext
+var sensitiveQueryParams = []string{"api_key", "apikey", "token", "access_token"}
+
+// statusRecorder captures the response status for request logging
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush keeps SSE streaming working through the logging wrapper
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack lets websocket upgrades work through the logging wrapper
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	r.status = http.StatusSwitchingProtocols
+	return hijacker.Hijack()
+}
+
+// loggingMiddleware logs each request with its MCP method, tool, duration and status.
+// LOG_LEVEL controls verbosity: debug adds the (redacted) query string and request ID,
+// info logs every request, warn only failed requests and error only server errors.
+func (s *MCPServer) loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		// Peek at the JSON-RPC body so the method and tool can be logged
+		var req MCPRequest
+		if r.Method == http.MethodPost && r.Body != nil {
+			body, err := io.ReadAll(r.Body)
+			if err == nil {
+				json.Unmarshal(body, &req)
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		level := strings.ToLower(s.logLevel)
+		switch {
+		case level == "error" && recorder.status < 500:
+			return
+		case level == "warn" && recorder.status < 400:
+			return
+		}
+
+		line := fmt.Sprintf("%s %s", r.Method, r.URL.Path)
+		if req.Method != "" {
+			line += " method=" + req.Method
+		}
+		if params, ok := req.Params.(map[string]interface{}); ok {
+			if toolName, ok := params["name"].(string); ok {
+				line += " tool=" + toolName
+			}
+		}
+		line += fmt.Sprintf(" status=%d duration=%s", recorder.status, time.Since(start).Round(time.Millisecond))
+
+		if level == "debug" {
+			if r.URL.RawQuery != "" {
+				line += " query=" + redactQuery(r.URL.Query())
+			}
+			if req.ID != nil {
+				line += fmt.Sprintf(" id=%v", req.ID)
+			}
+		}
+
+		log.Print(line)
+	})
+}
+
+// redactQuery encodes query parameters with sensitive values masked
+func redactQuery(query url.Values) string {
+	redacted := url.Values{}
+	for key, values := range query {
+		redacted[key] = values
+		for _, sensitive := range sensitiveQueryParams {
+			if strings.EqualFold(key, sensitive) {
+				redacted[key] = []string{"REDACTED"}
+				break
+			}
+		}
+	}
+	return redacted.Encode()
+}
+
 func (s *MCPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	
+	// /health needs no API key, so it reports only counters, never session IDs
+	s.sessionsMux.RLock()
+	sessions := len(s.sessions)
+	s.sessionsMux.RUnlock()
+
 	response := map[string]interface{}{
 		"status":    "healthy",
 		"timestamp": time.Now().Format(time.RFC3339),
 		"version":   "1.2.0",
-		"sessions":  len(s.sessions),
+		"sessions":  sessions,
 		"capabilities": s.getCapabilities(),
 	}
+	for key, value := range s.stats.snapshot() {
+		response[key] = value
+	}
 	
 	json.NewEncoder(w).Encode(response)
 }
 
+// record updates the counters for a handled request
+func (st *ServerStats) record(req *MCPRequest, response MCPResponse) {
+	st.mutex.Lock()
+	defer st.mutex.Unlock()
+
+	st.Requests++
+	st.LastRequest = time.Now()
+	if response.Error != nil {
+		st.Errors++
+	}
+
+	if req.Method != "tools/call" {
+		return
+	}
+	params, _ := req.Params.(map[string]interface{})
+	toolName, _ := params["name"].(string)
+	if toolName == "" {
+		return
+	}
+	st.ToolCalls[toolName]++
+	if response.Error != nil {
+		st.ToolErrors[toolName]++
+	}
+}
+
+// snapshot returns a copy of the counters suitable for JSON encoding
+func (st *ServerStats) snapshot() map[string]interface{} {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	toolCalls := make(map[string]int, len(st.ToolCalls))
+	for name, count := range st.ToolCalls {
+		toolCalls[name] = count
+	}
+	toolErrors := make(map[string]int, len(st.ToolErrors))
+	for name, count := range st.ToolErrors {
+		toolErrors[name] = count
+	}
+
+	lastRequest := ""
+	if !st.LastRequest.IsZero() {
+		lastRequest = st.LastRequest.Format(time.RFC3339)
+	}
+
+	uptime := time.Since(st.StartTime)
+	return map[string]interface{}{
+		"started_at":     st.StartTime.Format(time.RFC3339),
+		"uptime":         uptime.Round(time.Second).String(),
+		"uptime_seconds": int64(uptime.Seconds()),
+		"requests":       st.Requests,
+		"errors":         st.Errors,
+		"tool_calls":     toolCalls,
+		"tool_errors":    toolErrors,
+		"last_request":   lastRequest,
+	}
+}
+
 func (s *MCPServer) handleCapabilities(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(s.getCapabilities())
@@ -1108,7 +2320,29 @@ func (s *MCPServer) getCapabilities() map[string]interface{} {
 	}
 }
 
+// controlEnabled reports whether tools that mutate containers may run: they must be
+// allowed explicitly, and read-only mode always wins
+func (s *MCPServer) controlEnabled() bool {
+	return s.allowControl && !s.readOnly
+}
+
 func (s *MCPServer) getTools() []ToolDefinition {
+	tools := s.allTools()
+	if s.controlEnabled() {
+		return tools
+	}
+
+	// Servers without container control don't advertise destructive tools at all
+	var readOnlyTools []ToolDefinition
+	for _, tool := range tools {
+		if !mutatingTools[tool.Name] {
+			readOnlyTools = append(readOnlyTools, tool)
+		}
+	}
+	return readOnlyTools
+}
+
+func (s *MCPServer) allTools() []ToolDefinition {
 	return []ToolDefinition{
 		{
 			Name:        "list_containers",
@@ -1144,6 +2378,14 @@ func (s *MCPServer) getTools() []ToolDefinition {
 						"description": "Follow log output",
 						"default":     false,
 					},
+					"since_seq": map[string]interface{}{
+						"type":        []string{"string", "number"},
+						"description": "Only return entries newer than this cursor (the next_seq of a previous call, passed back as the string it was returned as)",
+					},
+					"since_timestamp": map[string]interface{}{
+						"type":        "string",
+						"description": "Only return entries newer than this RFC3339 timestamp",
+					},
 				},
 				"required": []string{"container_id"},
 			},
@@ -1176,6 +2418,70 @@ func (s *MCPServer) getTools() []ToolDefinition {
 				"required": []string{"container_ids"},
 			},
 		},
+		{
+			Name:        "summarize_errors",
+			Description: "Rank running containers by the errors and warnings in their recent logs, with sample error lines; a token-efficient first look at what's broken",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tail": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of recent log lines checked per container",
+						"default":     200,
+					},
+				},
+			},
+		},
+		{
+			Name:        "export_logs_archive",
+			Description: "Export per-container log files as a tar.gz archive, returned inline as base64 or written to disk when large",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"container_ids": map[string]interface{}{
+						"type": "array",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+						"description": "List of container IDs or names (default: all running)",
+					},
+					"tail": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of log lines per container",
+						"default":     100,
+					},
+					"max_inline_bytes": map[string]interface{}{
+						"type":        "number",
+						"description": "Largest archive returned inline; bigger archives are written to a file and its path returned",
+						"default":     archiveInlineLimit,
+					},
+				},
+			},
+		},
+		{
+			Name:        "stream_container_logs",
+			Description: "Follow a container's logs live (websocket connections to /mcp only): each new line arrives as a notifications/log notification with container_id, timestamp, message, stream and seq",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"container_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Container ID or name",
+					},
+					"tail": map[string]interface{}{
+						"type":        "number",
+						"description": "Number of existing lines to send before following",
+						"default":     10,
+					},
+					"stop": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Stop a stream started earlier on this connection",
+						"default":     false,
+					},
+				},
+				"required": []string{"container_id"},
+			},
+		},
 		{
 			Name:        "filter_containers",
 			Description: "Filter containers by various criteria",
@@ -1197,6 +2503,34 @@ func (s *MCPServer) getTools() []ToolDefinition {
 				},
 			},
 		},
+		{
+			Name:        "restart_container",
+			Description: "Restart a Docker container (requires COLOG_MCP_ALLOW_CONTROL=true)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"container_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Container ID or name",
+					},
+				},
+				"required": []string{"container_id"},
+			},
+		},
+		{
+			Name:        "kill_container",
+			Description: "Kill a Docker container with SIGKILL (requires COLOG_MCP_ALLOW_CONTROL=true)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"container_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Container ID or name",
+					},
+				},
+				"required": []string{"container_id"},
+			},
+		},
 	}
 }
 
@@ -1216,6 +2550,11 @@ func main() {
 		APIKey:      os.Getenv("MCP_API_KEY"),
 		RequireAuth: os.Getenv("MCP_API_KEY") != "",
 		AllowedOrigins: []string{"*"},
+		AllowQueryKey: os.Getenv("MCP_ALLOW_QUERY_KEY") == "true",
+	}
+
+	if auth.RequireAuth && auth.AllowQueryKey {
+		log.Printf("⚠️  MCP_ALLOW_QUERY_KEY is deprecated: API keys in URLs leak into proxy logs and browser history, prefer the X-API-Key header")
 	}
 
 	if origins := os.Getenv("MCP_ALLOWED_ORIGINS"); origins != "" {
@@ -1227,6 +2566,37 @@ func main() {
 		log.Fatalf("Failed to create MCP server: %v", err)
 	}
 
+	if interval := os.Getenv("MCP_STATS_INTERVAL"); interval != "" {
+		if d, err := time.ParseDuration(interval); err == nil && d >= time.Second {
+			server.statsInterval = d
+		}
+	}
+
+	server.readOnly = os.Getenv("MCP_READ_ONLY") == "true"
+	if server.readOnly {
+		log.Printf("🔒 Read-only mode: container-mutating tools are disabled")
+	}
+	server.allowControl = os.Getenv("COLOG_MCP_ALLOW_CONTROL") == "true"
+	if server.controlEnabled() {
+		log.Printf("⚠️  Container control enabled: clients can restart and kill containers")
+	}
+
+	if allowed := os.Getenv("MCP_ALLOWED_CONTAINERS"); allowed != "" {
+		for _, pattern := range strings.Split(allowed, ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				server.allowedContainers = append(server.allowedContainers, pattern)
+			}
+		}
+		log.Printf("🔒 Restricting tools to containers matching: %s", strings.Join(server.allowedContainers, ", "))
+	}
+
+	// Optional request logging, verbosity controlled by LOG_LEVEL
+	server.logRequests = os.Getenv("MCP_LOG_REQUESTS") == "true"
+	server.logLevel = os.Getenv("LOG_LEVEL")
+	if server.logLevel == "" {
+		server.logLevel = "info"
+	}
+
 	if err := server.Start(); err != nil {
 		log.Fatalf("Server failed: %v", err)
 	}
@@ -1234,8 +2604,21 @@ func main() {
 
 // Helper function to safely truncate container ID for display
 func truncateContainerID(containerID string) string {
-	if len(containerID) <= 12 {
+	if utf8.RuneCountInString(containerID) <= 12 {
 		return containerID
 	}
-	return containerID[:12]
+	return string([]rune(containerID)[:12])
+}
+
+// truncate shortens s to at most width characters, ending in "..." when cut. It
+// counts runes so multibyte text is never cut mid-character.
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width <= 3 {
+		return string(runes[:max(width, 0)])
+	}
+	return string(runes[:width-3]) + "..."
 }
\ No newline at end of file
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestAllowlistMatchesListedAndInspectedContainersAlike(t *testing.T) {
+	fullID := strings.Repeat("0123456789abcdef", 4)
+	listed := containerFromSummary(container.Summary{ID: fullID, Names: []string{"/web"}, Image: "nginx"})
+
+	for _, pattern := range []string{fullID, fullID[:12], "web", "we*"} {
+		s := &MCPServer{allowedContainers: []string{pattern}}
+		if got := s.filterAllowedContainers([]Container{listed}); len(got) != 1 {
+			t.Errorf("pattern %q hides the container from listings", pattern)
+		}
+		// checkContainerAccess matches the inspected container's full ID and name
+		if !s.isContainerAllowed(fullID, "web") {
+			t.Errorf("pattern %q denies direct access", pattern)
+		}
+	}
+
+	s := &MCPServer{allowedContainers: []string{"db", "fedcba987654"}}
+	if got := s.filterAllowedContainers([]Container{listed}); len(got) != 0 {
+		t.Errorf("listing = %v, want the container left out", got)
+	}
+	if s.isContainerAllowed(fullID, "web") {
+		t.Error("direct access allowed outside the allowlist")
+	}
+}
+
+func TestEntriesAfterKeepsTheOldestPage(t *testing.T) {
+	var logs []LogEntry
+	for _, seq := range []int64{100, 200, 300, 300, 400, 500} {
+		logs = append(logs, LogEntry{Seq: seq})
+	}
+	seqs := func(entries []LogEntry) []int64 {
+		var out []int64
+		for _, entry := range entries {
+			out = append(out, entry.Seq)
+		}
+		return out
+	}
+
+	// The cursor's own line is left out; a page ending inside a tie takes the tie
+	if got := seqs(entriesAfter(logs, 100, 2)); !reflect.DeepEqual(got, []int64{200, 300, 300}) {
+		t.Errorf("first page = %v, want [200 300 300]", got)
+	}
+	if got := seqs(entriesAfter(logs, 300, 2)); !reflect.DeepEqual(got, []int64{400, 500}) {
+		t.Errorf("next page = %v, want [400 500]", got)
+	}
+	if got := entriesAfter(logs, 500, 2); len(got) != 0 {
+		t.Errorf("after the last line = %v, want nothing", seqs(got))
+	}
+
+	// A nanosecond cursor passed back as a string survives exactly
+	cursor := int64(1760000000123456789)
+	if got, ok := cursorArg(map[string]interface{}{"since_seq": strconv.FormatInt(cursor, 10)}, "since_seq"); !ok || got != cursor {
+		t.Errorf("cursorArg = %d, %v; want %d", got, ok, cursor)
+	}
+}
+
+func TestSendSSEMessageStopsOnceTheStreamCloses(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	session := &Session{SSEWriter: recorder, SSEFlusher: recorder, SSEActive: true}
+	s := &MCPServer{}
+
+	s.sendSSEMessage(session, MCPNotification{Method: "ping"})
+	session.mutex.Lock()
+	session.SSEActive = false
+	session.mutex.Unlock()
+	s.sendSSEMessage(session, MCPNotification{Method: "notifications/stats"})
+
+	if body := recorder.Body.String(); strings.Count(body, "data: ") != 1 || strings.Contains(body, "stats") {
+		t.Errorf("stream = %q, want only the message sent while it was open", body)
+	}
+}
+
+func TestHealthDoesNotListSessions(t *testing.T) {
+	s, err := NewMCPServer("0", "localhost", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.sessions["secret-session-id"] = &Session{ID: "secret-session-id"}
+
+	recorder := httptest.NewRecorder()
+	s.handleHealth(recorder, httptest.NewRequest("GET", "/health", nil))
+	body := recorder.Body.String()
+	if strings.Contains(body, "secret-session-id") || !strings.Contains(body, `"sessions":1`) {
+		t.Errorf("/health = %s, want a session count without IDs", body)
+	}
+}
@@ -1,10 +1,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/berkantay/colog/v2/internal/app"
+	"github.com/berkantay/colog/v2/internal/config"
+	"github.com/berkantay/colog/v2/internal/docker"
 	"github.com/berkantay/colog/v2/internal/sdk"
 	"github.com/berkantay/colog/v2/internal/mcp"
 )
@@ -20,7 +26,7 @@ func main() {
 	if len(os.Args) > 1 && os.Args[1] == "sdk" {
 		if err := sdk.RunSDKCommand(os.Args[2:]); err != nil {
 			fmt.Fprintf(os.Stderr, "SDK Error: %v\n", err)
-			os.Exit(1)
+			os.Exit(sdkExitStatus(err))
 		}
 		return
 	}
@@ -44,12 +50,321 @@ func main() {
 	}
 
 	fmt.Println("Colog - Docker Container Logs Viewer")
+
+	args := os.Args[1:]
+	if name := stringArg(args, "--profile"); name != "" {
+		profile, err := config.LoadProfile(name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load profile %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		args = profile.Apply(args)
+	}
 	
 	app := app.NewApp()
+	app.SetDisplayMode(displayModeFromArgs(args))
+	if width := maxLineWidth(); width > 0 {
+		app.SetMaxLineWidth(width)
+	}
+	if limit, ok := clipboardLimit(); ok {
+		app.SetClipboardLimit(limit)
+	}
+	if size := bufferSize(args); size > 0 {
+		app.SetBufferSize(size)
+	}
+	if lines := lastLinesFromArgs(args); lines > 0 {
+		app.SetLastLines(lines)
+	}
+	if filter, ok := podFilterFromArgs(args); ok {
+		app.SetPodMode(filter)
+	}
+	if width, ok := titleWidth(args); ok {
+		app.SetTitleWidth(width)
+	}
+	if hasArg(args, "--full-ids") {
+		app.SetFullIDs(true)
+	}
+	if timeout, ok := stallTimeout(args); ok {
+		app.SetStallTimeout(timeout)
+	}
+	if hasArg(args, "--restart-stalled") {
+		app.SetRestartStalled(true)
+	}
+	if streams, ok := streamConcurrency(args); ok {
+		app.SetStreamConcurrency(streams)
+	}
+	if panes, ok := maxPanes(args); ok {
+		app.SetMaxPanes(panes)
+	}
+	if os.Getenv("COLOG_NO_COLOR") != "" {
+		app.SetNoColor(true)
+	}
+	if hasArg(args, "--no-collapse") {
+		app.SetCollapseRepeats(false)
+	}
+	if hasArg(args, "--exec-logs") {
+		app.SetExecLogFiles(true)
+	}
+	if hasArg(args, "--lazy") {
+		app.SetLazyStreaming(lazyIdleTimeout())
+	}
+	if path := stringArg(args, "--replay"); path != "" {
+		replay, err := docker.OpenReplay(path, replaySpeed(args))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load recording %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		app.SetReplay(replay)
+	}
+	if path := stringArg(args, "--import"); path != "" {
+		// Compared side by side, a recording is all there at once unless a pace is asked for
+		speed := 0.0
+		if hasArg(args, "--replay-speed") {
+			speed = replaySpeed(args)
+		}
+		imported, err := docker.OpenReplay(path, speed)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to load recording %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		app.SetImport(imported)
+	}
 	if err := app.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(reportRunError(err))
+	}
+}
+
+// exitNoContainers is the exit status when there are no running containers to show
+const exitNoContainers = 3
+
+// reportRunError prints why the viewer stopped and returns the exit status. Nothing
+// to show isn't a failure, but scripts can tell it apart by its own status.
+func reportRunError(err error) int {
+	if errors.Is(err, app.ErrNoContainers) {
+		fmt.Fprintf(os.Stderr, "Nothing to show: %v\n", err)
+		return exitNoContainers
+	}
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return 1
+}
+
+// Exit statuses of sdk commands failing for a reason scripts may want to handle
+const (
+	exitContainerNotFound = 4
+	exitDockerUnavailable = 5
+)
+
+// sdkExitStatus returns the exit status of a failed sdk command
+func sdkExitStatus(err error) int {
+	switch {
+	case errors.Is(err, sdk.ErrContainerNotFound), errors.Is(err, sdk.ErrAmbiguousID):
+		return exitContainerNotFound
+	case errors.Is(err, sdk.ErrDockerUnavailable), errors.Is(err, sdk.ErrPermissionDenied):
+		return exitDockerUnavailable
+	}
+	return 1
+}
+
+// displayModeFromArgs reads --simple/--plain/--no-tui and --tui; the last one wins
+func displayModeFromArgs(args []string) app.DisplayMode {
+	mode := app.ModeAuto
+	for _, arg := range args {
+		switch arg {
+		case "--simple", "--plain", "--no-tui":
+			mode = app.ModeSimple
+		case "--tui":
+			mode = app.ModeTUI
+		}
+	}
+	return mode
+}
+
+func hasArg(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == name {
+			return true
+		}
+	}
+	return false
+}
+
+// lastLinesFromArgs reads --last N (or --last=N); 0 when absent or invalid
+func lastLinesFromArgs(args []string) int {
+	for i, arg := range args {
+		var value string
+		switch {
+		case arg == "--last" && i+1 < len(args):
+			value = args[i+1]
+		case strings.HasPrefix(arg, "--last="):
+			value = strings.TrimPrefix(arg, "--last=")
+		default:
+			continue
+		}
+		lines, err := strconv.Atoi(value)
+		if err != nil || lines <= 0 {
+			fmt.Fprintf(os.Stderr, "Ignoring invalid --last %q\n", value)
+			return 0
+		}
+		return lines
 	}
+	return 0
+}
+
+// podFilterFromArgs reads --pods, --namespace and --pod; ok when any of them enables
+// Kubernetes pod mode
+func podFilterFromArgs(args []string) (docker.PodFilter, bool) {
+	filter := docker.PodFilter{
+		Namespace: stringArg(args, "--namespace"),
+		Pod:       stringArg(args, "--pod"),
+	}
+	enabled := hasArg(args, "--pods") || filter.Namespace != "" || filter.Pod != ""
+	return filter, enabled
+}
+
+// stringArg reads --name value (or --name=value); empty when absent
+func stringArg(args []string, name string) string {
+	for i, arg := range args {
+		if arg == name && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(arg, name+"=") {
+			return strings.TrimPrefix(arg, name+"=")
+		}
+	}
+	return ""
+}
+
+// bufferSize reads --buffer N (or --buffer=N), falling back to COLOG_BUFFER_SIZE; 0
+// when neither is set or valid
+func bufferSize(args []string) int {
+	value, source := stringArg(args, "--buffer"), "--buffer"
+	if value == "" {
+		value, source = os.Getenv("COLOG_BUFFER_SIZE"), "COLOG_BUFFER_SIZE"
+	}
+	if value == "" {
+		return 0
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size <= 0 {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid %s %q, keeping 50 lines per container\n", source, value)
+		return 0
+	}
+	return size
+}
+
+// titleWidth reads --title-width N (or --title-width=N), the container name
+// characters shown in pane titles (0 = whole names); ok when given and valid
+func titleWidth(args []string) (int, bool) {
+	value := stringArg(args, "--title-width")
+	if value == "" {
+		return 0, false
+	}
+	width, err := strconv.Atoi(value)
+	if err != nil || width < 0 {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid --title-width %q\n", value)
+		return 0, false
+	}
+	return width, true
+}
+
+// stallTimeout reads --stall-timeout D (a duration such as 90s or 5m; 0 turns stall
+// detection off); ok when given and valid
+func stallTimeout(args []string) (time.Duration, bool) {
+	value := stringArg(args, "--stall-timeout")
+	if value == "" {
+		return 0, false
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid --stall-timeout %q\n", value)
+		return 0, false
+	}
+	return timeout, true
+}
+
+// replaySpeed reads --replay-speed N (or Nx): 1 replays a recording at its recorded
+// pace (the default), 10 ten times faster, 0 all at once
+func replaySpeed(args []string) float64 {
+	value := stringArg(args, "--replay-speed")
+	if value == "" {
+		return 1
+	}
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(value, "x"), 64)
+	if err != nil || speed < 0 {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid --replay-speed %q\n", value)
+		return 1
+	}
+	return speed
+}
+
+// streamConcurrency reads --stream-concurrency N, how many log streams are opened at
+// once (0 = no limit); ok when given and valid
+func streamConcurrency(args []string) (int, bool) {
+	value := stringArg(args, "--stream-concurrency")
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid --stream-concurrency %q\n", value)
+		return 0, false
+	}
+	return n, true
+}
+
+// maxPanes reads --max-panes N (or --max-panes=N), how many containers the TUI shows
+// before asking which to watch (0 never asks); ok when given and valid
+func maxPanes(args []string) (int, bool) {
+	value := stringArg(args, "--max-panes")
+	if value == "" {
+		return 0, false
+	}
+	panes, err := strconv.Atoi(value)
+	if err != nil || panes < 0 {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid --max-panes %q\n", value)
+		return 0, false
+	}
+	return panes, true
+}
+
+// lazyIdleTimeout reads COLOG_LAZY_IDLE (a duration such as 90s or 5m), default 2m
+func lazyIdleTimeout() time.Duration {
+	if value := os.Getenv("COLOG_LAZY_IDLE"); value != "" {
+		if timeout, err := time.ParseDuration(value); err == nil && timeout > 0 {
+			return timeout
+		}
+		fmt.Fprintf(os.Stderr, "Ignoring invalid COLOG_LAZY_IDLE %q, using 2m\n", value)
+	}
+	return 2 * time.Minute
+}
+
+// maxLineWidth reads COLOG_MAX_LINE_WIDTH; 0 when unset or invalid
+func maxLineWidth() int {
+	value := os.Getenv("COLOG_MAX_LINE_WIDTH")
+	if value == "" {
+		return 0
+	}
+	width, err := strconv.Atoi(value)
+	if err != nil || width <= 0 {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid COLOG_MAX_LINE_WIDTH %q\n", value)
+		return 0
+	}
+	return width
+}
+
+// clipboardLimit reads COLOG_CLIPBOARD_LIMIT, the most bytes exports copy to the
+// clipboard (0 = no limit); ok when set and valid
+func clipboardLimit() (int, bool) {
+	value := os.Getenv("COLOG_CLIPBOARD_LIMIT")
+	if value == "" {
+		return 0, false
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 {
+		fmt.Fprintf(os.Stderr, "Ignoring invalid COLOG_CLIPBOARD_LIMIT %q\n", value)
+		return 0, false
+	}
+	return limit, true
 }
 
 func runMCPServer() error {
@@ -85,27 +400,130 @@ COMMANDS:
 
 OPTIONS:
     -h, --help     Show this help message
+    --simple       Stream plain text lines instead of the TUI, even in a terminal
+                   (aliases: --plain, --no-tui); handy for piping to a file or tee
+    --tui          Always start the TUI, skipping terminal auto-detection
+    --lazy         Only stream containers whose pane has been selected; streams of
+                   panes not selected for COLOG_LAZY_IDLE (default 2m) are stopped
+    --last N       Start in the recent activity view: each grid pane shows only its
+                   last N lines (toggle with L)
+    --buffer N     Keep the last N log lines per container for search, export (y)
+                   and AI analysis (default 50, or COLOG_BUFFER_SIZE)
+    --title-width N
+                   Show up to N characters of container names in pane titles
+                   (default 25; 0 shows whole names)
+    --full-ids     Show full container IDs in pane titles and simple mode output
+                   instead of the 12-character short form
+    --stall-timeout D
+                   Check streams silent for D (default 2m; 0 = off): when the container
+                   logged lines the stream missed, its pane shows [STALLED]
+    --restart-stalled
+                   Reopen stalled streams instead of only marking them
+    --stream-concurrency N
+                   Open at most N log streams at once at startup (default 8; 0 = no
+                   limit); the others queue so many containers don't spike the daemon
+    --max-panes N  With more than N running containers (default 12), ask at startup
+                   which ones to watch (1,3,5-8 or all); 0 never asks
+    --replay FILE  Play back a recording made with colog sdk export --format jsonl
+                   (optionally .gz) instead of watching Docker, to review an incident
+                   with search, AI and navigation; container actions are unavailable
+    --replay-speed N
+                   Replay at N times the recorded pace (default 1; 0 = all at once)
+    --import FILE  Show a recording's containers next to the live ones, as
+                   capture:NAME, to search and ask AI across both (the recording
+                   is shown all at once unless --replay-speed is given)
+    --no-collapse  Show every line; by default identical consecutive lines collapse
+                   into one with a count, e.g. "connection retry (x42)"
+    --exec-logs    Also tail log files inside containers (docker exec tail -F): the
+                   paths in a container's colog.log-files label (comma-separated) or
+                   in COLOG_LOG_FILES; off by default since it runs commands in them
+    --pods         Kubernetes pod mode: show only containers of pods (from their
+                   io.kubernetes.pod.* labels), titled pod/container and grouped by pod
+    --namespace NS Pod mode limited to namespace NS (glob patterns such as team-*)
+    --pod NAME     Pod mode limited to pods named NAME (glob patterns such as web-*)
+    --profile NAME Start with the flags and environment of profile NAME from
+                   ~/.colog/profiles.json; flags given on the command line win
+
+ENVIRONMENT:
+    COLOG_DOCKER_HOSTS  Comma-separated [label=]host list (tcp:// or unix://) to watch
+                        several Docker hosts at once; panes are titled label/name
+    COLOG_BUFFER_SIZE   Log lines kept per container when --buffer isn't given (default 50)
+    COLOG_CLIPBOARD_LIMIT
+                        Most bytes exports copy to the clipboard (default 1048576; 0 =
+                        no limit); larger exports are copied truncated, with a note
+                        pointing to the file holding the full export
+    COLOG_LOG_FILES     Comma-separated name=/path list of in-container log files to
+                        tail with --exec-logs, e.g. web=/var/log/nginx/error.log
+    COLOG_NO_COLOR      Set to any value to show log lines without level colors (and
+                        simple mode without colored container names)
+    COLOG_PROFILES      Profiles file for --profile instead of ~/.colog/profiles.json
 
 TUI CONTROLS:
     q              Quit the application
-    y              Export last 50 log lines from each container for LLM analysis
+    y              Export the buffered log lines (last 50 unless --buffer N) from each
+                   container for LLM analysis
     j/k            Navigate up/down between containers
+    1-9, 0         Jump to the 1st-9th or 10th pane (in fullscreen, show that container)
     Space          Toggle fullscreen mode for focused container
-    /              Search across all container logs (with purple highlighting)
+    /              Search across all container logs (with purple highlighting);
+                   start the query with re: for a regular expression (re:timeout|refused)
+    Up/Down        In search, AI search and chat, recall earlier queries (saved in
+                   ~/.colog/history)
+    Ctrl+E         In search mode, export the matching lines to clipboard/file
     ?              AI-powered semantic search (requires OPENAI_API_KEY)
-    C              Chat with your logs using GPT-4o (requires OPENAI_API_KEY)
+    A              AI search over the last 500 lines of each container, read from
+                   Docker, instead of the in-memory buffer (slower; token budget applies)
+    C              Chat with your logs using OPENAI_MODEL_CHAT (requires OPENAI_API_KEY)
     ESC            Exit search/AI mode
     r              Restart focused container
-    x              Kill focused container
+    x              Kill focused container (asks for confirmation)
+    e              Open a shell in the focused container (docker exec -it, sh or bash)
+    i              Inspect the focused container (c copies the JSON, ESC closes)
+    R, F5          Reload the container list (add new panes, remove stopped ones)
+    w              Toggle truncating long lines to the pane width (the focused pane
+                   shows full lines; COLOG_MAX_LINE_WIDTH=N caps the width and starts
+                   with truncation on)
+    L              Toggle the recent activity view (grid panes show only their last
+                   lines; 5 unless --last N is given)
+    v              Cycle log level threshold (ALL → WARN → ERROR) for all panes
+    V              Cycle log level threshold for the focused pane only
+    ↑/PgUp         Scroll the focused pane back (pauses following new lines)
+    End/G          Jump to the bottom and resume following new lines
+    f              Freeze/resume all panes (logs keep buffering while frozen)
+    p              Pause/resume the focused pane only (shows [PAUSED]; buffered lines
+                   are shown on resume)
+    E              Toggle a dark red background on the focused pane's stderr lines
+    o              Toggle compact list mode (Enter opens a container fullscreen)
+    s              Toggle log rate sparklines in pane titles
+    d              Toggle collapsing identical consecutive lines into one with a count
+    T              Reload the focused pane from a point in the past (15m, 2h, 14:05)
+                   and keep following; the title shows [since ...]
+    N              Attach a note to the focused container (shown in its title and exports)
     Ctrl+C         Quit the application
 
+EXIT STATUS:
+    0              Exited normally (q, Ctrl+C)
+    1              An error occurred (e.g. Docker is unreachable)
+    3              No running containers to show
+    4              colog sdk: no container matches a reference, or a short ID is ambiguous
+    5              colog sdk: Docker is unavailable or denied access
+
 AI FEATURES:
     Create a .env file with your OpenAI API key to enable AI features:
         echo "OPENAI_API_KEY=your-api-key" > .env
+
+    OPENAI_MODEL_SEARCH  Model for semantic search (default gpt-4o-mini)
+    OPENAI_MODEL_CHAT    Model for log chat (default gpt-4o)
+    OPENAI_BASE_URL      OpenAI-compatible endpoint to use instead of api.openai.com
+                         (e.g. Azure OpenAI or an Ollama gateway)
+    OPENAI_MAX_RETRIES   Retries for rate-limited (429) or failing (5xx) requests, with
+                         exponential backoff or Retry-After (default 3)
+    OPENAI_TOKEN_BUDGET  Estimated tokens of log lines per AI request (default 60000; 0 = no
+                         limit); older routine lines are left out first, errors kept
     
     Features:
     - Semantic search: Find logs by meaning, not just keywords
-    - Log analysis chat: Ask GPT-4o questions about your logs
+    - Log analysis chat: Ask the chat model questions about your logs
 
 SDK USAGE:
     colog sdk --help                           # Show SDK help
@@ -3,6 +3,7 @@ module github.com/berkantay/colog/v2
 go 1.24.1
 
 require (
+	github.com/containerd/errdefs v1.0.0
 	github.com/docker/docker v28.3.3+incompatible
 	github.com/gdamore/tcell/v2 v2.8.1
 	github.com/gorilla/mux v1.8.1
@@ -16,7 +17,6 @@ require (
 
 require (
 	github.com/Microsoft/go-winio v0.4.21 // indirect
-	github.com/containerd/errdefs v1.0.0 // indirect
 	github.com/containerd/errdefs/pkg v0.3.0 // indirect
 	github.com/containerd/log v0.1.0 // indirect
 	github.com/distribution/reference v0.6.0 // indirect
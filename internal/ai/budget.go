@@ -0,0 +1,85 @@
+package ai
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+// defaultTokenBudget is how many tokens of log lines go into a prompt unless
+// OPENAI_TOKEN_BUDGET says otherwise; it leaves room for the instructions, the chat
+// history and the answer in a 128k context
+const defaultTokenBudget = 60000
+
+// estimateTokens guesses the tokens in s with the usual four characters per token
+func estimateTokens(s string) int {
+	return (len(s) + 3) / 4
+}
+
+// entryTokens estimates the tokens of an entry as written into a prompt
+func entryTokens(entry docker.LogEntry) int {
+	return estimateTokens(fmt.Sprintf("[15:04:05] %s\n", entry.Message))
+}
+
+// fitLogs trims logs to about budget tokens (0 = no limit). Errors are kept first,
+// then warnings, then the newest other lines, so the oldest routine lines go first;
+// each container keeps its lines in order. It returns the trimmed logs and how many
+// lines were dropped.
+func fitLogs(logs map[string][]docker.LogEntry, budget int, matcher *docker.LevelMatcher) (map[string][]docker.LogEntry, int) {
+	type candidate struct {
+		container string
+		index     int
+		level     docker.LogLevel
+		tokens    int
+	}
+	var candidates []candidate
+	total := 0
+	for container, entries := range logs {
+		for i, entry := range entries {
+			tokens := entryTokens(entry)
+			total += tokens
+			candidates = append(candidates, candidate{container, i, matcher.MatchEntry(entry), tokens})
+		}
+	}
+	if budget <= 0 || total <= budget {
+		return logs, 0
+	}
+
+	// Errors, then warnings, then everything else; newest first within each
+	priority := func(level docker.LogLevel) docker.LogLevel {
+		return max(level, docker.LevelInfo)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if pa, pb := priority(a.level), priority(b.level); pa != pb {
+			return pa > pb
+		}
+		return logs[a.container][a.index].Timestamp.After(logs[b.container][b.index].Timestamp)
+	})
+
+	kept := make(map[string][]bool, len(logs))
+	for container, entries := range logs {
+		kept[container] = make([]bool, len(entries))
+	}
+	used, dropped := 0, 0
+	for _, c := range candidates {
+		if used+c.tokens > budget {
+			dropped++
+			continue
+		}
+		used += c.tokens
+		kept[c.container][c.index] = true
+	}
+
+	fitted := make(map[string][]docker.LogEntry, len(logs))
+	for container, entries := range logs {
+		fitted[container] = []docker.LogEntry{}
+		for i, entry := range entries {
+			if kept[container][i] {
+				fitted[container] = append(fitted[container], entry)
+			}
+		}
+	}
+	return fitted, dropped
+}
@@ -0,0 +1,51 @@
+package ai
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+func TestFitLogs(t *testing.T) {
+	start := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	line := func(second int, message string) docker.LogEntry {
+		return docker.LogEntry{Timestamp: start.Add(time.Duration(second) * time.Second), Message: message}
+	}
+	routine := strings.Repeat("x", 28) // 40 characters once written, 10 tokens
+	logs := map[string][]docker.LogEntry{
+		"web": {
+			line(0, routine),
+			line(1, "ERROR connection refused 123"),
+			line(2, routine),
+			line(5, routine),
+		},
+		"worker": {
+			line(3, routine),
+			line(4, "WARN retrying job 42 after t"),
+		},
+	}
+	matcher := &docker.LevelMatcher{Error: []string{"error"}, Warn: []string{"warn"}}
+
+	if fitted, dropped := fitLogs(logs, 0, matcher); dropped != 0 || len(fitted["web"]) != 4 {
+		t.Errorf("no budget dropped %d lines", dropped)
+	}
+	if _, dropped := fitLogs(logs, 60, matcher); dropped != 0 {
+		t.Errorf("logs within the budget dropped %d lines", dropped)
+	}
+
+	// Room for four lines: the error, the warning and the two newest others
+	fitted, dropped := fitLogs(logs, 40, matcher)
+	if dropped != 2 {
+		t.Errorf("dropped %d lines, want 2", dropped)
+	}
+	var seconds []int
+	for _, entry := range append(fitted["web"], fitted["worker"]...) {
+		seconds = append(seconds, entry.Timestamp.Second())
+	}
+	if fmt.Sprint(seconds) != "[1 5 3 4]" {
+		t.Errorf("kept seconds %v, want 1 and 5 (web), 3 and 4 (worker)", seconds)
+	}
+}
@@ -0,0 +1,113 @@
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	// defaultMaxRetries is how often a rate-limited or failing request is retried
+	// unless OPENAI_MAX_RETRIES says otherwise
+	defaultMaxRetries = 3
+	// firstRetryDelay doubles with each retry
+	firstRetryDelay = time.Second
+)
+
+// retryTransport retries OpenAI requests answered with 429 (rate limited) or a 5xx,
+// waiting as long as Retry-After asks or backing off exponentially. It gives up early
+// when the wait would run past the request's deadline, returning the last response.
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	sleep      func(req *http.Request, d time.Duration) error
+}
+
+func newRetryTransport(maxRetries int) *retryTransport {
+	return &retryTransport{base: http.DefaultTransport, maxRetries: maxRetries, sleep: sleepContext}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	delay := firstRetryDelay
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || !retryable(resp.StatusCode) || attempt >= t.maxRetries || req.GetBody == nil && req.Body != nil {
+			return resp, err
+		}
+
+		wait := retryAfter(resp.Header.Get("Retry-After"), delay)
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if err := t.sleep(req, wait); err != nil {
+			return nil, err
+		}
+		delay *= 2
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// retryable reports whether a response status is worth another try
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter reads a Retry-After header (seconds or an HTTP date), falling back to
+// the backoff delay when it's missing or unreadable
+func retryAfter(header string, fallback time.Duration) time.Duration {
+	if header == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return fallback
+}
+
+func sleepContext(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// describeAPIError explains errors that outlasted the retries: a request that stayed
+// rate limited or kept failing server-side says so instead of showing the raw error
+func (ai *AIService) describeAPIError(err error) error {
+	status := 0
+	var apiErr *openai.APIError
+	var reqErr *openai.RequestError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.HTTPStatusCode
+	case errors.As(err, &reqErr):
+		status = reqErr.HTTPStatusCode
+	}
+	switch {
+	case status == http.StatusTooManyRequests:
+		return fmt.Errorf("OpenAI rate limit still hit after %d retries, try again shortly: %w", ai.maxRetries, err)
+	case status >= 500:
+		return fmt.Errorf("OpenAI server error persisted after %d retries: %w", ai.maxRetries, err)
+	}
+	return fmt.Errorf("OpenAI API error: %w", err)
+}
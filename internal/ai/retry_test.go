@@ -0,0 +1,83 @@
+package ai
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	var calls int
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		buf := new(strings.Builder)
+		_, _ = io.Copy(buf, r.Body)
+		bodies = append(bodies, buf.String())
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	transport := newRetryTransport(3)
+	transport.sleep = func(_ *http.Request, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{"q":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls != 3 {
+		t.Fatalf("status %d after %d calls, want 200 after 3", resp.StatusCode, calls)
+	}
+	if len(waits) != 2 || waits[0] != 7*time.Second || waits[1] != 2*firstRetryDelay {
+		t.Errorf("waited %v, want Retry-After's 7s then the doubled backoff", waits)
+	}
+	for _, body := range bodies {
+		if body != `{"q":1}` {
+			t.Errorf("retried request body = %q", body)
+		}
+	}
+
+	// Giving up after the cap returns the last response
+	calls = 0
+	transport.maxRetries = 1
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || calls != 2 {
+		t.Errorf("status %d after %d calls, want 502 after 2", resp.StatusCode, calls)
+	}
+
+	// A wait running past the deadline isn't started
+	calls = 0
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, strings.NewReader(`{}`))
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests || calls != 1 {
+		t.Errorf("status %d after %d calls, want the 429 at once", resp.StatusCode, calls)
+	}
+}
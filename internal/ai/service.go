@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -17,6 +20,17 @@ import (
 // AIService handles OpenAI API interactions
 type AIService struct {
 	client *openai.Client
+	// Models used for semantic search and for chat
+	searchModel string
+	chatModel   string
+	// How often rate-limited (429) or failing (5xx) requests are retried
+	maxRetries int
+	// Estimated tokens of log lines sent per prompt (0 = no limit)
+	tokenBudget  int
+	levelMatcher *docker.LevelMatcher
+
+	mu    sync.RWMutex
+	notes map[string]string // user notes by container name, see SetNotes
 }
 
 // SearchResult represents a semantic search result
@@ -30,11 +44,30 @@ type SearchResult struct {
 	Message     string `json:"message,omitempty"`
 }
 
+// SearchResponse holds the results of a semantic search
+type SearchResponse struct {
+	Results []SearchResult
+	// DroppedLines is how many log lines were left out to fit the token budget
+	DroppedLines int
+}
+
 // ChatResponse represents a chat analysis response
 type ChatResponse struct {
 	Analysis    string
 	Suggestions []string
 	Summary     string
+	// DroppedLines is how many log lines were left out to fit the token budget
+	DroppedLines int
+}
+
+// maxEntries returns the most entries any container has, i.e. the buffer size in use
+// once buffers have filled up
+func maxEntries(logs map[string][]docker.LogEntry) int {
+	most := 0
+	for _, entries := range logs {
+		most = max(most, len(entries))
+	}
+	return most
 }
 
 // NewAIService creates a new AI service instance
@@ -47,24 +80,91 @@ func NewAIService() (*AIService, error) {
 		return nil, fmt.Errorf("OPENAI_API_KEY not found - create a .env file with OPENAI_API_KEY=your-key")
 	}
 
-	client := openai.NewClient(apiKey)
-	return &AIService{client: client}, nil
+	// OPENAI_BASE_URL points at any OpenAI-compatible endpoint (Azure OpenAI, an
+	// Ollama gateway, ...)
+	config := openai.DefaultConfig(apiKey)
+	if baseURL := os.Getenv("OPENAI_BASE_URL"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+
+	maxRetries := defaultMaxRetries
+	if value := os.Getenv("OPENAI_MAX_RETRIES"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			maxRetries = n
+		}
+	}
+	config.HTTPClient = &http.Client{Transport: newRetryTransport(maxRetries)}
+
+	tokenBudget := defaultTokenBudget
+	if value := os.Getenv("OPENAI_TOKEN_BUDGET"); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			tokenBudget = n
+		}
+	}
+
+	return &AIService{
+		client:       openai.NewClientWithConfig(config),
+		searchModel:  envOr("OPENAI_MODEL_SEARCH", openai.GPT4oMini),
+		chatModel:    envOr("OPENAI_MODEL_CHAT", openai.GPT4o),
+		maxRetries:   maxRetries,
+		tokenBudget:  tokenBudget,
+		levelMatcher: docker.NewLevelMatcher(),
+	}, nil
+}
+
+// SearchModel is the model semantic search uses (OPENAI_MODEL_SEARCH)
+func (ai *AIService) SearchModel() string {
+	return ai.searchModel
+}
+
+// ChatModel is the model log chat uses (OPENAI_MODEL_CHAT)
+func (ai *AIService) ChatModel() string {
+	return ai.chatModel
+}
+
+// SetNotes sets the notes the user attached to containers, keyed by container name
+// like the logs passed to searches and chats; each is sent along with its
+// container's logs
+func (ai *AIService) SetNotes(notes map[string]string) {
+	ai.mu.Lock()
+	defer ai.mu.Unlock()
+	ai.notes = notes
+}
+
+// writeNote adds the user's note on a container, if any, to a prompt's log context
+func (ai *AIService) writeNote(logContext *strings.Builder, containerName string) {
+	ai.mu.RLock()
+	note := ai.notes[containerName]
+	ai.mu.RUnlock()
+	if note != "" {
+		logContext.WriteString(fmt.Sprintf("User note: %s\n", note))
+	}
+}
+
+// envOr returns the environment variable key, or fallback when it's unset or empty
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
 }
 
 // SemanticSearch performs AI-powered semantic search across logs
-func (ai *AIService) SemanticSearch(ctx context.Context, query string, logs map[string][]docker.LogEntry) ([]SearchResult, error) {
+func (ai *AIService) SemanticSearch(ctx context.Context, query string, logs map[string][]docker.LogEntry) (*SearchResponse, error) {
 	if len(logs) == 0 {
 		return nil, fmt.Errorf("no logs provided for search")
 	}
+	logs, dropped := fitLogs(logs, ai.tokenBudget, ai.levelMatcher)
 
-	// Prepare log context for AI with all available entries (up to 50 per container)
+	// Prepare log context for AI with all available entries (already limited by the buffer size)
 	var logContext strings.Builder
 	
 	totalEntries := 0
 	for containerName, entries := range logs {
 		logContext.WriteString(fmt.Sprintf("=== CONTAINER: %s ===\n", containerName))
+		ai.writeNote(&logContext, containerName)
 		
-		// Use all available entries (up to 50 as they're already limited by buffer)
+		// Use all available entries
 		for _, entry := range entries {
 			timestamp := entry.Timestamp.Format("15:04:05")
 			logContext.WriteString(fmt.Sprintf("[%s] %s\n", timestamp, entry.Message))
@@ -106,17 +206,17 @@ Rules:
 - Maximum 10 most relevant results`
 
 	// User message contains the logs and query
-	userPrompt := fmt.Sprintf(`Container Logs (last 50 entries per container):
+	userPrompt := fmt.Sprintf(`Container Logs (last %d entries per container):
 
 %s
 
 User Query: "%s"
 
-Please analyze the above logs and find entries relevant to this query. Return only valid JSON.`, logContext.String(), query)
+Please analyze the above logs and find entries relevant to this query. Return only valid JSON.`, maxEntries(logs), logContext.String(), query)
 
 	// Call OpenAI API with proper system/user messages and structured output
 	resp, err := ai.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: openai.GPT4oMini,
+		Model: ai.searchModel,
 		Messages: []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleSystem,
@@ -133,7 +233,7 @@ Please analyze the above logs and find entries relevant to this query. Return on
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("OpenAI API error: %w", err)
+		return nil, ai.describeAPIError(err)
 	}
 
 	if len(resp.Choices) == 0 {
@@ -142,7 +242,7 @@ Please analyze the above logs and find entries relevant to this query. Return on
 
 	// Parse the response and convert to SearchResult
 	results := ai.parseSearchResponse(resp.Choices[0].Message.Content, logs)
-	return results, nil
+	return &SearchResponse{Results: results, DroppedLines: dropped}, nil
 }
 
 // SemanticSearchStream performs semantic search with streaming responses
@@ -152,15 +252,17 @@ func (ai *AIService) SemanticSearchStream(ctx context.Context, query string, log
 	if len(logs) == 0 {
 		return fmt.Errorf("no logs provided for search")
 	}
+	logs, _ = fitLogs(logs, ai.tokenBudget, ai.levelMatcher)
 
-	// Prepare log context for AI with all available entries (up to 50 per container)
+	// Prepare log context for AI with all available entries (already limited by the buffer size)
 	var logContext strings.Builder
 	
 	totalEntries := 0
 	for containerName, entries := range logs {
 		logContext.WriteString(fmt.Sprintf("=== CONTAINER: %s ===\n", containerName))
+		ai.writeNote(&logContext, containerName)
 		
-		// Use all available entries (up to 50 as they're already limited by buffer)
+		// Use all available entries
 		for _, entry := range entries {
 			timestamp := entry.Timestamp.Format("15:04:05")
 			logContext.WriteString(fmt.Sprintf("[%s] %s\n", timestamp, entry.Message))
@@ -190,7 +292,7 @@ Focus on finding entries that relate to the query's intent, not just keyword mat
 	// Note: Structured output may not work with streaming, so we'll use regular streaming
 	// and parse the response manually for now
 	req := openai.ChatCompletionRequest{
-		Model: openai.GPT4oMini,
+		Model: ai.searchModel,
 		Messages: []openai.ChatCompletionMessage{
 			{
 				Role:    openai.ChatMessageRoleUser,
@@ -209,7 +311,7 @@ Focus on finding entries that relate to the query's intent, not just keyword mat
 		if strings.Contains(err.Error(), "401") {
 			return fmt.Errorf("invalid OpenAI API key - check your .env file")
 		}
-		return fmt.Errorf("OpenAI streaming API error: %w", err)
+		return ai.describeAPIError(err)
 	}
 	defer stream.Close()
 
@@ -288,11 +390,12 @@ Focus on finding entries that relate to the query's intent, not just keyword mat
 	return nil
 }
 
-// ChatWithLogs provides conversational analysis of logs using GPT-4o
+// ChatWithLogs provides conversational analysis of logs using the chat model
 func (ai *AIService) ChatWithLogs(ctx context.Context, query string, logs map[string][]docker.LogEntry, conversationHistory []string) (*ChatResponse, error) {
 	if len(logs) == 0 {
 		return nil, fmt.Errorf("no logs provided for chat")
 	}
+	logs, dropped := fitLogs(logs, ai.tokenBudget, ai.levelMatcher)
 
 	// Prepare comprehensive log context
 	var logContext strings.Builder
@@ -300,13 +403,9 @@ func (ai *AIService) ChatWithLogs(ctx context.Context, query string, logs map[st
 	
 	for containerName, entries := range logs {
 		logContext.WriteString(fmt.Sprintf("=== %s ===\n", containerName))
-		// Include more entries for chat analysis
-		recentEntries := entries
-		if len(entries) > 50 {
-			recentEntries = entries[len(entries)-50:]
-		}
-		
-		for _, entry := range recentEntries {
+		ai.writeNote(&logContext, containerName)
+		// Entries are already limited by the buffer size
+		for _, entry := range entries {
 			timestamp := entry.Timestamp.Format("15:04:05")
 			logContext.WriteString(fmt.Sprintf("[%s] %s\n", timestamp, entry.Message))
 		}
@@ -352,16 +451,16 @@ User question: %s`, logContext.String(), query)
 		Content: currentPrompt,
 	})
 
-	// Call OpenAI API with GPT-4o for advanced analysis
+	// Call OpenAI API with the chat model for advanced analysis
 	resp, err := ai.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model:       openai.GPT4o,
+		Model:       ai.chatModel,
 		Messages:    messages,
 		MaxTokens:   2000,
 		Temperature: 0.7, // Higher temperature for more creative analysis
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("OpenAI API error: %w", err)
+		return nil, ai.describeAPIError(err)
 	}
 
 	if len(resp.Choices) == 0 {
@@ -371,9 +470,10 @@ User question: %s`, logContext.String(), query)
 	analysis := resp.Choices[0].Message.Content
 
 	return &ChatResponse{
-		Analysis:    analysis,
-		Suggestions: ai.extractSuggestions(analysis),
-		Summary:     ai.extractSummary(analysis),
+		Analysis:     analysis,
+		Suggestions:  ai.extractSuggestions(analysis),
+		Summary:      ai.extractSummary(analysis),
+		DroppedLines: dropped,
 	}, nil
 }
 
@@ -2,14 +2,18 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"os/signal"
+	"regexp"
 	"strings"
 	"syscall"
 	"time"
 
+	containertypes "github.com/docker/docker/api/types/container"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 
@@ -18,8 +22,24 @@ import (
 	"github.com/berkantay/colog/v2/internal/ai"
 )
 
+// ErrNoContainers is returned by Run when there are no running containers to show
+var ErrNoContainers = errors.New("no running containers found")
+
+// DisplayMode selects between the TUI and plain streaming output
+type DisplayMode int
+
+const (
+	// ModeAuto uses the TUI when a usable terminal is available
+	ModeAuto DisplayMode = iota
+	// ModeSimple always streams plain text lines, even in a terminal
+	ModeSimple
+	// ModeTUI always starts the TUI, skipping terminal detection
+	ModeTUI
+)
+
 type App struct {
 	app           *tview.Application
+	pages         *tview.Pages // root: the main layout plus dialogs on top
 	grid          *tview.Grid
 	mainGrid      *tview.Grid
 	helpBar       *tview.TextView
@@ -35,16 +55,111 @@ type App struct {
 	// Search modes
 	searchMode       bool               // whether we're in literal search mode
 	aiSearchMode     bool               // whether we're in AI semantic search mode
+	aiDeepSearch     bool               // whether AI search reads aiHistoryLines per container from Docker
 	chatMode         bool               // whether we're in AI chat mode
 	searchInput      *tview.InputField  // search input field
 	searchResults    *tview.TextView    // search results display
 	chatHistory      []string           // chat conversation history
+	history          *searchHistory     // recent queries, cycled with Up/Down
 	
 	// AI service
 	aiService        *ai.AIService      // AI service for semantic search and chat
 	
 	// Help section for status messages
 	helpText      string
+	
+	// Display level threshold applied to all panes
+	levelThreshold docker.LogLevel
+
+	// Whether all panes are frozen (display held, logs still buffered)
+	frozen bool
+
+	// Number of pane columns in the grid, recomputed when the terminal is resized
+	gridColumns int
+
+	// Matches of the last literal search, kept for export
+	searchTerm    string
+	searchMatches []searchMatch
+
+	// Per-pane log rate sparklines, refreshed while enabled
+	sparklineCancel context.CancelFunc
+
+	// Display mode chosen on the command line
+	displayMode DisplayMode
+
+	// Long line truncation in unselected panes (maxLineWidth 0 = fit the pane)
+	truncateLines bool
+	maxLineWidth  int
+
+	// Recent activity view: grid panes show only their last lastLines lines
+	recentView bool
+	lastLines  int
+
+	// Kubernetes pod mode: only pod containers matching podFilter, named after their pod
+	podMode   bool
+	podFilter docker.PodFilter
+
+	// Whether configured in-container log files are tailed through docker exec
+	execLogFiles bool
+
+	// Whether container IDs are shown in full rather than shortened to 12 characters
+	fullIDs bool
+
+	// Whether identical consecutive lines collapse into one line with a count ('d')
+	collapseRepeats bool
+
+	// Whether log lines are shown without level colors (COLOG_NO_COLOR)
+	noColor bool
+
+	// Most bytes copied to the clipboard (0 = no limit); exports are cut beyond it
+	clipboardLimit int
+
+	// Containers the TUI takes before asking which to watch (0 = no limit), and the
+	// IDs picked then; reloads only show picked containers
+	maxPanes int
+	watchIDs map[string]bool
+
+	// Lazy streaming: only selected panes stream; streams idle this long are stopped (0 = off)
+	lazyIdleTimeout time.Duration
+
+	// Streams silent this long are checked for a stall (0 = off), and restarted if set
+	stallTimeout   time.Duration
+	restartStalled bool
+
+	// Where containers and their logs come from: dockerService, or a recording being
+	// played back (SetReplay)
+	source docker.LogSource
+	// A recording shown next to the live containers (SetImport)
+	imported *docker.Replay
+
+	// Whether a confirmation dialog or a prompt is open
+	confirmMode bool
+	promptMode  bool
+
+	// Inspect panel for the focused container
+	inspectMode bool
+	inspectView *tview.TextView
+	inspectJSON string
+
+	// Compact list mode: one status line per container instead of the pane grid
+	listMode   bool
+	listTable  *tview.Table
+	listCancel context.CancelFunc
+}
+
+const (
+	// minPaneWidth is the narrowest a pane column may get before the grid drops a column
+	minPaneWidth = 80
+	// minPaneHeight keeps panes readable; rows that don't fit scroll with the focused pane
+	minPaneHeight = 12
+	// defaultLastLines is the recent activity clamp when --last isn't given
+	defaultLastLines = 5
+)
+
+// searchMatch holds the lines of one container that matched a search
+type searchMatch struct {
+	Container docker.Container
+	Entries   []docker.LogEntry
 }
 
 func NewApp() *App {
@@ -52,6 +167,7 @@ func NewApp() *App {
 	
 	return &App{
 		app:           tview.NewApplication(),
+		pages:         tview.NewPages(),
 		grid:          tview.NewGrid(),
 		mainGrid:      tview.NewGrid(),
 		helpBar:       tview.NewTextView(),
@@ -60,16 +176,176 @@ func NewApp() *App {
 		cancel:        cancel,
 		selectedContainer: 0,
 		helpText:      "",
+		levelThreshold: docker.LevelDebug,
+		gridColumns:   1,
+		lastLines:     defaultLastLines,
+		history:       loadSearchHistory(),
+		collapseRepeats: true,
+		clipboardLimit: defaultClipboardLimit,
+		maxPanes:      defaultMaxPanes,
+		stallTimeout:  defaultStallTimeout,
+	}
+}
+
+// SetDisplayMode overrides TTY auto-detection. Must be called before Run.
+func (a *App) SetDisplayMode(mode DisplayMode) {
+	a.displayMode = mode
+}
+
+// SetLazyStreaming starts streams only when a pane is selected and stops streams of
+// panes that haven't been selected for idleTimeout. Must be called before Run.
+func (a *App) SetLazyStreaming(idleTimeout time.Duration) {
+	a.lazyIdleTimeout = idleTimeout
+}
+
+// SetLastLines starts in the recent activity view, where each grid pane shows only
+// its last n lines. Must be called before Run.
+func (a *App) SetLastLines(n int) {
+	a.recentView = n > 0
+	if n > 0 {
+		a.lastLines = n
+	}
+}
+
+// SetBufferSize sets how many recent log entries each container keeps for search,
+// export and AI analysis. Must be called before Run.
+func (a *App) SetBufferSize(size int) {
+	a.contextManager.SetBufferSize(size)
+}
+
+// SetTitleWidth sets how many characters of container names pane titles show (0
+// shows whole names). Must be called before Run.
+func (a *App) SetTitleWidth(width int) {
+	a.contextManager.SetTitleWidth(width)
+}
+
+// SetFullIDs shows full container IDs instead of the 12-character short form, in
+// pane titles and simple mode. Must be called before Run.
+func (a *App) SetFullIDs(enabled bool) {
+	a.fullIDs = enabled
+	a.contextManager.SetFullIDs(enabled)
+}
+
+// SetNoColor shows log lines without their level colors, and simple mode output
+// without colored container names. Must be called before Run.
+func (a *App) SetNoColor(enabled bool) {
+	a.noColor = enabled
+	a.contextManager.SetNoColor(enabled)
+}
+
+// SetCollapseRepeats turns collapsing identical consecutive lines into one line with
+// an "(x42)" count on (the default) or off
+func (a *App) SetCollapseRepeats(enabled bool) {
+	a.collapseRepeats = enabled
+	a.contextManager.SetCollapseRepeats(enabled)
+}
+
+// SetClipboardLimit caps how many bytes exports copy to the clipboard (0 removes
+// the cap). Larger exports are copied truncated; their file always has everything.
+func (a *App) SetClipboardLimit(bytes int) {
+	a.clipboardLimit = bytes
+}
+
+// SetStallTimeout sets how long a stream may deliver nothing before colog checks
+// whether the container logged lines the stream missed (default 2m; 0 turns the check
+// off). Stalled panes show [STALLED]. Must be called before Run.
+func (a *App) SetStallTimeout(timeout time.Duration) {
+	a.stallTimeout = timeout
+}
+
+// SetRestartStalled reopens stalled streams instead of only marking them. Must be
+// called before Run.
+func (a *App) SetRestartStalled(enabled bool) {
+	a.restartStalled = enabled
+}
+
+// SetStreamConcurrency sets how many container log streams are opened at once (0 =
+// no limit); the rest queue and start as earlier ones are established. Must be called
+// before Run.
+func (a *App) SetStreamConcurrency(n int) {
+	a.contextManager.SetStreamStartLimit(n)
+}
+
+// SetReplay shows a recorded export played back by replay instead of the running
+// containers; Docker isn't contacted, so actions on containers are unavailable.
+// Must be called before Run.
+func (a *App) SetReplay(replay *docker.Replay) {
+	a.source = replay
+}
+
+// SetImport shows the containers of a recording next to the live ones, labeled
+// capture:NAME, so what's happening now can be searched and compared alongside a
+// captured incident. It has no effect with SetReplay. Must be called before Run.
+func (a *App) SetImport(imported *docker.Replay) {
+	a.imported = imported
+}
+
+// importOrigin labels the containers of an imported recording (see SetImport)
+const importOrigin = "capture"
+
+// SetMaxPanes sets how many containers the TUI shows before asking at startup which
+// ones to watch (0 never asks). Must be called before Run.
+func (a *App) SetMaxPanes(n int) {
+	a.maxPanes = n
+}
+
+// SetExecLogFiles tails the in-container log files configured per container
+// (colog.log-files label or COLOG_LOG_FILES) through docker exec and merges them
+// into the panes. Must be called before Run.
+func (a *App) SetExecLogFiles(enabled bool) {
+	a.execLogFiles = enabled
+}
+
+// SetMaxLineWidth starts with long lines truncated to at most width columns (and to
+// the pane width). Must be called before Run.
+func (a *App) SetMaxLineWidth(width int) {
+	a.truncateLines = width > 0
+	a.maxLineWidth = width
+}
+
+// SetPodMode shows only the containers of Kubernetes pods matching filter, titled
+// "pod/container" and grouped by namespace and pod. Must be called before Run.
+func (a *App) SetPodMode(filter docker.PodFilter) {
+	a.podMode = true
+	a.podFilter = filter
+}
+
+// listRunningContainers lists the running containers to show, applying pod mode
+func (a *App) listRunningContainers(ctx context.Context) ([]docker.Container, error) {
+	containers, err := a.source.List(ctx)
+	if err != nil {
+		return nil, err
 	}
+	if a.podMode {
+		containers = docker.PodContainers(containers, a.podFilter)
+	}
+	if a.watchIDs != nil {
+		var watched []docker.Container
+		for _, container := range containers {
+			if a.watchIDs[container.ID] {
+				watched = append(watched, container)
+			}
+		}
+		containers = watched
+	}
+	return containers, nil
 }
 
 func (a *App) Run() error {
 	var err error
-	a.dockerService, err = docker.NewDockerService()
-	if err != nil {
-		return fmt.Errorf("failed to connect to Docker: %w", err)
+	if a.source == nil {
+		a.dockerService, err = docker.NewDockerService()
+		if err != nil {
+			return fmt.Errorf("failed to connect to Docker: %w", err)
+		}
+		defer a.dockerService.Close()
+		a.source = a.dockerService
+		if a.imported != nil {
+			sources := docker.NewMultiSource(a.dockerService)
+			sources.Add(importOrigin, a.imported)
+			a.source = sources
+		}
 	}
-	defer a.dockerService.Close()
 
 	// Initialize AI service (optional - will show message if API key not set)
 	a.aiService, err = ai.NewAIService()
@@ -78,18 +354,44 @@ func (a *App) Run() error {
 		fmt.Println("Create a .env file with: OPENAI_API_KEY=your-openai-api-key")
 	}
 
-	containers, err := a.dockerService.ListRunningContainers(a.ctx)
+	containers, err := a.listRunningContainers(a.ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list containers: %w", err)
 	}
 
 	if len(containers) == 0 {
-		return fmt.Errorf("no running containers found")
+		if a.podMode {
+			return fmt.Errorf("%w (pod mode: are io.kubernetes.pod.* labels set?)", ErrNoContainers)
+		}
+		return ErrNoContainers
+	}
+
+	// More containers than the grid fits: ask which ones to watch when the TUI will run
+	if a.maxPanes > 0 && len(containers) > a.maxPanes && a.displayMode != ModeSimple && isTTY() {
+		containers = selectContainers(containers, a.maxPanes, os.Stdin, os.Stdout)
+		a.watchIDs = make(map[string]bool, len(containers))
+		for _, container := range containers {
+			a.watchIDs[container.ID] = true
+		}
 	}
 
-	if err := a.contextManager.InitializeContexts(containers, a.dockerService, a.app); err != nil {
+	// Simple mode prints every stream, so it never starts lazily
+	a.contextManager.SetLazy(a.lazyIdleTimeout > 0 && a.displayMode != ModeSimple)
+	a.contextManager.SetLogFiles(a.execLogFiles && a.dockerService != nil)
+	a.contextManager.SetReconnect(a.dockerService != nil && a.displayMode != ModeSimple)
+	if err := a.contextManager.InitializeContexts(containers, a.source, a.app); err != nil {
 		return fmt.Errorf("failed to initialize container contexts: %w", err)
 	}
+	if a.truncateLines {
+		for _, context := range a.contextManager.GetAllContexts() {
+			context.SetTruncation(true, a.maxLineWidth)
+		}
+	}
+	a.applyLastLines()
+
+	if a.displayMode == ModeSimple {
+		return a.runSimpleMode()
+	}
 
 	if err := a.setupUI(); err != nil {
 		return err
@@ -100,15 +402,37 @@ func (a *App) Run() error {
 	a.setupMainLayout()
 	a.setupKeyBindings()
 
-	// Check if we have a proper TTY before starting the TUI
-	if !isTTY() {
+	// Check if we have a proper TTY before starting the TUI (unless forced with --tui)
+	if a.displayMode == ModeAuto && !isTTY() {
 		fmt.Println("\nTTY not available, falling back to simple log output mode...")
 		return a.runSimpleMode()
 	}
 
+	// A TTY can still lack what tcell needs (terminfo entry, capabilities), which
+	// would make Run fail with a cryptic error - explain and fall back instead
+	if err := probeTerminal(); a.displayMode == ModeAuto && err != nil {
+		fmt.Printf("\nTerminal UI unavailable (%v), falling back to simple log output mode...\n", err)
+		fmt.Println("Tip: make sure TERM is set to a terminal type this system knows (e.g. TERM=xterm-256color).")
+		return a.runSimpleMode()
+	}
+
 	defer a.contextManager.Cleanup()
 	
-	if err := a.app.SetRoot(a.mainGrid, true).Run(); err != nil {
+	a.app.SetBeforeDrawFunc(a.handleResize)
+	a.app.SetAfterDrawFunc(a.checkPaneWidths)
+	if a.lazyIdleTimeout > 0 {
+		go a.stopIdleStreams()
+	}
+	// A replay has neither health nor stalls to watch
+	if a.dockerService != nil {
+		go a.pollHealth()
+		if a.stallTimeout > 0 {
+			go a.watchStalls()
+		}
+	}
+
+	a.pages.AddPage("main", a.mainGrid, true, true)
+	if err := a.app.SetRoot(a.pages, true).Run(); err != nil {
 		return fmt.Errorf("failed to run TUI application: %w", err)
 	}
 	return nil
@@ -144,27 +468,70 @@ func (a *App) setupGrid() {
 		return
 	}
 
-	a.grid.Clear()
+	a.layoutGrid(a.gridColumns)
+	
+	// Set initial focus
+	a.focusContainer(a.selectedContainer)
+}
 
-	// Create row-based list layout - all containers in a single column
-	rowSizes := make([]int, containerCount)
-	for i := range rowSizes {
-		rowSizes[i] = 0 // Equal height for all rows
+// layoutGrid places the panes in a grid with the given number of columns and
+// equal-sized rows. It doesn't touch focus so it is safe to call while drawing.
+func (a *App) layoutGrid(columns int) {
+	containerCount := a.contextManager.Count()
+	if containerCount == 0 {
+		a.grid.Clear()
+		return
+	}
+	if columns < 1 {
+		columns = 1
 	}
+	if columns > containerCount {
+		columns = containerCount
+	}
+	a.gridColumns = columns
+
+	a.grid.Clear()
 
-	a.grid.SetRows(rowSizes...).SetColumns(0) // Single column
+	rows := (containerCount + columns - 1) / columns
+	a.grid.SetRows(make([]int, rows)...).SetColumns(make([]int, columns)...) // 0 = equal share
+	a.grid.SetMinSize(minPaneHeight, 0)
 
 	contexts := a.contextManager.GetAllContexts()
 	for i, context := range contexts {
-		a.grid.AddItem(context.LogView, i, 0, 1, 1, 0, 0, i == 0)
+		a.grid.AddItem(context.LogView, i/columns, i%columns, 1, 1, 0, 0, i == a.selectedContainer)
 	}
-	
-	// Set initial focus
-	if containerCount > 0 {
-		a.focusContainer(0)
+}
+
+// columnsForWidth returns how many pane columns fit a terminal of the given width
+func columnsForWidth(width, containerCount int) int {
+	columns := width / minPaneWidth
+	if columns < 1 {
+		columns = 1
+	}
+	if columns > containerCount {
+		columns = containerCount
+	}
+	return columns
+}
+
+// checkPaneWidths lets truncating panes re-render after a layout change. Used as the
+// application's after-draw hook.
+func (a *App) checkPaneWidths(screen tcell.Screen) {
+	for _, context := range a.contextManager.GetAllContexts() {
+		context.CheckWidth()
 	}
 }
 
+// handleResize recomputes the grid when the terminal width calls for a different
+// number of columns. Used as the application's before-draw hook.
+func (a *App) handleResize(screen tcell.Screen) bool {
+	width, _ := screen.Size()
+	if columns := columnsForWidth(width, a.contextManager.Count()); columns != a.gridColumns {
+		a.layoutGrid(columns)
+	}
+	return false
+}
+
 func (a *App) setupHelpBar() {
 	trueBlack := tcell.NewRGBColor(0, 0, 0)
 	a.helpBar.SetDynamicColors(true).
@@ -180,18 +547,26 @@ func (a *App) setupHelpBar() {
 
 func (a *App) updateHelpBar() {
 	var baseText string
-	if a.searchMode {
-		baseText = "[#FF8C00]ESC[white]: Exit search  [#FF8C00]Type[white]: Search across all logs"
+	if a.inspectMode {
+		baseText = "[#FF8C00]↑/↓[white]: Scroll  [#FF8C00]c[white]: Copy JSON  [#FF8C00]i/ESC[white]: Close inspect"
+	} else if a.listMode {
+		baseText = "[#FF8C00]j/k[white]: Select container  [#FF8C00]Enter[white]: Open fullscreen  [#FF8C00]o/ESC[white]: Back to panes  [#FF8C00]q[white]: Quit"
+	} else if a.searchMode {
+		baseText = "[#FF8C00]ESC[white]: Exit search  [#FF8C00]Type[white]: Search across all logs  [#FF8C00]Ctrl+E[white]: Export matches"
 	} else if a.aiSearchMode {
-		baseText = "[#FF8C00]ESC[white]: Exit AI search  [#FF8C00]Type[white]: AI semantic search (powered by GPT-4o-mini)"
+		baseText = "[#FF8C00]ESC[white]: Exit AI search  [#FF8C00]Type[white]: AI semantic search (powered by " + tview.Escape(a.aiModel(false)) + ")"
 	} else if a.chatMode {
-		baseText = "[#FF8C00]ESC[white]: Exit chat  [#FF8C00]Type[white]: Chat with your logs (powered by GPT-4o)"
+		baseText = "[#FF8C00]ESC[white]: Exit chat  [#FF8C00]Type[white]: Chat with your logs (powered by " + tview.Escape(a.aiModel(true)) + ")"
 	} else {
 		aiHint := ""
 		if a.aiService != nil {
 			aiHint = "  [#FF8C00]?[white]: AI search  [#FF8C00]C[white]: AI chat"
 		}
-		baseText = "[#FF8C00]hjkl[white]: Navigate containers  [#FF8C00]Space[white]: Toggle fullscreen  [#FF8C00]/[white]: Search logs" + aiHint + "  [#FF8C00]y[white]: Export logs for LLM  [#FF8C00]q[white]: Quit  [#FF8C00]Ctrl+C[white]: Quit"
+		baseText = "[#FF8C00]hjkl[white]: Navigate containers  [#FF8C00]Space[white]: Toggle fullscreen  [#FF8C00]/[white]: Search logs" + aiHint + "  [#FF8C00]y[white]: Export logs for LLM  [#FF8C00]v[white]: Level " + levelThresholdName(a.levelThreshold) + "  [#FF8C00]q[white]: Quit  [#FF8C00]Ctrl+C[white]: Quit"
+	}
+	
+	if a.frozen {
+		baseText = "[black:red] FROZEN [white:#000000] [#FF8C00]f[white]: Resume  " + baseText
 	}
 	
 	if a.helpText != "" {
@@ -224,16 +599,65 @@ func (a *App) setupKeyBindings() {
 				a.cancel()
 				a.app.Stop()
 				return nil
+			case tcell.KeyCtrlE:
+				if a.searchMode {
+					a.exportSearchResults()
+					return nil
+				}
 			}
 			// Pass all other events to the focused component (search input)
 			return event
 		}
+
+		// A confirmation dialog or prompt handles its own keys (Tab/arrows, Enter, ESC)
+		if a.confirmMode || a.promptMode {
+			if event.Key() == tcell.KeyCtrlC {
+				a.cancel()
+				a.app.Stop()
+				return nil
+			}
+			return event
+		}
+
+		// In the inspect panel the text view handles scrolling
+		if a.inspectMode {
+			switch {
+			case event.Key() == tcell.KeyCtrlC:
+				a.cancel()
+				a.app.Stop()
+				return nil
+			case event.Key() == tcell.KeyEscape, event.Key() == tcell.KeyRune && (event.Rune() == 'i' || event.Rune() == 'q'):
+				a.closeInspectPanel()
+				return nil
+			case event.Key() == tcell.KeyRune && event.Rune() == 'c':
+				go a.shareOutput(a.inspectJSON, "colog_inspect", "Inspect JSON")
+				return nil
+			}
+			return event
+		}
+
+		// In list mode the table handles navigation (j/k, arrows, Enter)
+		if a.listMode {
+			switch {
+			case event.Key() == tcell.KeyCtrlC, event.Key() == tcell.KeyRune && (event.Rune() == 'q' || event.Rune() == 'Q'):
+				a.cancel()
+				a.app.Stop()
+				return nil
+			case event.Key() == tcell.KeyEscape, event.Key() == tcell.KeyRune && event.Rune() == 'o':
+				a.toggleListMode()
+				return nil
+			}
+			return event
+		}
 		
 		switch event.Key() {
 		case tcell.KeyCtrlC:
 			a.cancel()
 			a.app.Stop()
 			return nil
+		case tcell.KeyF5:
+			a.reloadContainers()
+			return nil
 		case tcell.KeyRune:
 			switch event.Rune() {
 			case 'q', 'Q':
@@ -268,11 +692,64 @@ func (a *App) setupKeyBindings() {
 				a.toggleSearchMode()
 				return nil
 			case '?':
+				a.aiDeepSearch = false
+				a.toggleAISearchMode()
+				return nil
+			case 'A':
+				a.aiDeepSearch = true
 				a.toggleAISearchMode()
 				return nil
 			case 'C':
 				a.toggleChatMode()
 				return nil
+			case 'v':
+				a.cycleLevelThreshold()
+				return nil
+			case 'V':
+				a.cycleFocusedLevelThreshold()
+				return nil
+			case 'f':
+				a.toggleFreeze()
+				return nil
+			case 'p':
+				a.togglePauseFocused()
+				return nil
+			case 'E':
+				a.toggleMarkStderrFocused()
+				return nil
+			case 'o':
+				a.toggleListMode()
+				return nil
+			case 's':
+				a.toggleSparklines()
+				return nil
+			case 'e':
+				a.execShellInFocusedContainer()
+				return nil
+			case 'i':
+				a.openInspectPanel()
+				return nil
+			case 'R':
+				a.reloadContainers()
+				return nil
+			case 'w':
+				a.toggleTruncation()
+				return nil
+			case 'd':
+				a.toggleCollapseRepeats()
+				return nil
+			case 'L':
+				a.toggleRecentView()
+				return nil
+			case 'T':
+				a.followFocusedSince()
+				return nil
+			case 'N':
+				a.annotateFocused()
+				return nil
+			case '1', '2', '3', '4', '5', '6', '7', '8', '9', '0':
+				a.jumpToContainer(digitPane(event.Rune()))
+				return nil
 			}
 		}
 		return event
@@ -280,11 +757,20 @@ func (a *App) setupKeyBindings() {
 }
 
 func (a *App) navigateLeft() {
-	// In row list layout, left navigation is not applicable
+	// Only applicable when the grid has more than one column
+	if a.selectedContainer%a.gridColumns > 0 {
+		a.selectedContainer--
+		a.focusContainer(a.selectedContainer)
+	}
 }
 
 func (a *App) navigateRight() {
-	// In row list layout, right navigation is not applicable
+	// Only applicable when the grid has more than one column
+	containerCount := a.contextManager.Count()
+	if a.selectedContainer%a.gridColumns < a.gridColumns-1 && a.selectedContainer < containerCount-1 {
+		a.selectedContainer++
+		a.focusContainer(a.selectedContainer)
+	}
 }
 
 func (a *App) navigateUp() {
@@ -293,8 +779,8 @@ func (a *App) navigateUp() {
 		return
 	}
 	
-	if a.selectedContainer > 0 {
-		a.selectedContainer--
+	if a.selectedContainer >= a.gridColumns {
+		a.selectedContainer -= a.gridColumns
 		a.focusContainer(a.selectedContainer)
 	}
 }
@@ -305,13 +791,38 @@ func (a *App) navigateDown() {
 		return
 	}
 	
-	if a.selectedContainer < containerCount-1 {
-		a.selectedContainer++
+	if a.selectedContainer+a.gridColumns < containerCount {
+		a.selectedContainer += a.gridColumns
 		a.focusContainer(a.selectedContainer)
 	}
 }
 
 
+// digitPane maps the number keys to pane indexes like tmux: 1-9 are the first nine
+// panes and 0 the tenth
+func digitPane(key rune) int {
+	if key == '0' {
+		return 9
+	}
+	return int(key - '1')
+}
+
+// jumpToContainer focuses the pane at index, in GetAllContexts order; in fullscreen
+// that container takes over the screen instead
+func (a *App) jumpToContainer(index int) {
+	if index >= a.contextManager.Count() {
+		a.showHelpMessage(fmt.Sprintf("[red]No pane %d[white]", index+1), 2*time.Second)
+		return
+	}
+	a.selectedContainer = index
+	if a.isFullscreen {
+		// Only the fullscreen pane shows all its lines in the recent activity view
+		a.applyLastLines()
+		a.showFullscreen()
+	}
+	a.focusContainer(index)
+}
+
 func (a *App) focusContainer(index int) {
 	containerCount := a.contextManager.Count()
 	if index < 0 || index >= containerCount {
@@ -329,6 +840,14 @@ func (a *App) focusContainer(index int) {
 	if selectedContext != nil && selectedContext.LogView != nil {
 		a.app.SetFocus(selectedContext.LogView)
 	}
+
+	// In lazy mode, selecting a pane starts (or resumes) its stream
+	if selectedContext != nil && a.lazyIdleTimeout > 0 {
+		selectedContext.MarkFocused()
+		if err := selectedContext.StartStreaming(); err != nil {
+			selectedContext.AppendLog(fmt.Sprintf("[red]Error starting stream: %v[white]", err))
+		}
+	}
 }
 
 func (a *App) toggleFullscreen() {
@@ -337,17 +856,10 @@ func (a *App) toggleFullscreen() {
 	}
 	
 	a.isFullscreen = !a.isFullscreen
+	a.applyLastLines()
 	
 	if a.isFullscreen {
-		// Enter fullscreen mode - show only the selected container
-		a.mainGrid.Clear()
-		selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
-		if selectedContext != nil && selectedContext.LogView != nil {
-			a.mainGrid.SetRows(0, 3).
-				SetColumns(0).
-				AddItem(selectedContext.LogView, 0, 0, 1, 1, 0, 0, true).
-				AddItem(a.helpBar, 1, 0, 1, 1, 0, 0, false)
-		}
+		a.showFullscreen()
 	} else {
 		// Exit fullscreen mode - restore grid layout
 		a.mainGrid.Clear()
@@ -362,6 +874,271 @@ func (a *App) toggleFullscreen() {
 }
 
 
+// showFullscreen lays out the selected container alone above the help bar
+func (a *App) showFullscreen() {
+	a.mainGrid.Clear()
+	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
+	if selectedContext != nil && selectedContext.LogView != nil {
+		a.mainGrid.SetRows(0, 3).
+			SetColumns(0).
+			AddItem(selectedContext.LogView, 0, 0, 1, 1, 0, 0, true).
+			AddItem(a.helpBar, 1, 0, 1, 1, 0, 0, false)
+	}
+}
+
+// cycleLevelThreshold steps the display threshold ALL → WARN → ERROR for every pane,
+// replacing any per-pane thresholds
+func (a *App) cycleLevelThreshold() {
+	a.levelThreshold = nextLevelThreshold(a.levelThreshold)
+
+	for _, context := range a.contextManager.GetAllContexts() {
+		context.SetMinLevel(a.levelThreshold)
+	}
+	a.updateHelpBar()
+}
+
+// cycleFocusedLevelThreshold steps the display threshold of the focused pane only
+func (a *App) cycleFocusedLevelThreshold() {
+	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
+	if selectedContext == nil {
+		return
+	}
+
+	level := nextLevelThreshold(selectedContext.MinLevel())
+	selectedContext.SetMinLevel(level)
+	a.showHelpMessage(fmt.Sprintf("[#FF8C00]%s: level %s[white]", tview.Escape(selectedContext.Container.DisplayName()), levelThresholdName(level)), 2*time.Second)
+}
+
+// nextLevelThreshold returns the threshold after level in the ALL → WARN → ERROR cycle
+func nextLevelThreshold(level docker.LogLevel) docker.LogLevel {
+	switch level {
+	case docker.LevelWarn:
+		return docker.LevelError
+	case docker.LevelError:
+		return docker.LevelDebug
+	default:
+		return docker.LevelWarn
+	}
+}
+
+// toggleListMode switches between the pane grid and a compact one-line-per-container
+// overview (name, status, last log line, error count)
+func (a *App) toggleListMode() {
+	if a.listMode {
+		a.listMode = false
+		if a.listCancel != nil {
+			a.listCancel()
+			a.listCancel = nil
+		}
+		a.setupMainLayout()
+		a.updateHelpBar()
+		a.focusContainer(a.selectedContainer)
+		return
+	}
+
+	a.isFullscreen = false
+	a.listMode = true
+	a.applyLastLines()
+
+	if a.listTable == nil {
+		a.listTable = tview.NewTable().
+			SetSelectable(true, false).
+			SetFixed(1, 0)
+		a.listTable.SetBackgroundColor(tcell.NewRGBColor(0, 0, 0))
+		a.listTable.SetBorder(true).
+			SetBorderColor(tcell.ColorGray).
+			SetTitle(" Containers - Enter to open, o to go back ")
+		a.listTable.SetSelectedFunc(func(row, column int) {
+			a.openFromList(row - 1) // row 0 is the header
+		})
+	}
+	a.refreshList()
+	a.listTable.Select(a.selectedContainer+1, 0)
+
+	a.mainGrid.Clear()
+	a.mainGrid.SetRows(0, 3).
+		SetColumns(0).
+		AddItem(a.listTable, 0, 0, 1, 1, 0, 0, true).
+		AddItem(a.helpBar, 1, 0, 1, 1, 0, 0, false)
+	a.app.SetFocus(a.listTable)
+	a.updateHelpBar()
+
+	// Keep the overview current while it is shown
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.listCancel = cancel
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.app.QueueUpdateDraw(func() {
+					if a.listMode {
+						a.refreshList()
+					}
+				})
+			}
+		}
+	}()
+}
+
+// refreshList fills the list table with one row per container
+func (a *App) refreshList() {
+	headerColor := tcell.NewRGBColor(255, 140, 0)
+	for column, header := range []string{"CONTAINER", "STATUS", "ERRORS", "LAST LOG LINE"} {
+		a.listTable.SetCell(0, column, tview.NewTableCell(header).
+			SetTextColor(headerColor).
+			SetSelectable(false))
+	}
+
+	for i, context := range a.contextManager.GetAllContexts() {
+		row := i + 1
+		errors := context.ErrorCount()
+		errorColor := tcell.ColorGray
+		if errors > 0 {
+			errorColor = tcell.ColorRed
+		}
+
+		lastLine := ""
+		if entry, ok := context.LastLogEntry(); ok {
+			lastLine = entry.Timestamp.Format("15:04:05") + " " + entry.Message
+		}
+
+		a.listTable.SetCell(row, 0, tview.NewTableCell(tview.Escape(context.Container.DisplayName())).SetTextColor(context.Color))
+		a.listTable.SetCell(row, 1, tview.NewTableCell(tview.Escape(context.Container.Status)).SetTextColor(tcell.ColorSilver))
+		a.listTable.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%d", errors)).SetTextColor(errorColor).SetAlign(tview.AlignRight))
+		a.listTable.SetCell(row, 3, tview.NewTableCell(tview.Escape(lastLine)).SetTextColor(tcell.ColorWhite).SetExpansion(1))
+	}
+}
+
+// openFromList leaves list mode and shows the chosen container fullscreen
+func (a *App) openFromList(index int) {
+	if index < 0 || index >= a.contextManager.Count() {
+		return
+	}
+	a.selectedContainer = index
+	a.toggleListMode()
+	a.toggleFullscreen()
+}
+
+// toggleSparklines shows or hides a per-second log rate sparkline in every pane title
+func (a *App) toggleSparklines() {
+	if a.sparklineCancel != nil {
+		a.sparklineCancel()
+		a.sparklineCancel = nil
+		return
+	}
+
+	ctx, cancel := context.WithCancel(a.ctx)
+	a.sparklineCancel = cancel
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			for _, context := range a.contextManager.GetAllContexts() {
+				context.UpdateSparkline(time.Now())
+			}
+			select {
+			case <-ctx.Done():
+				for _, context := range a.contextManager.GetAllContexts() {
+					context.ClearSparkline()
+				}
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// toggleTruncation switches long line truncation in unselected panes on or off
+func (a *App) toggleTruncation() {
+	a.truncateLines = !a.truncateLines
+	for _, context := range a.contextManager.GetAllContexts() {
+		context.SetTruncation(a.truncateLines, a.maxLineWidth)
+	}
+
+	state := "off"
+	if a.truncateLines {
+		state = "on (selected pane shows full lines)"
+	}
+	a.showHelpMessage("[#FF8C00]Line truncation "+state+"[white]", 2*time.Second)
+}
+
+// toggleCollapseRepeats switches collapsing identical consecutive lines on or off
+// for new lines in all panes
+func (a *App) toggleCollapseRepeats() {
+	a.SetCollapseRepeats(!a.collapseRepeats)
+
+	state := "off (every line is shown)"
+	if a.collapseRepeats {
+		state = "on (repeated lines show a count)"
+	}
+	a.showHelpMessage("[#FF8C00]Collapsing repeated lines "+state+"[white]", 2*time.Second)
+}
+
+// toggleRecentView switches grid panes between their whole buffer and only their
+// last few lines, so every pane shows comparable recency
+func (a *App) toggleRecentView() {
+	a.recentView = !a.recentView
+	a.applyLastLines()
+
+	state := "off"
+	if a.recentView {
+		state = fmt.Sprintf("on (last %d lines per pane)", a.lastLines)
+	}
+	a.showHelpMessage("[#FF8C00]Recent activity view "+state+"[white]", 2*time.Second)
+}
+
+// applyLastLines sets each pane's line clamp for the recent activity view. The
+// fullscreen pane is never clamped.
+func (a *App) applyLastLines() {
+	for i, context := range a.contextManager.GetAllContexts() {
+		limit := 0
+		if a.recentView && !(a.isFullscreen && i == a.selectedContainer) {
+			limit = a.lastLines
+		}
+		context.SetLastLines(limit)
+	}
+}
+
+// toggleFreeze freezes or resumes the display of every pane at once
+func (a *App) toggleFreeze() {
+	a.frozen = !a.frozen
+	for _, context := range a.contextManager.GetAllContexts() {
+		context.SetFrozen(a.frozen)
+	}
+	a.updateHelpBar()
+}
+
+// togglePauseFocused pauses or resumes the focused pane only
+func (a *App) togglePauseFocused() {
+	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
+	if selectedContext == nil {
+		return
+	}
+	selectedContext.SetPaused(!selectedContext.IsPaused())
+}
+
+// toggleMarkStderrFocused sets the focused pane's stderr lines apart with a
+// background of their own, or stops doing so
+func (a *App) toggleMarkStderrFocused() {
+	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
+	if selectedContext == nil {
+		return
+	}
+	selectedContext.SetMarkStderr(!selectedContext.MarksStderr())
+}
+
+// levelThresholdName returns the help bar label for a display threshold
+func levelThresholdName(level docker.LogLevel) string {
+	if level == docker.LevelDebug {
+		return "ALL"
+	}
+	return level.String() + "+"
+}
+
 func (a *App) exportLogsForLLM() {
 	// Run export in background to avoid blocking the UI
 	go func() {
@@ -373,12 +1150,14 @@ func (a *App) exportLogsForLLM() {
 		
 		// Collect logs from all contexts
 		allLogs := make(map[string][]docker.LogEntry)
+		notes := make(map[string]string)
 		var containers []docker.Container
 		
 		for _, context := range contexts {
 			logBuffer := context.GetLogBuffer()
 			if len(logBuffer) > 0 {
 				allLogs[context.Container.ID] = logBuffer
+				notes[context.Container.ID] = context.Note()
 				containers = append(containers, context.Container)
 			}
 		}
@@ -398,131 +1177,630 @@ func (a *App) exportLogsForLLM() {
 				continue
 			}
 			
-			output += fmt.Sprintf("## Container: %s\n", container.Name)
+			output += fmt.Sprintf("## Container: %s\n", container.DisplayName())
 			output += fmt.Sprintf("- Image: %s\n", container.Image)
 			output += fmt.Sprintf("- Status: %s\n", container.Status)
+			if note := notes[container.ID]; note != "" {
+				output += fmt.Sprintf("- Note: %s\n", note)
+			}
 			
 			output += "```\n"
 			for _, log := range logs {
 				timestamp := log.Timestamp.Format("2006-01-02 15:04:05")
-				output += fmt.Sprintf("[%s] %s\n", timestamp, log.Message)
+				output += fmt.Sprintf("[%s] %s", timestamp, log.Message)
+				if log.Repeats > 1 {
+					output += fmt.Sprintf(" (x%d)", log.Repeats)
+				}
+				output += "\n"
 			}
 			output += "```\n\n"
 		}
 		
-		// Write to temporary file and copy to clipboard if available
-		filename := fmt.Sprintf("/tmp/colog_logs_%d.md", time.Now().Unix())
-		if err := os.WriteFile(filename, []byte(output), 0644); err == nil {
-			// Try to copy to clipboard using pbcopy (macOS) or xclip (Linux)
-			clipboardSuccess := false
-			if err := exec.Command("pbcopy").Run(); err == nil {
-				// pbcopy exists, use it
-				cmd := exec.Command("pbcopy")
-				cmd.Stdin = strings.NewReader(output)
-				if cmd.Run() == nil {
-					clipboardSuccess = true
+		a.shareOutput(output, "colog_logs", "Logs")
+	}()
+}
+
+// shareOutput writes output to a temporary file and copies it to the clipboard if
+// available, reporting the result in the help bar. what names the content ("Logs").
+func (a *App) shareOutput(output, filePrefix, what string) {
+	filename := fmt.Sprintf("/tmp/%s_%d.md", filePrefix, time.Now().Unix())
+	if err := os.WriteFile(filename, []byte(output), 0644); err != nil {
+		a.showHelpMessage(fmt.Sprintf("[red]❌ Failed to export %s[white]", strings.ToLower(what)), 2*time.Second)
+		return
+	}
+
+	clipped, truncated := clipText(output, a.clipboardLimit, filename)
+	if copyToClipboard(clipped) {
+		if truncated {
+			a.showHelpMessage(fmt.Sprintf("[#FFA500]📋 %s truncated to %s in clipboard; full copy in %s[white]", what, formatSize(a.clipboardLimit), filename), 5*time.Second)
+			return
+		}
+		a.showHelpMessage(fmt.Sprintf("[#00FF00]📋 %s copied to clipboard[white]", what), 3*time.Second)
+	} else {
+		a.showHelpMessage(fmt.Sprintf("[#FFA500]📄 %s saved to %s[white]", what, filename), 3*time.Second)
+	}
+}
+
+// copyToClipboard copies text using pbcopy (macOS) or xclip (Linux), reporting success
+func copyToClipboard(text string) bool {
+	if err := exec.Command("pbcopy").Run(); err == nil {
+		// pbcopy exists, use it
+		cmd := exec.Command("pbcopy")
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run() == nil
+	}
+	if err := exec.Command("xclip", "-version").Run(); err == nil {
+		// xclip exists, use it
+		cmd := exec.Command("xclip", "-selection", "clipboard")
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run() == nil
+	}
+	return false
+}
+
+
+func (a *App) restartFocusedContainer() {
+	if !a.requireDocker() {
+		return
+	}
+	if a.contextManager.Count() == 0 {
+		a.showHelpMessage("[red]No containers available[white]", 2*time.Second)
+		return
+	}
+
+	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
+	if selectedContext == nil {
+		a.showHelpMessage("[red]No container selected[white]", 2*time.Second)
+		return
+	}
+
+	containerName := tview.Escape(selectedContext.Container.DisplayName())
+	containerID := selectedContext.Container.ID
+	
+	// Show immediate feedback
+	a.showHelpMessage(fmt.Sprintf("[yellow]Restarting %s...[white]", containerName), 3*time.Second)
+	
+	// Use a channel to communicate result back to main thread instead of QueueUpdateDraw from goroutine
+	resultChan := make(chan error, 1)
+	
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		
+		err := a.dockerService.RestartContainer(ctx, containerID)
+		resultChan <- err
+		close(resultChan)
+	}()
+	
+	// Handle result in main thread without blocking
+	go func() {
+		err := <-resultChan
+		
+		// Use a simple approach - append to the container's log instead of help message
+		message := ""
+		if err != nil {
+			message = fmt.Sprintf("[red]RESTART FAILED: %v[white]", err)
+		} else {
+			message = fmt.Sprintf("[green]RESTART SUCCESS: %s restarted[white]", containerName)
+		}
+		
+		// Add result to the selected container's log stream - this avoids QueueUpdateDraw conflicts
+		if selectedContext.LogView != nil {
+			selectedContext.AppendLog(message)
+		}
+	}()
+}
+
+func (a *App) killFocusedContainer() {
+	if !a.requireDocker() {
+		return
+	}
+	if a.contextManager.Count() == 0 {
+		a.showHelpMessage("[red]No containers available[white]", 2*time.Second)
+		return
+	}
+
+	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
+	if selectedContext == nil {
+		a.showHelpMessage("[red]No container selected[white]", 2*time.Second)
+		return
+	}
+
+	containerName := tview.Escape(selectedContext.Container.DisplayName())
+	containerID := selectedContext.Container.ID
+
+	// Warn when Docker will just restart the container, so it "coming back" isn't a surprise
+	text := fmt.Sprintf("Kill %s?", containerName)
+	ctx, cancel := context.WithTimeout(a.ctx, 3*time.Second)
+	policy, err := a.dockerService.RestartPolicy(ctx, containerID)
+	cancel()
+	if err == nil && docker.RestartsAfterKill(policy) {
+		text += fmt.Sprintf("\n\nIts restart policy is %q, so Docker will restart it right away.", policy)
+	}
+
+	a.confirm(text, "Kill", func() {
+		a.killContainer(containerName, containerID)
+	})
+}
+
+// killContainer kills a container in the background and reports the result
+func (a *App) killContainer(containerName, containerID string) {
+	a.showHelpMessage(fmt.Sprintf("[red]Killing %s...[white]", containerName), 1*time.Second)
+	
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		
+		if err := a.dockerService.KillContainer(ctx, containerID); err != nil {
+			a.app.QueueUpdateDraw(func() {
+				a.showHelpMessage(fmt.Sprintf("[red]Failed to kill %s: %v[white]", containerName, err), 3*time.Second)
+			})
+		} else {
+			a.app.QueueUpdateDraw(func() {
+				a.showHelpMessage(fmt.Sprintf("[red]✗ Killed %s[white]", containerName), 2*time.Second)
+			})
+		}
+	}()
+}
+
+// stopIdleStreams periodically stops the streams of panes that haven't been
+// selected within the lazy idle timeout
+func (a *App) stopIdleStreams() {
+	ticker := time.NewTicker(a.lazyIdleTimeout / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, context := range a.contextManager.GetAllContexts() {
+				if context.IsSelected || !context.IsStreaming() {
+					continue
 				}
-			} else if err := exec.Command("xclip", "-version").Run(); err == nil {
-				// xclip exists, use it
-				cmd := exec.Command("xclip", "-selection", "clipboard")
-				cmd.Stdin = strings.NewReader(output)
-				if cmd.Run() == nil {
-					clipboardSuccess = true
+				if time.Since(context.LastFocused()) > a.lazyIdleTimeout {
+					context.StopStreaming()
 				}
 			}
-			
-			if clipboardSuccess {
-				a.showHelpMessage("[#00FF00]📋 Logs copied to clipboard[white]", 3*time.Second)
-			} else {
-				a.showHelpMessage(fmt.Sprintf("[#FFA500]📄 Logs saved to %s[white]", filename), 3*time.Second)
+		}
+	}
+}
+
+// defaultStallTimeout is how long a stream may stay silent before it's checked
+const defaultStallTimeout = 2 * time.Minute
+
+// watchStalls checks streams that have been silent for the stall timeout, telling
+// quiet containers from wedged streams (see ContainerContext.CheckStall)
+func (a *App) watchStalls() {
+	ticker := time.NewTicker(max(a.stallTimeout/4, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			for _, context := range a.contextManager.GetAllContexts() {
+				context.CheckStall(a.ctx, a.stallTimeout, a.restartStalled)
 			}
-		} else {
-			a.showHelpMessage("[red]❌ Failed to export logs[white]", 2*time.Second)
 		}
-	}()
+	}
+}
+
+// healthPollInterval is how often the panes' healthcheck status and uptime are
+// refreshed
+const healthPollInterval = 5 * time.Second
+
+// pollHealth keeps the health dots and uptimes in the pane titles current. Docker's
+// container list doesn't carry them, so each container is inspected. When the daemon
+// comes back after a restart, the containers are re-listed.
+func (a *App) pollHealth() {
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+
+	unreachable := false
+	for {
+		var contexts []*container.ContainerContext
+		for _, context := range a.contextManager.GetAllContexts() {
+			if context.Container.Origin == "" { // imported containers have no state
+				contexts = append(contexts, context)
+			}
+		}
+		failed := 0
+		for _, context := range contexts {
+			state, err := a.dockerService.State(a.ctx, context.Container.ID)
+			if err != nil {
+				failed++
+				continue
+			}
+			context.SetState(state)
+		}
+		// No container answering means the daemon is down; once it's back, re-list so
+		// containers started meanwhile get panes
+		down := len(contexts) > 0 && failed == len(contexts)
+		if unreachable && !down {
+			a.app.QueueUpdateDraw(a.reloadContainers)
+		}
+		unreachable = down
+
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reloadContainers re-lists running containers and adds/removes panes to match
+func (a *App) reloadContainers() {
+	ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
+	defer cancel()
+
+	containers, err := a.listRunningContainers(ctx)
+	if err != nil {
+		a.showHelpMessage(fmt.Sprintf("[red]Failed to list containers: %v[white]", err), 3*time.Second)
+		return
+	}
+
+	// Keep the focus on the same container if it is still running
+	var focusedID string
+	if selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer); selectedContext != nil {
+		focusedID = selectedContext.Container.ID
+	}
+
+	added, removed, err := a.contextManager.Reconcile(containers, a.source, a.app)
+	if err != nil {
+		a.showHelpMessage(fmt.Sprintf("[red]Refresh incomplete: %v[white]", err), 3*time.Second)
+	}
+
+	a.selectedContainer = 0
+	for i, context := range a.contextManager.GetAllContexts() {
+		if context.Container.ID == focusedID {
+			a.selectedContainer = i
+			break
+		}
+	}
+	// New panes follow the global threshold and freeze state
+	for _, container := range added {
+		if context, ok := a.contextManager.GetContext(container.ID); ok {
+			context.SetTruncation(a.truncateLines, a.maxLineWidth)
+			context.SetMinLevel(a.levelThreshold)
+			context.SetFrozen(a.frozen)
+		}
+	}
+
+	if a.isFullscreen && len(removed) > 0 {
+		a.toggleFullscreen()
+	}
+	a.applyLastLines()
+	a.layoutGrid(a.gridColumns)
+	if !a.isFullscreen {
+		a.focusContainer(a.selectedContainer)
+	}
+
+	if err == nil {
+		a.showHelpMessage(fmt.Sprintf("[#00FF00]Refreshed: +%d / -%d[white]", len(added), len(removed)), 3*time.Second)
+	}
+}
+
+// confirm shows a modal dialog over the current layout and runs onConfirm when the
+// user picks confirmLabel
+func (a *App) confirm(text, confirmLabel string, onConfirm func()) {
+	previousFocus := a.app.GetFocus()
+
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{confirmLabel, "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.confirmMode = false
+			a.pages.RemovePage("confirm")
+			a.app.SetFocus(previousFocus)
+			if buttonLabel == confirmLabel {
+				onConfirm()
+			}
+		})
+	modal.SetBackgroundColor(tcell.NewRGBColor(0, 0, 0)).
+		SetBorderColor(tcell.NewRGBColor(255, 140, 0))
+	modal.SetButtonBackgroundColor(tcell.ColorGray)
+
+	a.confirmMode = true
+	a.pages.AddPage("confirm", modal, true, true)
+	a.app.SetFocus(modal)
+}
+
+// prompt shows a one-line input over the current layout and runs onDone with the
+// text entered; ESC closes it without calling onDone
+func (a *App) prompt(title, label string, onDone func(text string)) {
+	previousFocus := a.app.GetFocus()
+	trueBlack := tcell.NewRGBColor(0, 0, 0)
+
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetLabelColor(tcell.ColorWhite).
+		SetFieldBackgroundColor(trueBlack).
+		SetFieldTextColor(tcell.ColorWhite)
+	input.SetBackgroundColor(trueBlack)
+	input.SetBorder(true).
+		SetTitle(title).
+		SetBorderColor(tcell.NewRGBColor(255, 140, 0))
+	input.SetDoneFunc(func(key tcell.Key) {
+		a.promptMode = false
+		a.pages.RemovePage("prompt")
+		a.app.SetFocus(previousFocus)
+		if key == tcell.KeyEnter {
+			onDone(input.GetText())
+		}
+	})
+
+	// Centered, 3 rows high and at most 80 columns wide
+	box := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(input, 3, 0, true).
+			AddItem(nil, 0, 1, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	a.promptMode = true
+	a.pages.AddPage("prompt", box, true, true)
+	a.app.SetFocus(input)
+}
+
+// followFocusedSince asks where to reload the focused pane's stream from and restarts
+// it there, so lines from before colog started can be brought back
+func (a *App) followFocusedSince() {
+	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
+	if selectedContext == nil {
+		a.showHelpMessage("[red]No container selected[white]", 2*time.Second)
+		return
+	}
+
+	name := tview.Escape(selectedContext.Container.DisplayName())
+	a.prompt(fmt.Sprintf(" Follow %s since (15m, 2h, 14:05 or RFC 3339; empty = last 100 lines) ", name), "Since: ", func(text string) {
+		since, err := parseSince(text, time.Now())
+		if err != nil {
+			a.showHelpMessage(fmt.Sprintf("[red]%s[white]", tview.Escape(err.Error())), 3*time.Second)
+			return
+		}
+		if err := selectedContext.FollowSince(since); err != nil {
+			a.showHelpMessage(fmt.Sprintf("[red]Failed to reload %s: %v[white]", name, err), 3*time.Second)
+		}
+	})
+}
+
+// requireDocker reports whether Docker can act on the focused container, telling the
+// user otherwise (while replaying a recording, or for an imported container)
+func (a *App) requireDocker() bool {
+	if a.dockerService == nil {
+		a.showHelpMessage("[yellow]Not available while replaying a recording[white]", 2*time.Second)
+		return false
+	}
+	if selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer); selectedContext != nil && selectedContext.Container.Origin != "" {
+		a.showHelpMessage("[yellow]Not available for a container of an imported recording[white]", 2*time.Second)
+		return false
+	}
+	return true
+}
+
+// annotateFocused asks for a note on the focused container, replacing its current
+// one; an empty note removes it
+func (a *App) annotateFocused() {
+	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
+	if selectedContext == nil {
+		a.showHelpMessage("[red]No container selected[white]", 2*time.Second)
+		return
+	}
+
+	title := fmt.Sprintf(" Note for %s (empty removes it) ", tview.Escape(selectedContext.Container.DisplayName()))
+	if note := selectedContext.Note(); note != "" {
+		title = fmt.Sprintf(" Note for %s, now %q (empty removes it) ", tview.Escape(selectedContext.Container.DisplayName()), tview.Escape(note))
+	}
+	a.prompt(title, "Note: ", selectedContext.SetNote)
+}
+
+// containerNotes returns the panes' notes keyed by container display name, as the
+// AI service expects. Imported containers are noted as such, so answers don't take a
+// recording for what's running now.
+func (a *App) containerNotes() map[string]string {
+	notes := make(map[string]string)
+	for _, context := range a.contextManager.GetAllContexts() {
+		note := context.Note()
+		if context.Container.Origin != "" {
+			note = strings.TrimSuffix(importedNote+"; "+note, "; ")
+		}
+		if note != "" {
+			notes[context.Container.DisplayName()] = note
+		}
+	}
+	return notes
 }
 
+// importedNote tells the AI an imported container's logs were recorded earlier
+const importedNote = "logs from an imported recording, not live"
 
-func (a *App) restartFocusedContainer() {
-	if a.contextManager.Count() == 0 {
-		a.showHelpMessage("[red]No containers available[white]", 2*time.Second)
+// execShellInFocusedContainer suspends the TUI and opens an interactive shell (sh,
+// falling back to bash) in the focused container, restoring the TUI when it exits
+func (a *App) execShellInFocusedContainer() {
+	if !a.requireDocker() {
 		return
 	}
-
 	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
 	if selectedContext == nil {
 		a.showHelpMessage("[red]No container selected[white]", 2*time.Second)
 		return
 	}
 
-	containerName := selectedContext.Container.Name
+	if _, err := exec.LookPath("docker"); err != nil {
+		a.showHelpMessage("[red]docker CLI not found in PATH - needed for an interactive shell[white]", 3*time.Second)
+		return
+	}
+
+	containerName := selectedContext.Container.DisplayName()
 	containerID := selectedContext.Container.ID
-	
-	// Show immediate feedback
-	a.showHelpMessage(fmt.Sprintf("[yellow]Restarting %s...[white]", containerName), 3*time.Second)
-	
-	// Use a channel to communicate result back to main thread instead of QueueUpdateDraw from goroutine
-	resultChan := make(chan error, 1)
-	
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
-		defer cancel()
-		
-		err := a.dockerService.RestartContainer(ctx, containerID)
-		resultChan <- err
-		close(resultChan)
-	}()
-	
-	// Handle result in main thread without blocking
-	go func() {
-		err := <-resultChan
-		
-		// Use a simple approach - append to the container's log instead of help message
-		message := ""
-		if err != nil {
-			message = fmt.Sprintf("[red]RESTART FAILED: %v[white]", err)
-		} else {
-			message = fmt.Sprintf("[green]RESTART SUCCESS: %s restarted[white]", containerName)
+
+	shell := ""
+	for _, candidate := range []string{"sh", "bash"} {
+		// Probe without a TTY so a missing shell fails fast instead of mid-session
+		if a.dockerCommand(containerID, "exec", containerID, candidate, "-c", "exit 0").Run() == nil {
+			shell = candidate
+			break
 		}
-		
-		// Add result to the selected container's log stream - this avoids QueueUpdateDraw conflicts
-		if selectedContext.LogView != nil {
-			selectedContext.AppendLog(message)
+	}
+	if shell == "" {
+		a.showHelpMessage(fmt.Sprintf("[red]No shell (sh or bash) available in %s[white]", tview.Escape(containerName)), 3*time.Second)
+		return
+	}
+
+	var execErr error
+	a.app.Suspend(func() {
+		fmt.Printf("Opening %s in %s (exit the shell to return to colog)...\n", shell, containerName)
+		cmd := a.dockerCommand(containerID, "exec", "-it", containerID, shell)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		execErr = cmd.Run()
+	})
+
+	// A non-zero exit from the user's last command is not a failure worth reporting
+	if execErr != nil {
+		if _, exited := execErr.(*exec.ExitError); !exited {
+			a.showHelpMessage(fmt.Sprintf("[red]Failed to open shell in %s: %v[white]", tview.Escape(containerName), execErr), 3*time.Second)
 		}
-	}()
+	}
 }
 
-func (a *App) killFocusedContainer() {
-	if a.contextManager.Count() == 0 {
-		a.showHelpMessage("[red]No containers available[white]", 2*time.Second)
-		return
+// dockerCommand builds a docker CLI command aimed at the host running containerID
+// when containers come from several hosts
+func (a *App) dockerCommand(containerID string, args ...string) *exec.Cmd {
+	cmd := exec.Command("docker", args...)
+	if a.dockerService.IsMultiHost() {
+		if host, err := a.dockerService.DaemonHost(a.ctx, containerID); err == nil {
+			cmd.Env = append(os.Environ(), "DOCKER_HOST="+host)
+		}
 	}
+	return cmd
+}
 
+// openInspectPanel shows the focused container's configuration and inspect JSON
+func (a *App) openInspectPanel() {
+	if !a.requireDocker() {
+		return
+	}
 	selectedContext := a.contextManager.GetContextByIndex(a.selectedContainer)
 	if selectedContext == nil {
 		a.showHelpMessage("[red]No container selected[white]", 2*time.Second)
 		return
 	}
 
-	containerName := selectedContext.Container.Name
-	containerID := selectedContext.Container.ID
-	
-	a.showHelpMessage(fmt.Sprintf("[red]Killing %s...[white]", containerName), 1*time.Second)
-	
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-		
-		if err := a.dockerService.KillContainer(ctx, containerID); err != nil {
-			a.app.QueueUpdateDraw(func() {
-				a.showHelpMessage(fmt.Sprintf("[red]Failed to kill %s: %v[white]", containerName, err), 3*time.Second)
-			})
-		} else {
-			a.app.QueueUpdateDraw(func() {
-				a.showHelpMessage(fmt.Sprintf("[red]✗ Killed %s[white]", containerName), 2*time.Second)
-			})
+	ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+	defer cancel()
+	info, err := a.dockerService.Inspect(ctx, selectedContext.Container.ID)
+	if err != nil {
+		a.showHelpMessage(fmt.Sprintf("[red]Failed to inspect %s: %v[white]", tview.Escape(selectedContext.Container.DisplayName()), err), 3*time.Second)
+		return
+	}
+
+	jsonData, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		a.showHelpMessage(fmt.Sprintf("[red]Failed to format inspect data: %v[white]", err), 3*time.Second)
+		return
+	}
+	a.inspectJSON = string(jsonData)
+
+	if a.inspectView == nil {
+		a.inspectView = tview.NewTextView().
+			SetDynamicColors(true).
+			SetScrollable(true).
+			SetWrap(true)
+		a.inspectView.SetBackgroundColor(tcell.NewRGBColor(0, 0, 0))
+		a.inspectView.SetBorder(true).
+			SetBorderColor(tcell.NewRGBColor(255, 140, 0))
+	}
+	a.inspectView.SetTitle(fmt.Sprintf(" Inspect: %s - c to copy JSON, ESC to close ", tview.Escape(selectedContext.Container.DisplayName())))
+	a.inspectView.SetText(formatInspectSummary(info) + "\n[gray]── Full inspect JSON ──[white]\n" + tview.Escape(a.inspectJSON))
+	a.inspectView.ScrollToBeginning()
+
+	a.inspectMode = true
+	a.mainGrid.Clear()
+	a.mainGrid.SetRows(0, 3).
+		SetColumns(0).
+		AddItem(a.inspectView, 0, 0, 1, 1, 0, 0, true).
+		AddItem(a.helpBar, 1, 0, 1, 1, 0, 0, false)
+	a.app.SetFocus(a.inspectView)
+	a.updateHelpBar()
+}
+
+// closeInspectPanel restores the pane layout
+func (a *App) closeInspectPanel() {
+	a.inspectMode = false
+	if a.isFullscreen {
+		a.isFullscreen = false
+		a.toggleFullscreen()
+	} else {
+		a.setupMainLayout()
+		a.focusContainer(a.selectedContainer)
+	}
+	a.updateHelpBar()
+}
+
+// formatInspectSummary renders the commonly needed parts of the inspect data
+func formatInspectSummary(info containertypes.InspectResponse) string {
+	var summary strings.Builder
+	field := func(name, value string) {
+		summary.WriteString(fmt.Sprintf("[#FF8C00]%-15s[white] %s\n", name+":", tview.Escape(value)))
+	}
+
+	if info.ContainerJSONBase != nil {
+		field("Name", strings.TrimPrefix(info.Name, "/"))
+		field("ID", info.ID)
+		field("Image", info.Image)
+		if info.State != nil {
+			field("State", info.State.Status)
 		}
-	}()
+		if info.HostConfig != nil {
+			policy := string(info.HostConfig.RestartPolicy.Name)
+			if policy == "" {
+				policy = "no"
+			}
+			field("Restart policy", policy)
+		}
+	}
+
+	if info.Config != nil {
+		field("Config image", info.Config.Image)
+		field("Command", strings.Join(append(append([]string{}, info.Config.Entrypoint...), info.Config.Cmd...), " "))
+		if len(info.Config.Env) > 0 {
+			summary.WriteString("[#FF8C00]Env:[white]\n")
+			for _, env := range info.Config.Env {
+				summary.WriteString("  " + tview.Escape(env) + "\n")
+			}
+		}
+	}
+
+	if info.NetworkSettings != nil && len(info.NetworkSettings.Ports) > 0 {
+		summary.WriteString("[#FF8C00]Ports:[white]\n")
+		for port, bindings := range info.NetworkSettings.Ports {
+			if len(bindings) == 0 {
+				summary.WriteString(fmt.Sprintf("  %s\n", port))
+			}
+			for _, binding := range bindings {
+				summary.WriteString(fmt.Sprintf("  %s:%s -> %s\n", binding.HostIP, binding.HostPort, port))
+			}
+		}
+	}
+
+	if len(info.Mounts) > 0 {
+		summary.WriteString("[#FF8C00]Mounts:[white]\n")
+		for _, mount := range info.Mounts {
+			mode := "rw"
+			if !mount.RW {
+				mode = "ro"
+			}
+			summary.WriteString(fmt.Sprintf("  %s -> %s (%s, %s)\n", tview.Escape(mount.Source), tview.Escape(mount.Destination), mount.Type, mode))
+		}
+	}
+
+	return summary.String()
 }
 
 // toggleSearchMode toggles literal search mode on/off
@@ -637,6 +1915,9 @@ func (a *App) setupSearchLayout(mode string) {
 	// Update label and handler based on mode
 	if mode == "AI Search" {
 		a.searchInput.SetLabel("AI Search: ")
+		if a.aiDeepSearch {
+			a.searchInput.SetLabel(fmt.Sprintf("AI Search (last %d lines): ", aiHistoryLines))
+		}
 		a.searchInput.SetChangedFunc(func(text string) {
 			// AI Search mode processes on Enter, not on change
 		})
@@ -647,10 +1928,13 @@ func (a *App) setupSearchLayout(mode string) {
 			} else if event.Key() == tcell.KeyEnter {
 				text := a.searchInput.GetText()
 				if text != "" {
+					a.history.Add(historyAISearch, text)
 					a.performAISearch(text)
 					a.searchInput.SetText("")
 				}
 				return nil
+			} else if a.cycleHistory(historyAISearch, event) {
+				return nil
 			}
 			return event
 		})
@@ -666,10 +1950,13 @@ func (a *App) setupSearchLayout(mode string) {
 			} else if event.Key() == tcell.KeyEnter {
 				text := a.searchInput.GetText()
 				if text != "" {
+					a.history.Add(historyChat, text)
 					a.performAIChat(text)
 					a.searchInput.SetText("")
 				}
 				return nil
+			} else if a.cycleHistory(historyChat, event) {
+				return nil
 			}
 			return event
 		})
@@ -678,7 +1965,24 @@ func (a *App) setupSearchLayout(mode string) {
 		a.searchInput.SetChangedFunc(func(text string) {
 			a.performSearch(text)
 		})
+		// Literal search runs as you type, so the query is remembered on Enter or ESC
+		a.searchInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch event.Key() {
+			case tcell.KeyEscape:
+				a.history.Add(historySearch, a.searchInput.GetText())
+				a.toggleSearchMode()
+				return nil
+			case tcell.KeyEnter:
+				a.history.Add(historySearch, a.searchInput.GetText())
+				return nil
+			}
+			if a.cycleHistory(historySearch, event) {
+				return nil
+			}
+			return event
+		})
 	}
+	a.history.Reset(historyMode(mode))
 	
 	// Create search results if it doesn't exist  
 	if a.searchResults == nil {
@@ -696,14 +2000,17 @@ func (a *App) setupSearchLayout(mode string) {
 		a.searchResults.SetBorderColor(tcell.NewRGBColor(0, 255, 127)). // Green for AI
 			SetTitle(" AI Semantic Search Results - ESC to exit ")
 		a.searchResults.SetText("Enter query for AI-powered semantic search...")
+		if a.aiDeepSearch {
+			a.searchResults.SetText(fmt.Sprintf("Enter query for AI-powered semantic search over the last %d lines of each container...", aiHistoryLines))
+		}
 	} else if mode == "AI Chat" {
 		a.searchResults.SetBorderColor(tcell.NewRGBColor(64, 224, 255)). // Blue for chat
 			SetTitle(" AI Chat - Press Enter to send, ESC to exit ")
-		a.searchResults.SetText("Ask questions about your logs. GPT-4o will analyze them for you...")
+		a.searchResults.SetText(fmt.Sprintf("Ask questions about your logs. %s will analyze them for you...", tview.Escape(a.aiModel(true))))
 	} else {
 		a.searchResults.SetBorderColor(tcell.NewRGBColor(128, 0, 128)). // Purple for regular search
 			SetTitle(" Search Results - ESC to exit ")
-		a.searchResults.SetText("Enter search term...")
+		a.searchResults.SetText("Enter search term... (prefix with re: for a regular expression)")
 	}
 	
 	// KEEP EXISTING GRID INTACT - just add search overlay on top
@@ -723,8 +2030,42 @@ func (a *App) setupSearchLayout(mode string) {
 	a.updateHelpBar()
 }
 
+// historyMode maps a search layout mode to its history
+func historyMode(mode string) string {
+	switch mode {
+	case "AI Search":
+		return historyAISearch
+	case "AI Chat":
+		return historyChat
+	default:
+		return historySearch
+	}
+}
+
+// cycleHistory replaces the search input with an older (Up) or newer (Down) query
+// of the mode's history; false for other keys
+func (a *App) cycleHistory(mode string, event *tcell.EventKey) bool {
+	var query string
+	var ok bool
+	switch event.Key() {
+	case tcell.KeyUp:
+		query, ok = a.history.Previous(mode)
+	case tcell.KeyDown:
+		query, ok = a.history.Next(mode)
+	default:
+		return false
+	}
+	if ok {
+		a.searchInput.SetText(query)
+	}
+	return true
+}
+
 // performSearch searches logs synchronously (like exportLogsForLLM)
 func (a *App) performSearch(searchTerm string) {
+	a.searchTerm = searchTerm
+	a.searchMatches = nil
+
 	if searchTerm == "" {
 		a.searchResults.SetText("Enter search term...")
 		return
@@ -736,18 +2077,25 @@ func (a *App) performSearch(searchTerm string) {
 		return
 	}
 	
+	matcher, err := newSearchMatcher(searchTerm)
+	if err != nil {
+		a.searchResults.SetText(fmt.Sprintf("[red]Invalid regular expression: %s[white]", tview.Escape(err.Error())))
+		return
+	}
+
 	var results []string
-	searchTermLower := strings.ToLower(searchTerm)
 	
 	// Search through all container logs (simple synchronous approach)
 	for _, context := range contexts {
 		logBuffer := context.GetLogBuffer()
 		containerMatches := []string{}
+		var matchedEntries []docker.LogEntry
 		
 		for _, logEntry := range logBuffer {
-			if strings.Contains(strings.ToLower(logEntry.Message), searchTermLower) {
+			if matcher.Match(logEntry.Message) {
+				matchedEntries = append(matchedEntries, logEntry)
 				// Highlight matches in purple
-				highlightedMessage := a.highlightSearchTerm(logEntry.Message, searchTerm)
+				highlightedMessage := matcher.Highlight(logEntry.Message)
 				timestamp := logEntry.Timestamp.Format("15:04:05")
 				matchLine := fmt.Sprintf("[gray]%s[white] %s", timestamp, highlightedMessage)
 				containerMatches = append(containerMatches, matchLine)
@@ -755,7 +2103,8 @@ func (a *App) performSearch(searchTerm string) {
 		}
 		
 		if len(containerMatches) > 0 {
-			containerHeader := fmt.Sprintf("[orange]Container: %s (%d matches)[white]", context.Container.Name, len(containerMatches))
+			a.searchMatches = append(a.searchMatches, searchMatch{Container: context.Container, Entries: matchedEntries})
+			containerHeader := fmt.Sprintf("[orange]Container: %s (%d matches)[white]", tview.Escape(context.Container.DisplayName()), len(containerMatches))
 			results = append(results, containerHeader)
 			results = append(results, containerMatches...)
 			results = append(results, "") // Empty line between containers
@@ -764,49 +2113,131 @@ func (a *App) performSearch(searchTerm string) {
 	
 	// Update results
 	if len(results) == 0 {
-		a.searchResults.SetText(fmt.Sprintf("No matches found for: %s", searchTerm))
+		a.searchResults.SetText(fmt.Sprintf("No matches found for: %s", tview.Escape(searchTerm)))
 	} else {
 		a.searchResults.SetText(strings.Join(results, "\n"))
 		a.searchResults.ScrollToBeginning()
 	}
 }
 
-// highlightSearchTerm adds purple highlighting (simple string replacement)
-func (a *App) highlightSearchTerm(text, searchTerm string) string {
-	if searchTerm == "" {
-		return text
+// exportSearchResults copies the current search matches to the clipboard as markdown
+func (a *App) exportSearchResults() {
+	if len(a.searchMatches) == 0 {
+		a.showHelpMessage("[red]No search results to export[white]", 2*time.Second)
+		return
 	}
-	
-	// Case-insensitive replacement with purple highlighting
-	searchLower := strings.ToLower(searchTerm)
+
+	output := formatSearchResults(a.searchTerm, a.searchMatches, time.Now())
+	go a.shareOutput(output, "colog_search", "Search results")
+}
+
+// formatSearchResults renders search matches as markdown, one section per container
+func formatSearchResults(searchTerm string, matches []searchMatch, generatedAt time.Time) string {
+	total := 0
+	for _, match := range matches {
+		total += len(match.Entries)
+	}
+
+	var output strings.Builder
+	output.WriteString("# Docker Log Search Results\n\n")
+	output.WriteString(fmt.Sprintf("Search: `%s`\n", searchTerm))
+	output.WriteString(fmt.Sprintf("Matches: %d in %d containers\n", total, len(matches)))
+	output.WriteString(fmt.Sprintf("Generated at: %s\n\n", generatedAt.Format("2006-01-02 15:04:05")))
+
+	for _, match := range matches {
+		output.WriteString(fmt.Sprintf("## Container: %s (%d matches)\n", match.Container.DisplayName(), len(match.Entries)))
+		output.WriteString(fmt.Sprintf("- Image: %s\n", match.Container.Image))
+		if match.Container.Origin != "" {
+			output.WriteString("- Source: imported recording\n")
+		}
+		output.WriteString("```\n")
+		for _, entry := range match.Entries {
+			output.WriteString(fmt.Sprintf("[%s] %s\n", entry.Timestamp.Format("2006-01-02 15:04:05"), entry.Message))
+		}
+		output.WriteString("```\n\n")
+	}
+	return output.String()
+}
+
+// regexSearchPrefix switches the literal search to a regular expression search
+const regexSearchPrefix = "re:"
+
+// searchMatcher matches log lines for the / search: a case-insensitive substring by
+// default, or a regular expression when the query starts with "re:"
+type searchMatcher struct {
+	term  string         // lowercased substring, when not a regex search
+	regex *regexp.Regexp // compiled "re:" query
+}
+
+func newSearchMatcher(query string) (*searchMatcher, error) {
+	if pattern, ok := strings.CutPrefix(query, regexSearchPrefix); ok {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return &searchMatcher{regex: regex}, nil
+	}
+	return &searchMatcher{term: strings.ToLower(query)}, nil
+}
+
+// Match reports whether text contains a match
+func (m *searchMatcher) Match(text string) bool {
+	if m.regex != nil {
+		return m.regex.MatchString(text)
+	}
+	return strings.Contains(strings.ToLower(text), m.term)
+}
+
+// matchIndexes returns the [start, end) byte ranges of the non-empty matches in text
+func (m *searchMatcher) matchIndexes(text string) [][]int {
+	if m.regex != nil {
+		var indexes [][]int
+		for _, loc := range m.regex.FindAllStringIndex(text, -1) {
+			if loc[1] > loc[0] {
+				indexes = append(indexes, loc)
+			}
+		}
+		return indexes
+	}
+	if m.term == "" {
+		return nil
+	}
+
+	// Lowercasing can change byte lengths outside ASCII, so only trust indexes into
+	// the lowered text when the lengths agree
 	textLower := strings.ToLower(text)
-	
-	var result strings.Builder
-	lastIndex := 0
-	
-	for {
-		index := strings.Index(textLower[lastIndex:], searchLower)
+	if len(textLower) != len(text) {
+		return nil
+	}
+	var indexes [][]int
+	for offset := 0; ; {
+		index := strings.Index(textLower[offset:], m.term)
 		if index == -1 {
-			result.WriteString(text[lastIndex:])
-			break
+			return indexes
 		}
-		
-		index += lastIndex
-		result.WriteString(text[lastIndex:index])
-		
-		originalMatch := text[index : index+len(searchTerm)]
-		result.WriteString(fmt.Sprintf("[purple]%s[white]", originalMatch))
-		
-		lastIndex = index + len(searchTerm)
+		start := offset + index
+		offset = start + len(m.term)
+		indexes = append(indexes, []int{start, offset})
 	}
-	
+}
+
+// Highlight escapes text for tview and colors the matches purple
+func (m *searchMatcher) Highlight(text string) string {
+	var result strings.Builder
+	lastIndex := 0
+	for _, loc := range m.matchIndexes(text) {
+		result.WriteString(tview.Escape(text[lastIndex:loc[0]]))
+		result.WriteString(fmt.Sprintf("[purple]%s[white]", tview.Escape(text[loc[0]:loc[1]])))
+		lastIndex = loc[1]
+	}
+	result.WriteString(tview.Escape(text[lastIndex:]))
 	return result.String()
 }
 
 // performAISearch performs AI-powered semantic search
 func (a *App) performAISearch(query string) {
 	logs := a.getAllLogs()
-	if len(logs) == 0 {
+	if len(logs) == 0 && !a.aiDeepSearch {
 		a.app.QueueUpdateDraw(func() {
 			a.searchResults.SetText("[red]No logs available for AI search[white]")
 		})
@@ -839,7 +2270,7 @@ func (a *App) performAISearch(query string) {
 					frame++
 					
 					a.app.QueueUpdateDraw(func() {
-						a.searchResults.SetText(fmt.Sprintf("%s Analyzing logs with AI for: [green]%s[white]\n\n[cyan]Processing with GPT-4o-mini...[white]", currentStar, query))
+						a.searchResults.SetText(fmt.Sprintf("%s Analyzing logs with AI for: [green]%s[white]\n\n[cyan]Processing with %s...[white]", currentStar, query, tview.Escape(a.aiModel(false))))
 						a.searchResults.ScrollToEnd()
 					})
 					a.app.ForceDraw()
@@ -847,8 +2278,14 @@ func (a *App) performAISearch(query string) {
 			}
 		}()
 		
+		// A deep search reads more history from Docker; the token budget still applies
+		if a.aiDeepSearch {
+			logs = a.fetchHistory(ctx, aiHistoryLines, logs)
+		}
+
 		// Perform the AI search
-		results, err := a.aiService.SemanticSearch(ctx, query, logs)
+		a.aiService.SetNotes(a.containerNotes())
+		response, err := a.aiService.SemanticSearch(ctx, query, logs)
 		loadingDone <- true
 		
 		// Display results
@@ -860,16 +2297,20 @@ func (a *App) performAISearch(query string) {
 			
 			// Clear and show clean results
 			var output strings.Builder
-			output.WriteString(fmt.Sprintf("AI Semantic Search Results for: [green]%s[white]\n\n", query))
+			output.WriteString(fmt.Sprintf("AI Semantic Search Results for: [green]%s[white]\n\n", tview.Escape(query)))
+			if response.DroppedLines > 0 {
+				output.WriteString(fmt.Sprintf("[yellow]Partial analysis: %d older log lines were left out to fit the token budget (OPENAI_TOKEN_BUDGET)[white]\n\n", response.DroppedLines))
+			}
+			results := response.Results
 			
 			if len(results) == 0 {
 				output.WriteString("[gray]No semantic matches found for this query.[white]")
 			} else {
 				for i, result := range results {
-					output.WriteString(fmt.Sprintf("[green]%d. Container: %s[white] ([yellow]%s[white])\n", i+1, result.Container, result.Relevance))
-					output.WriteString(fmt.Sprintf("   [gray]%s[white] %s\n", result.LogEntry.Timestamp.Format("15:04:05"), result.LogEntry.Message))
+					output.WriteString(fmt.Sprintf("[green]%d. Container: %s[white] ([yellow]%s[white])\n", i+1, tview.Escape(result.Container), tview.Escape(result.Relevance)))
+					output.WriteString(fmt.Sprintf("   [gray]%s[white] %s\n", result.LogEntry.Timestamp.Format("15:04:05"), tview.Escape(result.LogEntry.Message)))
 					if result.Explanation != "" {
-						output.WriteString(fmt.Sprintf("   [cyan]%s[white]\n", result.Explanation))
+						output.WriteString(fmt.Sprintf("   [cyan]%s[white]\n", tview.Escape(result.Explanation)))
 					}
 					output.WriteString("\n")
 				}
@@ -882,6 +2323,37 @@ func (a *App) performAISearch(query string) {
 	}()
 }
 
+// aiHistoryLines is how many lines per container a deep AI search (A) reads
+const aiHistoryLines = 500
+
+// fetchHistory reads the last lines of every container's logs from Docker, keyed by
+// display name like getAllLogs; containers that can't be read keep their buffer
+// from fallback
+func (a *App) fetchHistory(ctx context.Context, lines int, fallback map[string][]docker.LogEntry) map[string][]docker.LogEntry {
+	if a.dockerService == nil {
+		return fallback
+	}
+	logs := make(map[string][]docker.LogEntry)
+	for _, context := range a.contextManager.GetAllContexts() {
+		name := context.Container.DisplayName()
+		if context.Container.Origin != "" { // an imported container's buffer is its history
+			if buffered := fallback[name]; len(buffered) > 0 {
+				logs[name] = buffered
+			}
+			continue
+		}
+		history, err := a.dockerService.GetRecentLogs(ctx, context.Container.ID, lines)
+		if err != nil || len(history) == 0 {
+			if buffered := fallback[name]; len(buffered) > 0 {
+				logs[name] = buffered
+			}
+			continue
+		}
+		logs[name] = history
+	}
+	return logs
+}
+
 // getAllLogs collects logs from all containers
 func (a *App) getAllLogs() map[string][]docker.LogEntry {
 	contexts := a.contextManager.GetAllContexts()
@@ -889,7 +2361,7 @@ func (a *App) getAllLogs() map[string][]docker.LogEntry {
 	for _, context := range contexts {
 		logBuffer := context.GetLogBuffer()
 		if len(logBuffer) > 0 {
-			logs[context.Container.Name] = logBuffer
+			logs[context.Container.DisplayName()] = logBuffer
 		}
 	}
 	return logs
@@ -911,7 +2383,7 @@ func (a *App) performAIChat(query string) {
 	
 	// Show loading message
 	currentChat := a.formatChatHistory()
-	currentChat += fmt.Sprintf("\n[blue]You:[white] %s\n\n🤖 GPT-4o is analyzing your logs...", query)
+	currentChat += fmt.Sprintf("\n[blue]You:[white] %s\n\n🤖 %s is analyzing your logs...", query, tview.Escape(a.aiModel(true)))
 	a.searchResults.SetText(currentChat)
 	a.searchResults.ScrollToEnd()
 	
@@ -926,7 +2398,7 @@ func (a *App) performAIChat(query string) {
 	for _, context := range contexts {
 		logBuffer := context.GetLogBuffer()
 		if len(logBuffer) > 0 {
-			logs[context.Container.Name] = logBuffer
+			logs[context.Container.DisplayName()] = logBuffer
 		}
 	}
 	
@@ -935,6 +2407,7 @@ func (a *App) performAIChat(query string) {
 		ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
 		defer cancel()
 		
+		a.aiService.SetNotes(a.containerNotes())
 		response, err := a.aiService.ChatWithLogs(ctx, query, logs, a.chatHistory[:len(a.chatHistory)-1]) // Exclude the current query
 		
 		// Update UI in main thread
@@ -942,7 +2415,11 @@ func (a *App) performAIChat(query string) {
 			if err != nil {
 				a.chatHistory = append(a.chatHistory, fmt.Sprintf("Error: %v", err))
 			} else {
-				a.chatHistory = append(a.chatHistory, response.Analysis)
+				analysis := response.Analysis
+				if response.DroppedLines > 0 {
+					analysis += fmt.Sprintf("\n\n(Partial analysis: %d older log lines were left out to fit the token budget)", response.DroppedLines)
+				}
+				a.chatHistory = append(a.chatHistory, analysis)
 			}
 			
 			// Update chat display
@@ -953,6 +2430,18 @@ func (a *App) performAIChat(query string) {
 	}()
 }
 
+// aiModel names the model behind chat (or semantic search), as configured with
+// OPENAI_MODEL_CHAT and OPENAI_MODEL_SEARCH; "AI" without an AI service
+func (a *App) aiModel(chat bool) string {
+	switch {
+	case a.aiService == nil:
+		return "AI"
+	case chat:
+		return a.aiService.ChatModel()
+	}
+	return a.aiService.SearchModel()
+}
+
 // formatChatHistory formats the chat history for display
 func (a *App) formatChatHistory() string {
 	if len(a.chatHistory) == 0 {
@@ -964,9 +2453,9 @@ func (a *App) formatChatHistory() string {
 	
 	for i, msg := range a.chatHistory {
 		if i%2 == 0 { // User messages
-			output.WriteString(fmt.Sprintf("[blue]You:[white] %s\n\n", msg))
+			output.WriteString(fmt.Sprintf("[blue]You:[white] %s\n\n", tview.Escape(msg)))
 		} else { // AI responses
-			output.WriteString(fmt.Sprintf("[green]🤖 GPT-4o:[white] %s\n\n", msg))
+			output.WriteString(fmt.Sprintf("[green]🤖 %s:[white] %s\n\n", tview.Escape(a.aiModel(true)), tview.Escape(msg)))
 		}
 	}
 	
@@ -985,6 +2474,20 @@ func isTTY() bool {
 	return false
 }
 
+// probeTerminal initializes and releases a tcell screen to check the terminal can
+// run the TUI
+func probeTerminal() error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	screen.Fini()
+	return nil
+}
+
 func (a *App) runSimpleMode() error {
 	fmt.Println("Starting simple log output mode (press Ctrl+C to stop)...")
 	fmt.Println(strings.Repeat("=", 60))
@@ -993,10 +2496,22 @@ func (a *App) runSimpleMode() error {
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
-	// Start streaming logs in simple text mode
+	// Start streaming logs in simple text mode, with names padded to a common width
+	// and colored per container (only when writing to a terminal)
 	contexts := a.contextManager.GetAllContexts()
+	nameWidth := 0
 	for _, context := range contexts {
-		go a.streamContainerLogsSimple(context)
+		if width := len(context.Container.DisplayName()); width > nameWidth {
+			nameWidth = width
+		}
+	}
+	useColor := isTTY() && !a.noColor
+	for i, context := range contexts {
+		prefix := simplePrefix(context.Container.DisplayName(), nameWidth, i, useColor)
+		go a.streamContainerLogsSimple(context, prefix)
+	}
+	if a.dockerService != nil {
+		go a.watchContainersSimple(nameWidth, useColor)
 	}
 
 	// Wait for signal or context cancellation
@@ -1010,15 +2525,76 @@ func (a *App) runSimpleMode() error {
 	return nil
 }
 
-func (a *App) streamContainerLogsSimple(context *container.ContainerContext) {
+// simpleRescanInterval is how often simple mode re-lists containers to pick up ones
+// started after it
+const simpleRescanInterval = 10 * time.Second
+
+// watchContainersSimple periodically re-lists the running containers in simple mode,
+// streaming containers that started since and dropping the ones that stopped
+func (a *App) watchContainersSimple(nameWidth int, useColor bool) {
+	ticker := time.NewTicker(simpleRescanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(a.ctx, 10*time.Second)
+		containers, err := a.listRunningContainers(ctx)
+		cancel()
+		if err != nil {
+			continue
+		}
+		added, removed, err := a.contextManager.Reconcile(containers, a.source, a.app)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to follow new containers: %v\n", err)
+		}
+		for _, container := range removed {
+			fmt.Printf("\n=== %s stopped ===\n", container.DisplayName())
+		}
+		for _, added := range added {
+			context, ok := a.contextManager.GetContext(added.ID)
+			if !ok {
+				continue
+			}
+			context.SetTruncation(a.truncateLines, a.maxLineWidth)
+			prefix := simplePrefix(added.DisplayName(), nameWidth, a.contextManager.Count()-1, useColor)
+			go a.streamContainerLogsSimple(context, prefix)
+		}
+	}
+}
+
+// simpleNameColors are the ANSI colors cycled through for container names in simple mode
+var simpleNameColors = []string{"36", "33", "32", "35", "34", "96", "93", "92", "95", "94"}
+
+// simplePrefix pads a container name to width and colors it, like docker-compose logs
+func simplePrefix(name string, width, index int, useColor bool) string {
+	padded := fmt.Sprintf("%-*s |", width, name)
+	if !useColor {
+		return padded
+	}
+	return fmt.Sprintf("\033[%sm%s\033[0m", simpleNameColors[index%len(simpleNameColors)], padded)
+}
+
+func (a *App) streamContainerLogsSimple(context *container.ContainerContext, prefix string) {
 	container := context.Container
-	fmt.Printf("\n=== %s (%s) ===\n", container.Name, container.ID)
+	id := docker.ShortID(container.ID)
+	if a.fullIDs {
+		id = container.ID
+	}
+	fmt.Printf("\n=== %s (%s) ===\n", container.DisplayName(), id)
 	
-	// First, show recent logs using the reliable GetRecentLogs method
-	if recentLogs, err := a.dockerService.GetRecentLogs(a.ctx, container.ID, 10); err == nil {
-		for _, entry := range recentLogs {
-			timestamp := entry.Timestamp.Format("15:04:05")
-			fmt.Printf("[%s] %s: %s\n", timestamp, container.Name, entry.Message)
+	// First, show recent logs using the reliable GetRecentLogs method (a replay's
+	// stream carries the whole recording)
+	if a.dockerService != nil {
+		if recentLogs, err := a.dockerService.GetRecentLogs(a.ctx, container.ID, 10); err == nil {
+			for _, entry := range recentLogs {
+				timestamp := entry.Timestamp.Format("15:04:05")
+				fmt.Printf("%s [%s] %s\n", prefix, timestamp, entry.Message)
+			}
 		}
 	}
 	
@@ -1033,7 +2609,7 @@ func (a *App) streamContainerLogsSimple(context *container.ContainerContext) {
 			}
 			
 			timestamp := entry.Timestamp.Format("15:04:05")
-			fmt.Printf("[%s] %s: %s\n", timestamp, container.Name, entry.Message)
+			fmt.Printf("%s [%s] %s\n", prefix, timestamp, entry.Message)
 		}
 	}
 }
\ No newline at end of file
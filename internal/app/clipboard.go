@@ -0,0 +1,44 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultClipboardLimit is the most bytes copied to the clipboard unless
+// SetClipboardLimit changes it; some clipboard managers choke on larger text
+const defaultClipboardLimit = 1 << 20
+
+// clipText cuts text to at most limit bytes for the clipboard, at a line boundary,
+// and ends it with a note pointing to the full content in filename. Text within
+// the limit (or a limit of 0) is returned unchanged, with truncated false.
+func clipText(text string, limit int, filename string) (clipped string, truncated bool) {
+	if limit <= 0 || len(text) <= limit {
+		return text, false
+	}
+
+	note := fmt.Sprintf("\n[... truncated to fit the clipboard; the full export is in %s]\n", filename)
+	keep := max(limit-len(note)-len("```\n"), 0)
+	cut := text[:keep]
+	if i := strings.LastIndex(cut, "\n"); i >= 0 {
+		cut = cut[:i+1]
+	} else {
+		cut = ""
+	}
+	// Close a markdown code block the cut left open
+	if strings.Count(cut, "```")%2 == 1 {
+		cut += "```\n"
+	}
+	return cut + note, true
+}
+
+// formatSize renders a byte count for the status bar ("1.0 MB")
+func formatSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClipText(t *testing.T) {
+	var export strings.Builder
+	export.WriteString("# Docker Container Logs Summary\n\n## Container: web\n```\n")
+	for i := 0; i < 200; i++ {
+		export.WriteString("[2025-03-04 12:00:00] GET /health 200\n")
+	}
+	export.WriteString("```\n")
+	text := export.String()
+
+	if got, truncated := clipText(text, 0, "/tmp/full.md"); truncated || got != text {
+		t.Error("limit 0 changed the text")
+	}
+	if got, truncated := clipText(text, len(text), "/tmp/full.md"); truncated || got != text {
+		t.Error("text within the limit changed")
+	}
+
+	got, truncated := clipText(text, 1000, "/tmp/full.md")
+	if !truncated {
+		t.Fatal("text over the limit not truncated")
+	}
+	if len(got) > 1000 {
+		t.Errorf("clipped text has %d bytes, over the 1000 byte limit", len(got))
+	}
+	if !strings.Contains(got, "/tmp/full.md") {
+		t.Error("clipped text doesn't point to the full export")
+	}
+	if strings.Count(got, "```")%2 != 0 {
+		t.Errorf("clipped text leaves a code block open:\n%s", got)
+	}
+	body := got[:strings.Index(got, "\n[... truncated")]
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\n"), "\n")[4:] {
+		if line != "[2025-03-04 12:00:00] GET /health 200" && line != "```" {
+			t.Errorf("clipped text cuts a line: %q", line)
+		}
+	}
+}
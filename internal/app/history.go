@@ -0,0 +1,110 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxHistoryEntries caps the remembered queries of each search mode
+const maxHistoryEntries = 100
+
+// Search modes with separate histories
+const (
+	historySearch   = "search"
+	historyAISearch = "ai_search"
+	historyChat     = "chat"
+)
+
+// searchHistory remembers recent queries per search mode, persisted to
+// ~/.colog/history so they survive restarts. Up/Down in the search input walk it.
+type searchHistory struct {
+	path    string              // empty when the home directory is unknown (memory only)
+	entries map[string][]string // mode → queries, oldest first, no duplicates
+	cursor  int                 // position while cycling; len(entries) when not cycling
+}
+
+// loadSearchHistory reads ~/.colog/history; a missing or unreadable file starts empty
+func loadSearchHistory() *searchHistory {
+	h := &searchHistory{entries: make(map[string][]string)}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return h
+	}
+	h.path = filepath.Join(home, ".colog", "history")
+
+	if data, err := os.ReadFile(h.path); err == nil {
+		json.Unmarshal(data, &h.entries)
+		if h.entries == nil {
+			h.entries = make(map[string][]string) // the file held "null"
+		}
+	}
+	return h
+}
+
+// Add records query as the newest entry of mode, moving an earlier duplicate to
+// the end, and saves the history
+func (h *searchHistory) Add(mode, query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	entries := h.entries[mode]
+	for i, entry := range entries {
+		if entry == query {
+			entries = append(entries[:i], entries[i+1:]...)
+			break
+		}
+	}
+	entries = append(entries, query)
+	if len(entries) > maxHistoryEntries {
+		entries = entries[len(entries)-maxHistoryEntries:]
+	}
+	h.entries[mode] = entries
+	h.Reset(mode)
+	return h.save()
+}
+
+// Reset stops cycling, so the next Previous returns the newest entry
+func (h *searchHistory) Reset(mode string) {
+	h.cursor = len(h.entries[mode])
+}
+
+// Previous steps back to an older query of mode; false when there is none
+func (h *searchHistory) Previous(mode string) (string, bool) {
+	if h.cursor <= 0 || len(h.entries[mode]) == 0 {
+		return "", false
+	}
+	h.cursor--
+	return h.entries[mode][h.cursor], true
+}
+
+// Next steps forward to a newer query of mode. Stepping past the newest returns
+// an empty query, like a shell; false when not cycling.
+func (h *searchHistory) Next(mode string) (string, bool) {
+	entries := h.entries[mode]
+	if h.cursor >= len(entries) {
+		return "", false
+	}
+	h.cursor++
+	if h.cursor == len(entries) {
+		return "", true
+	}
+	return entries[h.cursor], true
+}
+
+func (h *searchHistory) save() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(h.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, data, 0600)
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSearchHistory(t *testing.T) {
+	h := &searchHistory{
+		path:    filepath.Join(t.TempDir(), ".colog", "history"),
+		entries: make(map[string][]string),
+	}
+	for _, query := range []string{"timeout", "refused", "timeout", " ", "panic"} {
+		if err := h.Add(historySearch, query); err != nil {
+			t.Fatalf("Add(%q): %v", query, err)
+		}
+	}
+	h.Add(historyChat, "why did web restart?")
+
+	// Duplicates move to the end, blanks are skipped, modes are separate
+	want := []string{"refused", "timeout", "panic"}
+	if got := h.entries[historySearch]; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("entries = %v, want %v", got, want)
+	}
+
+	h.Reset(historySearch)
+	for _, want := range []string{"panic", "timeout", "refused"} {
+		if got, ok := h.Previous(historySearch); !ok || got != want {
+			t.Errorf("Previous = %q, %v; want %q", got, ok, want)
+		}
+	}
+	if _, ok := h.Previous(historySearch); ok {
+		t.Error("Previous past the oldest entry should report false")
+	}
+	if got, _ := h.Next(historySearch); got != "timeout" {
+		t.Errorf("Next = %q, want timeout", got)
+	}
+
+	// The saved file is read back by a fresh history
+	t.Setenv("HOME", filepath.Dir(filepath.Dir(h.path)))
+	reloaded := loadSearchHistory()
+	if got := reloaded.entries[historyChat]; len(got) != 1 || got[0] != "why did web restart?" {
+		t.Errorf("reloaded chat history = %v", got)
+	}
+}
+
+func TestSearchHistoryCap(t *testing.T) {
+	h := &searchHistory{entries: make(map[string][]string)}
+	for i := 0; i < maxHistoryEntries+10; i++ {
+		h.Add(historySearch, string(rune('a'+i%26))+string(rune('0'+i/26)))
+	}
+	if got := len(h.entries[historySearch]); got != maxHistoryEntries {
+		t.Errorf("kept %d entries, want %d", got, maxHistoryEntries)
+	}
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+// defaultMaxPanes is how many containers the grid takes before colog asks which
+// ones to watch, unless SetMaxPanes changes it
+const defaultMaxPanes = 12
+
+// selectContainers lists the containers on out and reads which ones to watch from
+// in, like the Docker endpoint selection: numbers and ranges ("1,3,5-8"), "all", or
+// nothing for the first limit containers. Invalid input asks again; end of input
+// takes the default.
+func selectContainers(containers []docker.Container, limit int, in io.Reader, out io.Writer) []docker.Container {
+	fmt.Fprintf(out, "\n%d running containers found (more than %d panes):\n", len(containers), limit)
+	fmt.Fprintln(out, "═══════════════════════════════════════════════════════════════")
+	for i, container := range containers {
+		fmt.Fprintf(out, "%3d. %s\n", i+1, container.DisplayName())
+		fmt.Fprintf(out, "     %s - %s\n", container.Image, container.Status)
+	}
+	fmt.Fprintln(out)
+
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "Select containers to watch (e.g. 1,3,5-8 or all) [default: 1-%d]: ", limit)
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return containers[:limit]
+		}
+		input := strings.TrimSpace(scanner.Text())
+		if input == "" {
+			return containers[:limit]
+		}
+		indexes, err := parseSelection(input, len(containers))
+		if err != nil {
+			fmt.Fprintf(out, "%v\n", err)
+			continue
+		}
+		selected := make([]docker.Container, len(indexes))
+		for i, index := range indexes {
+			selected[i] = containers[index]
+		}
+		return selected
+	}
+}
+
+// parseSelection turns "1,3,5-8" or "all" into 0-based indexes below n, in list
+// order and without duplicates
+func parseSelection(input string, n int) ([]int, error) {
+	chosen := make([]bool, n)
+	if strings.EqualFold(input, "all") {
+		for i := range chosen {
+			chosen[i] = true
+		}
+	} else {
+		for _, part := range strings.Split(input, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			first, last, isRange := strings.Cut(part, "-")
+			from, err := strconv.Atoi(strings.TrimSpace(first))
+			to := from
+			if err == nil && isRange {
+				to, err = strconv.Atoi(strings.TrimSpace(last))
+			}
+			if err != nil || from < 1 || to > n || from > to {
+				return nil, fmt.Errorf("invalid selection %q: use numbers from 1 to %d", part, n)
+			}
+			for i := from; i <= to; i++ {
+				chosen[i-1] = true
+			}
+		}
+	}
+
+	var indexes []int
+	for i, ok := range chosen {
+		if ok {
+			indexes = append(indexes, i)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, fmt.Errorf("no containers selected")
+	}
+	return indexes, nil
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+func TestParseSelection(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"1,3,5-7", "[0 2 4 5 6]"},
+		{"4, 2 ,2", "[1 3]"},
+		{"all", "[0 1 2 3 4 5 6 7]"},
+		{"8-8", "[7]"},
+	}
+	for _, c := range cases {
+		got, err := parseSelection(c.input, 8)
+		if err != nil || fmt.Sprint(got) != c.want {
+			t.Errorf("parseSelection(%q) = %v, %v; want %s", c.input, got, err, c.want)
+		}
+	}
+
+	for _, input := range []string{"0", "9", "3-1", "x", ",", "1-"} {
+		if got, err := parseSelection(input, 8); err == nil {
+			t.Errorf("parseSelection(%q) = %v, want an error", input, got)
+		}
+	}
+}
+
+func TestSelectContainers(t *testing.T) {
+	var containers []docker.Container
+	for i := 1; i <= 5; i++ {
+		containers = append(containers, docker.Container{ID: fmt.Sprint(i), Name: fmt.Sprintf("c%d", i)})
+	}
+	ids := func(selected []docker.Container) string {
+		var names []string
+		for _, container := range selected {
+			names = append(names, container.ID)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := ids(selectContainers(containers, 3, strings.NewReader("\n"), io.Discard)); got != "1,2,3" {
+		t.Errorf("empty input selected %s, want the first 3", got)
+	}
+	if got := ids(selectContainers(containers, 3, strings.NewReader(""), io.Discard)); got != "1,2,3" {
+		t.Errorf("end of input selected %s, want the first 3", got)
+	}
+	if got := ids(selectContainers(containers, 3, strings.NewReader("7\n2,4-5\n"), io.Discard)); got != "2,4,5" {
+		t.Errorf("selected %s after an invalid answer, want 2,4,5", got)
+	}
+}
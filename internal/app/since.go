@@ -0,0 +1,39 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// parseSince reads where to start a pane's stream from: a duration back from now
+// ("15m", "2h30m"), a time of day ("14:05", "14:05:30", today or, when that's still
+// ahead, yesterday), or an RFC 3339 timestamp. An empty input is the zero time.
+func parseSince(input string, now time.Time) (time.Time, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(input); err == nil {
+		if d <= 0 {
+			return time.Time{}, fmt.Errorf("duration must be positive: %s", input)
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, input); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"15:04:05", "15:04"} {
+		clock, err := time.ParseInLocation(layout, input, now.Location())
+		if err != nil {
+			continue
+		}
+		y, m, d := now.Date()
+		t := time.Date(y, m, d, clock.Hour(), clock.Minute(), clock.Second(), 0, now.Location())
+		if t.After(now) {
+			t = t.AddDate(0, 0, -1)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use a duration (15m), a time of day (14:05) or RFC 3339", input)
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		input string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"15m", now.Add(-15 * time.Minute)},
+		{" 2h30m ", now.Add(-150 * time.Minute)},
+		{"11:05", time.Date(2025, 3, 4, 11, 5, 0, 0, time.UTC)},
+		{"09:30:15", time.Date(2025, 3, 4, 9, 30, 15, 0, time.UTC)},
+		{"14:00", time.Date(2025, 3, 3, 14, 0, 0, 0, time.UTC)}, // still ahead today
+		{"2025-03-01T08:00:00Z", time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		got, err := parseSince(c.input, now)
+		if err != nil || !got.Equal(c.want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", c.input, got, err, c.want)
+		}
+	}
+
+	for _, input := range []string{"yesterday", "-5m", "25:00"} {
+		if got, err := parseSince(input, now); err == nil {
+			t.Errorf("parseSince(%q) = %v, want an error", input, got)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Profile is a named startup setup from ~/.colog/profiles.json, e.g.
+//
+//	{
+//	  "triage": {"args": ["--simple", "--buffer", "500"], "env": {"OPENAI_MODEL_CHAT": "gpt-4o"}}
+//	}
+//
+// Args are command-line flags as they would be typed; Env sets environment
+// variables such as OPENAI_MODEL_CHAT or COLOG_NO_COLOR.
+type Profile struct {
+	Args []string          `json:"args"`
+	Env  map[string]string `json:"env"`
+}
+
+// ProfilesPath is where profiles are read from: COLOG_PROFILES when set,
+// otherwise ~/.colog/profiles.json
+func ProfilesPath() (string, error) {
+	if path := os.Getenv("COLOG_PROFILES"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".colog", "profiles.json"), nil
+}
+
+// LoadProfiles reads the profiles file at path, keyed by profile name
+func LoadProfiles(path string) (map[string]Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var profiles map[string]Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return profiles, nil
+}
+
+// LoadProfile reads the profile called name from the profiles file
+func LoadProfile(name string) (Profile, error) {
+	path, err := ProfilesPath()
+	if err != nil {
+		return Profile{}, err
+	}
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		return Profile{}, err
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Profile{}, fmt.Errorf("no profile %q in %s (have: %s)", name, path, strings.Join(names, ", "))
+	}
+	return profile, nil
+}
+
+// Apply returns the effective command line: the profile's flags followed by
+// args, leaving out profile flags that args give themselves so the command line
+// always wins. Profile variables are set unless already in the environment.
+func (p Profile) Apply(args []string) []string {
+	given := make(map[string]bool)
+	for _, arg := range args {
+		if name := flagName(arg); name != "" {
+			given[name] = true
+		}
+	}
+
+	var merged []string
+	for i := 0; i < len(p.Args); i++ {
+		arg := p.Args[i]
+		name := flagName(arg)
+		// A flag's value is the next word unless given as --name=value
+		hasValue := name != "" && !strings.Contains(arg, "=") && i+1 < len(p.Args) && !strings.HasPrefix(p.Args[i+1], "-")
+		if name != "" && given[name] {
+			if hasValue {
+				i++
+			}
+			continue
+		}
+		merged = append(merged, arg)
+		if hasValue {
+			merged = append(merged, p.Args[i+1])
+			i++
+		}
+	}
+
+	for key, value := range p.Env {
+		if _, ok := os.LookupEnv(key); !ok {
+			os.Setenv(key, value)
+		}
+	}
+	return append(merged, args...)
+}
+
+// flagName returns the name of a --flag or --flag=value argument; empty for values
+func flagName(arg string) string {
+	if !strings.HasPrefix(arg, "-") {
+		return ""
+	}
+	name, _, _ := strings.Cut(arg, "=")
+	return name
+}
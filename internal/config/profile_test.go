@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProfileApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles.json")
+	data := `{"triage": {"args": ["--simple", "--buffer", "500", "--last=10", "--full-ids"], "env": {"COLOG_TEST_MODEL": "gpt-4o", "COLOG_TEST_KEPT": "profile"}}}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("COLOG_PROFILES", path)
+	t.Setenv("COLOG_TEST_KEPT", "environment")
+	os.Unsetenv("COLOG_TEST_MODEL")
+	defer os.Unsetenv("COLOG_TEST_MODEL")
+
+	profile, err := LoadProfile("triage")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Command-line flags replace the profile's, with or without =value
+	got := profile.Apply([]string{"--profile", "triage", "--buffer=100", "--last", "3"})
+	want := []string{"--simple", "--full-ids", "--profile", "triage", "--buffer=100", "--last", "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply = %q, want %q", got, want)
+	}
+	if got := os.Getenv("COLOG_TEST_MODEL"); got != "gpt-4o" {
+		t.Errorf("COLOG_TEST_MODEL = %q, want the profile's gpt-4o", got)
+	}
+	if got := os.Getenv("COLOG_TEST_KEPT"); got != "environment" {
+		t.Errorf("COLOG_TEST_KEPT = %q, want the environment's value kept", got)
+	}
+
+	if _, err := LoadProfile("frontend"); err == nil || !strings.Contains(err.Error(), "have: triage") {
+		t.Errorf("LoadProfile(frontend) error = %v, want the known profiles listed", err)
+	}
+}
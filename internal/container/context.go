@@ -3,7 +3,10 @@ package container
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -22,30 +25,122 @@ type ContainerContext struct {
 	mu            sync.RWMutex
 	ctx           context.Context
 	cancel        context.CancelFunc
-	streamStarted bool
+	streamCancel  context.CancelFunc // stops the current stream; nil when not streaming
+	source        docker.LogSource // where the lines come from: Docker or a replayed recording
+	lastFocused   time.Time // when the pane was last selected, for idle stream shutdown
 	app           *tview.Application // Reference to app for thread-safe UI updates
+	minLevel      docker.LogLevel    // lines below this level are hidden in the view
+	levelMatcher  *docker.LevelMatcher
+	followTail    bool // whether new lines scroll the view to the bottom
+	newLines      int  // lines appended while the view was scrolled away from the bottom
+	frozen        bool     // whether view updates are held back for all panes ('f')
+	paused        bool     // whether view updates are held back for this pane ('p')
+	pendingLines  []string // lines held back while frozen or paused
+	unflushed     []string // lines waiting for the next frame to be written to the view
+	replaceText   string   // re-rendered view text waiting for the next frame
+	replaceView   bool     // whether replaceText is pending
+	renderer      *frameRenderer // draws queued view updates; nil draws each update on its own
+	staleView     bool     // whether a re-render was skipped while frozen or paused
+	errorCount    int      // error-level lines seen since the stream started
+	sparkline     string   // recent log rate shown in the title, empty when disabled
+	truncate      bool     // whether long lines are cut to fit the pane (unless selected)
+	maxLineWidth  int      // upper bound for truncated messages; 0 = pane width only
+	renderedWidth int      // pane width the current view was rendered for
+	lastLines     int      // show only this many of the most recent lines; 0 = all
+	logFiles      []string // in-container log files tailed through docker exec
+	bufferSize    int      // how many recent entries LogBuffer keeps
+	health        string   // healthcheck status, empty without a healthcheck
+	uptime        string   // "up 3h12m" or "exited 5m ago", empty until the state is known
+	note          string   // the user's note on this container, shown in the title
+	noColor       bool     // whether messages are shown without their level colors
+	markStderr    bool     // whether stderr lines get a dark red background to stand out
+	titleWidth    int      // container name characters shown in the title; 0 = all
+	fullID        bool     // whether the title shows the full container ID
+	collapse      bool     // whether identical consecutive lines collapse into one with a count
+	replaceLast   string   // line replacing the view's last line in the next frame
+	replaceLastOK bool     // whether replaceLast is pending
+	since         time.Time // the stream starts with lines logged since then; zero = the last 100 lines
+	startSlots    chan struct{} // shared limit on streams being opened at once; nil = no limit
+	streamState   docker.StreamState // what the log stream is doing, see CheckStall
+	lastActivity  time.Time          // when the stream started, delivered a line or was last probed
+	reconnect     bool               // whether streams that end while the container runs are reopened
+	retryDelay    time.Duration      // wait before the next reconnect, reset when a line arrives
 }
 
-// NewContainerContext creates a new container context
-func NewContainerContext(container docker.Container, color tcell.Color, app *tview.Application) *ContainerContext {
+// logFileStreamer tails log files inside containers; DockerService does through
+// docker exec
+type logFileStreamer interface {
+	StreamLogFiles(ctx context.Context, containerID string, paths []string, tail int, logCh chan<- docker.LogEntry) error
+}
+
+// containerStater reads a container's state; DockerService does from the daemon
+type containerStater interface {
+	State(ctx context.Context, containerID string) (docker.ContainerState, error)
+}
+
+// Reconnect backoff: the first retry waits reconnectDelay and each one after it
+// twice as long, up to maxReconnectDelay, until the stream delivers a line again
+var (
+	reconnectDelay    = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// DefaultBufferSize is how many recent entries each container keeps by default
+const DefaultBufferSize = 50
+
+// DefaultTitleWidth is how many characters of a container name pane titles show
+// before cutting it with "..."
+const DefaultTitleWidth = 25
+
+// noteTitleWidth is how many characters of a pane's note its title shows
+const noteTitleWidth = 30
+
+// DefaultStreamStarts is how many log streams are opened at once, unless the
+// manager's SetStreamStartLimit changes it
+const DefaultStreamStarts = 8
+
+// maxPendingLines caps the lines held back while frozen or paused, matching the view's line limit
+const maxPendingLines = 1000
+
+// NewContainerContext creates a new container context keeping the last bufferSize
+// log entries (DefaultBufferSize when bufferSize <= 0)
+func NewContainerContext(container docker.Container, color tcell.Color, app *tview.Application, bufferSize int) *ContainerContext {
 	ctx, cancel := context.WithCancel(context.Background())
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
 	
 	return &ContainerContext{
 		Container:  container,
-		LogBuffer:  make([]docker.LogEntry, 0, 50), // Keep last 50 entries
+		LogBuffer:  make([]docker.LogEntry, 0, bufferSize),
+		bufferSize: bufferSize,
 		LogChannel: make(chan docker.LogEntry, 100),
 		Color:      color,
 		IsSelected: false,
 		ctx:        ctx,
 		cancel:     cancel,
 		app:        app,
+		minLevel:   docker.LevelDebug,
+		levelMatcher: docker.NewLevelMatcher(),
+		followTail:   true,
+		titleWidth:   DefaultTitleWidth,
+		collapse:     true,
 	}
 }
 
 // Initialize sets up the log view and starts log streaming
-func (cc *ContainerContext) Initialize(dockerService *docker.DockerService) error {
+func (cc *ContainerContext) Initialize(source docker.LogSource) error {
+	cc.source = source
 	cc.setupLogView()
-	return cc.startLogStreaming(dockerService)
+	return cc.StartStreaming()
+}
+
+// InitializeLazy sets up the log view without streaming; StartStreaming begins the
+// stream when the pane is first selected
+func (cc *ContainerContext) InitializeLazy(source docker.LogSource) {
+	cc.source = source
+	cc.setupLogView()
+	fmt.Fprintf(cc.LogView, "[gray:#000000]Streaming paused - select this pane to start[white:#000000]\n")
 }
 
 // setupLogView creates and configures the tview.TextView for this container
@@ -59,84 +154,988 @@ func (cc *ContainerContext) setupLogView() {
 	trueBlack := tcell.NewRGBColor(0, 0, 0)
 	cc.LogView.SetBackgroundColor(trueBlack)
 
-	title := fmt.Sprintf(" %s ", cc.Container.Name)
-	if len(title) > 30 {
-		title = title[:27] + "... "
-	}
-
 	cc.LogView.SetBorder(true).
-		SetTitle(title).
+		SetTitle(cc.title()).
 		SetTitleAlign(tview.AlignLeft).
 		SetBorderColor(cc.Color)
+	cc.LogView.SetInputCapture(cc.handleScrollKey)
 
 	// Display container info
-	cc.LogView.SetText(fmt.Sprintf("[%s:#000000]Container: %s[white:#000000]\n[%s:#000000]Image: %s[white:#000000]\n[%s:#000000]Status: %s[white:#000000]\n[gray:#000000]────────────────────────────────[white:#000000]\n",
-		cc.colorToTviewColor(cc.Color), cc.Container.Name,
-		cc.colorToTviewColor(cc.Color), cc.Container.Image,
-		cc.colorToTviewColor(cc.Color), cc.Container.Status))
+	cc.LogView.SetText(cc.headerText())
+}
+
+// title renders the pane title, including the level threshold and scroll-lock state
+func (cc *ContainerContext) title() string {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+
+	name := truncateName(cc.Container.DisplayName(), cc.titleWidth)
+	if cc.fullID {
+		name += " " + cc.Container.ID
+	}
+	title := fmt.Sprintf(" %s ", tview.Escape(name))
+	if dot := healthDot(cc.health); dot != "" {
+		title += dot + " "
+	}
+	if cc.uptime != "" {
+		title += "[gray]" + tview.Escape(cc.uptime) + "[-] "
+	}
+	if cc.note != "" {
+		title += "[yellow]" + tview.Escape("✎ "+truncateName(cc.note, noteTitleWidth)) + "[-] "
+	}
+	if cc.minLevel > docker.LevelDebug {
+		title += tview.Escape(fmt.Sprintf("[%s+] ", cc.minLevel))
+	}
+	if cc.paused {
+		title += tview.Escape("[PAUSED] ")
+	}
+	if cc.markStderr {
+		title += "[red]" + tview.Escape("[stderr]") + "[-] "
+	}
+	if cc.streamState == docker.StreamStalled {
+		title += "[red]" + tview.Escape("[STALLED]") + "[-] "
+	}
+	if !cc.since.IsZero() {
+		title += tview.Escape(fmt.Sprintf("[since %s] ", formatSince(cc.since, time.Now())))
+	}
+	if !cc.followTail {
+		title += tview.Escape(fmt.Sprintf("[scroll-lock: %d new lines below] ", cc.newLines))
+	}
+	if cc.sparkline != "" {
+		title += cc.sparkline + " "
+	}
+	return title
+}
+
+// formatSince shows a stream's start time, with the date when it isn't today
+func formatSince(since, now time.Time) string {
+	since = since.Local()
+	if y, m, d := now.Local().Date(); since.Year() == y && since.Month() == m && since.Day() == d {
+		return since.Format("15:04:05")
+	}
+	return since.Format("Jan 2 15:04:05")
+}
+
+// uptimeLabel describes how long a container has been running, or how long ago a
+// stopped one exited; empty when Docker didn't report the times
+func uptimeLabel(state docker.ContainerState, now time.Time) string {
+	switch {
+	case state.Running && !state.StartedAt.IsZero():
+		return "up " + formatDuration(now.Sub(state.StartedAt))
+	case !state.Running && !state.FinishedAt.IsZero():
+		return "exited " + formatDuration(now.Sub(state.FinishedAt)) + " ago"
+	}
+	return ""
+}
+
+// formatDuration shows a duration in its two largest units: "45s", "12m", "3h12m",
+// "2d4h"
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return fmt.Sprintf("%dd%dh", int(d/(24*time.Hour)), int(d%(24*time.Hour)/time.Hour))
+}
+
+// truncateName cuts a name to width characters (not bytes, so multibyte names stay
+// intact) followed by "..."; width 0 keeps the whole name
+func truncateName(name string, width int) string {
+	runes := []rune(name)
+	if width <= 0 || len(runes) <= width {
+		return name
+	}
+	return string(runes[:width]) + "..."
+}
+
+// healthDot renders a healthcheck status as a colored dot for the pane title
+func healthDot(health string) string {
+	switch health {
+	case "healthy":
+		return "[green]●[-]"
+	case "unhealthy":
+		return "[red]●[-]"
+	case "starting":
+		return "[yellow]●[-]"
+	}
+	return ""
 }
 
-// startLogStreaming begins streaming logs for this container
-func (cc *ContainerContext) startLogStreaming(dockerService *docker.DockerService) error {
-	if cc.streamStarted {
+// handleScrollKey pauses following the tail when the user scrolls up and resumes it
+// when they jump to the end (End/G) or scroll back down to the bottom
+func (cc *ContainerContext) handleScrollKey(event *tcell.EventKey) *tcell.EventKey {
+	switch event.Key() {
+	case tcell.KeyUp, tcell.KeyPgUp, tcell.KeyCtrlB, tcell.KeyHome:
+		cc.setFollowTail(false)
+	case tcell.KeyEnd:
+		cc.setFollowTail(true)
+	case tcell.KeyDown, tcell.KeyPgDn, tcell.KeyCtrlF:
+		// Check once the text view has applied the scroll
+		cc.app.QueueUpdateDraw(cc.resumeIfAtBottom)
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'g':
+			cc.setFollowTail(false)
+		case 'G':
+			cc.setFollowTail(true)
+		}
+	}
+	return event
+}
+
+// resumeIfAtBottom resumes following the tail once the last line is visible again
+func (cc *ContainerContext) resumeIfAtBottom() {
+	if cc.IsFollowing() {
+		return
+	}
+	row, _ := cc.LogView.GetScrollOffset()
+	_, _, _, height := cc.LogView.GetInnerRect()
+	if row+height >= cc.LogView.GetWrappedLineCount() {
+		cc.setFollowTail(true)
+	}
+}
+
+// setFollowTail switches tail following on or off. Must be called from the UI goroutine.
+func (cc *ContainerContext) setFollowTail(follow bool) {
+	cc.mu.Lock()
+	changed := cc.followTail != follow
+	cc.followTail = follow
+	if follow {
+		cc.newLines = 0
+	}
+	cc.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	cc.LogView.SetTitle(cc.title())
+	if follow {
+		cc.LogView.ScrollToEnd()
+	}
+}
+
+// IsFollowing reports whether the view scrolls to new lines as they arrive
+func (cc *ContainerContext) IsFollowing() bool {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.followTail
+}
+
+// headerText renders the container info shown at the top of the log view
+func (cc *ContainerContext) headerText() string {
+	return fmt.Sprintf("[%s:#000000]Container: %s[white:#000000]\n[%s:#000000]Image: %s[white:#000000]\n[%s:#000000]Status: %s[white:#000000]\n[gray:#000000]────────────────────────────────[white:#000000]\n",
+		cc.colorToTviewColor(cc.Color), tview.Escape(cc.Container.DisplayName()),
+		cc.colorToTviewColor(cc.Color), tview.Escape(cc.Container.Image),
+		cc.colorToTviewColor(cc.Color), tview.Escape(cc.Container.Status))
+}
+
+// StartStreaming begins streaming logs for this container if it isn't already.
+// A restarted stream skips lines that are already buffered.
+func (cc *ContainerContext) StartStreaming() error {
+	cc.mu.Lock()
+	if cc.streamCancel != nil {
+		cc.mu.Unlock()
 		return nil
 	}
+
+	ctx, cancel := context.WithCancel(cc.ctx)
+	cc.streamCancel = cancel
+
+	// The stream closes its channel when it ends, so whatever ends a stream gives
+	// the pane a fresh one
+	var resumeAfter time.Time
+	if len(cc.LogBuffer) > 0 {
+		resumeAfter = cc.LogBuffer[len(cc.LogBuffer)-1].Timestamp
+	}
+	logCh := cc.LogChannel
+	since := cc.since
+	cc.streamState = docker.StreamActive
+	cc.lastActivity = time.Now()
+	cc.mu.Unlock()
 	
-	cc.streamStarted = true
-	
+	// A restarted stream picks up after the buffered lines, which the last 100 cover
+	opts := docker.DefaultLogStreamOptions()
+	if !since.IsZero() && resumeAfter.IsZero() {
+		opts.Since = since
+		opts.Tail = -1
+	}
 	go func() {
-		err := dockerService.StreamLogs(cc.ctx, cc.Container.ID, cc.LogChannel)
+		if !cc.acquireStartSlot(ctx) {
+			return
+		}
+		entries, err := cc.source.Stream(ctx, cc.Container.ID, opts)
+		cc.releaseStartSlot()
 		if err != nil {
-			cc.AppendLog(fmt.Sprintf("[red]Error streaming logs: %v[white]", err))
+			cc.AppendLog(fmt.Sprintf("[red]Error streaming logs: %s[white]", tview.Escape(err.Error())))
+			if cc.reconnect {
+				close(logCh) // ends processLogs, which retries
+			}
+			return
+		}
+		// The pane's channel outlives the stream's, so simple mode can keep reading it
+		defer close(logCh)
+		for entry := range entries {
+			select {
+			case logCh <- entry:
+			case <-ctx.Done():
+				return
+			}
 		}
 	}()
 	
-	// Start log processing goroutine
-	go cc.processLogs()
+	// Start log processing goroutine; the channel closing without ctx ending is EOF
+	go func() {
+		cc.processLogs(ctx, logCh, resumeAfter)
+		if ctx.Err() == nil {
+			cc.setStreamState(docker.StreamEnded)
+			if cc.reconnect {
+				cc.reconnectStream(ctx)
+			}
+		}
+	}()
+
+	if len(cc.logFiles) > 0 {
+		cc.streamLogFiles(ctx, !resumeAfter.IsZero())
+	}
 	
 	return nil
 }
 
+// FollowSince reloads the pane from the container's logs since the given time (like
+// docker logs --since), replacing the buffered lines, and keeps following from there.
+// A zero since goes back to the last 100 lines. The title shows the window.
+func (cc *ContainerContext) FollowSince(since time.Time) error {
+	cc.mu.Lock()
+	cancel := cc.streamCancel
+	cc.streamCancel = nil
+	cc.since = since
+	cc.LogBuffer = nil
+	cc.errorCount = 0
+	cc.LogChannel = make(chan docker.LogEntry, 100)
+	cc.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	cc.rerender()
+	cc.refreshTitle()
+	return cc.StartStreaming()
+}
+
+// Since returns when the pane's stream starts (zero for the last 100 lines)
+func (cc *ContainerContext) Since() time.Time {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.since
+}
+
+// CheckStall checks a stream that delivered nothing for idle: when the container did
+// log lines the stream missed, the stream is marked stalled ([STALLED] in the title)
+// and, with restart, reopened after the buffered lines; otherwise the container is
+// just quiet. Streams that were stopped or ended aren't probed. It returns the state.
+func (cc *ContainerContext) CheckStall(ctx context.Context, idle time.Duration, restart bool) docker.StreamState {
+	cc.mu.RLock()
+	state := cc.streamState
+	check := cc.streamCancel != nil && state != docker.StreamEnded && time.Since(cc.lastActivity) >= idle
+	lastSeen := cc.lastActivity
+	if n := len(cc.LogBuffer); n > 0 {
+		lastSeen = cc.LogBuffer[n-1].Timestamp
+	}
+	cc.mu.RUnlock()
+	if !check {
+		return state
+	}
+
+	reader, ok := cc.source.(docker.LogReader)
+	if !ok {
+		return state
+	}
+	probed, err := docker.ProbeStall(ctx, reader, cc.Container.ID, lastSeen)
+	if err != nil {
+		return state
+	}
+	cc.mu.Lock()
+	cc.lastActivity = time.Now() // probe again only after another idle period
+	cc.mu.Unlock()
+	cc.setStreamState(probed)
+
+	if probed == docker.StreamStalled && restart {
+		cc.restartStream()
+		cc.AppendLog("[yellow:#000000]Log stream stalled - restarted[white:#000000]")
+	}
+	return probed
+}
+
+// StreamState returns what the log stream was doing when last seen: active, quiet
+// or stalled (as found by CheckStall), or ended
+func (cc *ContainerContext) StreamState() docker.StreamState {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.streamState
+}
+
+func (cc *ContainerContext) setStreamState(state docker.StreamState) {
+	cc.mu.Lock()
+	changed := (cc.streamState == docker.StreamStalled) != (state == docker.StreamStalled)
+	cc.streamState = state
+	cc.mu.Unlock()
+	if changed {
+		cc.refreshTitle()
+	}
+}
+
+// restartStream reopens the log stream, picking up after the buffered lines
+func (cc *ContainerContext) restartStream() {
+	cc.mu.Lock()
+	cancel := cc.streamCancel
+	cc.streamCancel = nil
+	cc.LogChannel = make(chan docker.LogEntry, 100) // the old stream still closes its channel
+	cc.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	cc.StartStreaming()
+}
+
+// reconnectStream reopens a stream that ended while its container still runs, as
+// when the Docker daemon restarts. While the daemon can't be reached it retries with
+// backoff, noting "[reconnecting…]" in the pane; a stopped container's stream stays
+// ended. It gives up when ctx ends.
+func (cc *ContainerContext) reconnectStream(ctx context.Context) {
+	states, ok := cc.source.(containerStater)
+	if !ok {
+		return
+	}
+	for notified := false; ; notified = true {
+		cc.mu.Lock()
+		delay := max(cc.retryDelay, reconnectDelay)
+		cc.retryDelay = min(delay*2, maxReconnectDelay)
+		cc.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		state, err := states.State(ctx, cc.Container.ID)
+		if err == nil {
+			if state.Running {
+				cc.restartStream()
+			}
+			return
+		}
+		if !notified {
+			cc.AppendLog("[yellow:#000000][reconnecting…][white:#000000]")
+		}
+	}
+}
+
+// acquireStartSlot waits until fewer than the manager's limit of streams are being
+// opened, so launching with many containers doesn't hit the daemon with all of them at
+// once. It reports false when ctx ends first.
+func (cc *ContainerContext) acquireStartSlot(ctx context.Context) bool {
+	if cc.startSlots == nil {
+		return true
+	}
+	select {
+	case cc.startSlots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// releaseStartSlot lets the next queued stream open once this one is established
+func (cc *ContainerContext) releaseStartSlot() {
+	if cc.startSlots != nil {
+		<-cc.startSlots
+	}
+}
+
+// streamLogFiles merges the tails of the container's log files into the view. File
+// lines carry no timestamps of their own, so a restarted stream only shows new lines.
+func (cc *ContainerContext) streamLogFiles(ctx context.Context, restarted bool) {
+	tail := 10
+	if restarted {
+		tail = 0
+	}
+	files, ok := cc.source.(logFileStreamer)
+	if !ok {
+		return
+	}
+	fileCh := make(chan docker.LogEntry, 100)
+	go func() {
+		err := files.StreamLogFiles(ctx, cc.Container.ID, cc.logFiles, tail, fileCh)
+		if err != nil {
+			cc.AppendLog(fmt.Sprintf("[red]Error tailing log files: %v[white]", tview.Escape(err.Error())))
+		}
+	}()
+	go cc.processLogs(ctx, fileCh, time.Time{})
+}
+
+// StopStreaming stops the log stream, keeping the buffered lines and the view
+func (cc *ContainerContext) StopStreaming() {
+	cc.mu.Lock()
+	cancel := cc.streamCancel
+	cc.streamCancel = nil
+	if cancel != nil {
+		cc.LogChannel = make(chan docker.LogEntry, 100) // the stopped stream still closes its channel
+	}
+	cc.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	cc.AppendLog("[gray:#000000]Streaming paused (idle) - select this pane to resume[white:#000000]")
+}
+
+// IsStreaming reports whether a log stream is running
+func (cc *ContainerContext) IsStreaming() bool {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.streamCancel != nil
+}
+
+// MarkFocused records that the pane was just selected
+func (cc *ContainerContext) MarkFocused() {
+	cc.mu.Lock()
+	cc.lastFocused = time.Now()
+	cc.mu.Unlock()
+}
+
+// LastFocused returns when the pane was last selected
+func (cc *ContainerContext) LastFocused() time.Time {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.lastFocused
+}
+
 // processLogs handles incoming log entries
-func (cc *ContainerContext) processLogs() {
+func (cc *ContainerContext) processLogs(ctx context.Context, logCh <-chan docker.LogEntry, resumeAfter time.Time) {
 	for {
 		select {
-		case <-cc.ctx.Done():
+		case <-ctx.Done():
 			return
-		case entry, ok := <-cc.LogChannel:
+		case entry, ok := <-logCh:
 			if !ok {
 				return
 			}
+			if !resumeAfter.IsZero() && !entry.Timestamp.After(resumeAfter) {
+				continue
+			}
 			
-			// Add to buffer (keep last 50 entries)
+			// Add to buffer (keep the last bufferSize entries); a repeat of the last
+			// line only bumps its count
+			level := cc.levelMatcher.MatchEntry(entry)
 			cc.mu.Lock()
-			cc.LogBuffer = append(cc.LogBuffer, entry)
-			if len(cc.LogBuffer) > 50 {
-				cc.LogBuffer = cc.LogBuffer[1:]
+			cc.lastActivity = time.Now()
+			cc.retryDelay = 0
+			wasStalled := cc.streamState == docker.StreamStalled
+			cc.streamState = docker.StreamActive
+			repeated := cc.collapse && cc.isRepeatLocked(entry)
+			if repeated {
+				last := &cc.LogBuffer[len(cc.LogBuffer)-1]
+				last.Repeats = max(last.Repeats, 1) + 1
+				last.Timestamp = entry.Timestamp
+				entry = *last
+			} else {
+				cc.LogBuffer = append(cc.LogBuffer, entry)
+				if len(cc.LogBuffer) > cc.bufferSize {
+					cc.LogBuffer = cc.LogBuffer[len(cc.LogBuffer)-cc.bufferSize:]
+				}
+			}
+			if level == docker.LevelError {
+				cc.errorCount++
 			}
+			minLevel := cc.minLevel
 			cc.mu.Unlock()
+			if wasStalled {
+				cc.refreshTitle()
+			}
 			
-			// Format and display log entry
-			timestamp := entry.Timestamp.Format("15:04:05")
-			logLine := fmt.Sprintf("[gray:#000000]%s[white:#000000] %s", timestamp, entry.Message)
-			cc.AppendLog(logLine)
+			// Entries below the threshold stay buffered (for export) but aren't shown
+			if level < minLevel {
+				continue
+			}
+			if cc.LastLines() > 0 {
+				// A clamped view is rebuilt so the oldest shown line drops off
+				cc.rerender()
+				continue
+			}
+			if repeated {
+				cc.replaceLastLog(cc.renderLine(entry))
+				continue
+			}
+			cc.AppendLog(cc.renderLine(entry))
 		}
 	}
 }
 
+// isRepeatLocked reports whether entry repeats the last buffered line. Callers hold
+// cc.mu.
+func (cc *ContainerContext) isRepeatLocked(entry docker.LogEntry) bool {
+	if len(cc.LogBuffer) == 0 {
+		return false
+	}
+	last := cc.LogBuffer[len(cc.LogBuffer)-1]
+	return last.Message == entry.Message && last.Stream == entry.Stream && last.Source == entry.Source
+}
+
+// SetCollapseRepeats turns collapsing identical consecutive lines into one line
+// with an "(x42)" count on or off. Lines already collapsed stay collapsed.
+func (cc *ContainerContext) SetCollapseRepeats(enabled bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.collapse = enabled
+}
+
+// formatLogLine renders a log entry for display in the log view, colored by level
+// (stderr info lines are red too) unless noColor is set. The message is escaped so
+// container output can't inject color tags into the view.
+func formatLogLine(entry docker.LogEntry, level docker.LogLevel, noColor, markStderr bool) string {
+	timestamp := entry.Timestamp.Format("15:04:05")
+	color := levelColor(level)
+	if level == docker.LevelInfo && entry.Stream == "stderr" {
+		color = "red"
+	}
+	if color == "white" || noColor {
+		color = "white"
+	}
+	background := "#000000"
+	if markStderr && entry.Stream == "stderr" {
+		background = stderrBackground
+	}
+	return fmt.Sprintf("[gray:%s]%s[%s:%s] %s[white:#000000]", background, timestamp, color, background, tview.Escape(entry.Message))
+}
+
+// stderrBackground sets stderr lines apart in panes marking them (see SetMarkStderr)
+const stderrBackground = "#3A0000"
+
+// SetMarkStderr gives the pane's stderr lines a dark red background, so problems an
+// app reports on stderr stand out from its normal stdout output. The title shows
+// [stderr] while it's on.
+func (cc *ContainerContext) SetMarkStderr(enabled bool) {
+	cc.mu.Lock()
+	cc.markStderr = enabled
+	cc.mu.Unlock()
+	cc.rerender()
+	cc.refreshTitle()
+}
+
+// MarksStderr reports whether the pane sets stderr lines apart
+func (cc *ContainerContext) MarksStderr() bool {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.markStderr
+}
+
+// levelColor returns the tview color of messages at level
+func levelColor(level docker.LogLevel) string {
+	switch level {
+	case docker.LevelError:
+		return "red"
+	case docker.LevelWarn:
+		return "yellow"
+	case docker.LevelDebug:
+		return "gray"
+	default:
+		return "white"
+	}
+}
+
+// renderLine formats an entry for the view, truncated when truncation is on
+func (cc *ContainerContext) renderLine(entry docker.LogEntry) string {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.renderLineLocked(entry)
+}
+
+// renderLineLocked is renderLine for callers holding cc.mu. JSON log lines are shown
+// as their message field followed by the remaining fields, syslog lines as tag: message.
+func (cc *ContainerContext) renderLineLocked(entry docker.LogEntry) string {
+	level := cc.levelMatcher.MatchEntry(entry)
+	if entry.Syslog != nil && entry.Syslog.Tag != "" {
+		tag := entry.Syslog.Tag
+		if entry.Syslog.PID != "" {
+			tag += "[" + entry.Syslog.PID + "]"
+		}
+		entry.Message = tag + ": " + entry.Message
+	} else if structured, ok := docker.ParseJSONLog(entry.Message, cc.levelMatcher.Fields); ok {
+		entry.Message = structured.Display()
+		if !structured.Time.IsZero() {
+			entry.Timestamp = structured.Time
+		}
+	}
+
+	if entry.Source != "" {
+		entry.Message = path.Base(entry.Source) + ": " + entry.Message
+	}
+
+	count := ""
+	if entry.Repeats > 1 {
+		count = fmt.Sprintf(" (x%d)", entry.Repeats)
+	}
+	if width := cc.messageWidth(); width > 0 {
+		entry.Message = truncateMessage(entry.Message, max(width-len(count), 1))
+	}
+	entry.Message += count
+	return formatLogLine(entry, level, cc.noColor, cc.markStderr)
+}
+
+// messageWidth returns how many columns a message may use, or 0 for no truncation.
+// The selected pane always shows full lines. Callers hold cc.mu.
+func (cc *ContainerContext) messageWidth() int {
+	if !cc.truncate || cc.IsSelected {
+		return 0
+	}
+	width := cc.renderedWidth - len("15:04:05 ")
+	if cc.maxLineWidth > 0 && (width <= 0 || cc.maxLineWidth < width) {
+		width = cc.maxLineWidth
+	}
+	return width
+}
+
+// truncateMessage cuts message to width runes, ending with an ellipsis when cut
+func truncateMessage(message string, width int) string {
+	runes := []rune(message)
+	if len(runes) <= width {
+		return message
+	}
+	if width <= 1 {
+		return "…"
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// SetTruncation turns line truncation on or off. maxWidth caps the message width;
+// 0 truncates to the pane width only.
+func (cc *ContainerContext) SetTruncation(enabled bool, maxWidth int) {
+	cc.mu.Lock()
+	cc.truncate = enabled
+	cc.maxLineWidth = maxWidth
+	cc.mu.Unlock()
+	cc.rerender()
+}
+
+// CheckWidth re-renders a truncating pane whose width changed since it was last
+// rendered. Must be called from the UI goroutine after drawing.
+func (cc *ContainerContext) CheckWidth() {
+	if cc.LogView == nil {
+		return
+	}
+	_, _, width, _ := cc.LogView.GetInnerRect()
+
+	cc.mu.Lock()
+	changed := width != cc.renderedWidth
+	cc.renderedWidth = width
+	truncate := cc.truncate
+	cc.mu.Unlock()
+
+	if changed && truncate {
+		go cc.rerender()
+	}
+}
+
+// SetMinLevel changes the display threshold and re-renders the buffered lines
+func (cc *ContainerContext) SetMinLevel(level docker.LogLevel) {
+	cc.mu.Lock()
+	cc.minLevel = level
+	cc.mu.Unlock()
+	cc.rerender()
+}
+
+// SetLastLines clamps the view to the n most recent visible lines (0 shows the
+// whole buffer) and re-renders
+func (cc *ContainerContext) SetLastLines(n int) {
+	cc.mu.Lock()
+	if cc.lastLines == n {
+		cc.mu.Unlock()
+		return
+	}
+	cc.lastLines = n
+	cc.mu.Unlock()
+	cc.rerender()
+}
+
+// LastLines returns the current display clamp, 0 when the whole buffer is shown
+func (cc *ContainerContext) LastLines() int {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.lastLines
+}
+
+// MinLevel returns the current display threshold
+func (cc *ContainerContext) MinLevel() docker.LogLevel {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.minLevel
+}
+
+// rerender rebuilds the log view from the buffer using the current threshold and
+// line clamp
+func (cc *ContainerContext) rerender() {
+	if cc.LogView == nil || cc.app == nil {
+		return
+	}
+
+	cc.mu.Lock()
+	if cc.frozen || cc.paused {
+		cc.staleView = true
+		cc.mu.Unlock()
+		return
+	}
+	cc.mu.Unlock()
+
+	cc.mu.RLock()
+	var lines []string
+	for _, entry := range cc.LogBuffer {
+		if cc.levelMatcher.MatchEntry(entry) >= cc.minLevel {
+			lines = append(lines, cc.renderLineLocked(entry))
+		}
+	}
+	if cc.lastLines > 0 && len(lines) > cc.lastLines {
+		lines = lines[len(lines)-cc.lastLines:]
+	}
+	var text strings.Builder
+	text.WriteString(cc.headerText())
+	for _, line := range lines {
+		text.WriteString(line)
+		text.WriteString("\n")
+	}
+	cc.mu.RUnlock()
+
+	cc.mu.Lock()
+	cc.replaceText = text.String()
+	cc.replaceView = true
+	cc.unflushed = nil // superseded by the new text
+	cc.replaceLast, cc.replaceLastOK = "", false
+	cc.mu.Unlock()
+	cc.scheduleFlush()
+}
+
+// SetFrozen holds back (or releases) view updates. Lines keep being buffered while
+// frozen and are written to the view when it is unfrozen.
+func (cc *ContainerContext) SetFrozen(frozen bool) {
+	cc.setHold(&cc.frozen, frozen)
+}
+
+// SetPaused pauses (or resumes) this pane alone, independently of SetFrozen. Paused
+// panes show [PAUSED] in their title and flush the buffered lines on resume.
+func (cc *ContainerContext) SetPaused(paused bool) {
+	cc.setHold(&cc.paused, paused)
+	cc.refreshTitle()
+}
+
+// SetHealth records the container's healthcheck status ("healthy", "unhealthy",
+// "starting" or "" without a healthcheck) shown as a dot in the pane title
+func (cc *ContainerContext) SetHealth(health string) {
+	cc.mu.Lock()
+	changed := cc.health != health
+	cc.health = health
+	cc.mu.Unlock()
+	if changed {
+		cc.refreshTitle()
+	}
+}
+
+// SetState records the container's inspected state: its health dot and the uptime
+// (or time since it exited) shown in the pane title
+func (cc *ContainerContext) SetState(state docker.ContainerState) {
+	uptime := uptimeLabel(state, time.Now())
+	cc.mu.Lock()
+	changed := cc.health != state.Health || cc.uptime != uptime
+	cc.health = state.Health
+	cc.uptime = uptime
+	cc.mu.Unlock()
+	if changed {
+		cc.refreshTitle()
+	}
+}
+
+// SetNote attaches a note to the container ("this one is the suspect"), shown in
+// the pane title and included in exports and AI prompts; an empty note removes it
+func (cc *ContainerContext) SetNote(note string) {
+	cc.mu.Lock()
+	cc.note = strings.TrimSpace(note)
+	cc.mu.Unlock()
+	cc.refreshTitle()
+}
+
+// Note returns the note attached to the container, empty without one
+func (cc *ContainerContext) Note() string {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.note
+}
+
+// Health returns the container's last known healthcheck status
+func (cc *ContainerContext) Health() string {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.health
+}
+
+// IsPaused reports whether the pane is paused
+func (cc *ContainerContext) IsPaused() bool {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.paused
+}
+
+// setHold sets one of the reasons to hold back view updates (frozen or paused) and
+// flushes the held lines once neither is left
+func (cc *ContainerContext) setHold(flag *bool, value bool) {
+	cc.mu.Lock()
+	if *flag == value {
+		cc.mu.Unlock()
+		return
+	}
+	*flag = value
+	if cc.frozen || cc.paused {
+		cc.mu.Unlock()
+		return
+	}
+	pending := cc.pendingLines
+	stale := cc.staleView
+	cc.pendingLines = nil
+	cc.staleView = false
+	cc.mu.Unlock()
+
+	if stale {
+		// The buffer already holds the pending lines
+		cc.rerender()
+		return
+	}
+	for _, line := range pending {
+		cc.AppendLog(line)
+	}
+}
+
 // AppendLog adds a log line to the view (thread-safe)
 func (cc *ContainerContext) AppendLog(message string) {
-	if cc.LogView != nil && cc.app != nil {
-		cc.app.QueueUpdateDraw(func() {
-			fmt.Fprintf(cc.LogView, "%s\n", message)
-			cc.LogView.ScrollToEnd()
-		})
+	cc.mu.Lock()
+	if cc.frozen || cc.paused {
+		cc.pendingLines = append(cc.pendingLines, message)
+		if len(cc.pendingLines) > maxPendingLines {
+			cc.pendingLines = cc.pendingLines[1:]
+		}
+		cc.mu.Unlock()
+		return
+	}
+	if cc.LogView == nil || cc.app == nil {
+		cc.mu.Unlock()
+		return
+	}
+	cc.unflushed = append(cc.unflushed, message)
+	if len(cc.unflushed) > maxPendingLines {
+		cc.unflushed = cc.unflushed[len(cc.unflushed)-maxPendingLines:]
+	}
+	cc.mu.Unlock()
+
+	cc.scheduleFlush()
+}
+
+// replaceLastLog replaces the last line added to the view, like AppendLog adds one
+func (cc *ContainerContext) replaceLastLog(message string) {
+	cc.mu.Lock()
+	if cc.frozen || cc.paused {
+		if n := len(cc.pendingLines); n > 0 {
+			cc.pendingLines[n-1] = message
+		} else {
+			cc.staleView = true // the line is already in the view; re-render on resume
+		}
+		cc.mu.Unlock()
+		return
+	}
+	if cc.LogView == nil || cc.app == nil {
+		cc.mu.Unlock()
+		return
+	}
+	if n := len(cc.unflushed); n > 0 {
+		cc.unflushed[n-1] = message
+	} else {
+		cc.replaceLast, cc.replaceLastOK = message, true
+	}
+	cc.mu.Unlock()
+
+	cc.scheduleFlush()
+}
+
+// scheduleFlush has the queued view updates drawn: in the renderer's next frame, or
+// right away without a renderer
+func (cc *ContainerContext) scheduleFlush() {
+	if cc.renderer != nil {
+		cc.renderer.schedule(cc)
+		return
+	}
+	cc.app.QueueUpdateDraw(cc.flushView)
+}
+
+// flushView writes the queued re-render and lines to the view in one go. It runs
+// on the UI goroutine.
+func (cc *ContainerContext) flushView() {
+	cc.mu.Lock()
+	lines := cc.unflushed
+	text, replace := cc.replaceText, cc.replaceView
+	lastLine, replaceLast := cc.replaceLast, cc.replaceLastOK
+	cc.unflushed = nil
+	cc.replaceText, cc.replaceView = "", false
+	cc.replaceLast, cc.replaceLastOK = "", false
+	follow := cc.followTail
+	if !follow {
+		cc.newLines += len(lines)
+	}
+	cc.mu.Unlock()
+
+	if !replace && !replaceLast && len(lines) == 0 {
+		return
+	}
+	if replace {
+		cc.LogView.SetText(text)
+	}
+	if replaceLast {
+		text := strings.TrimSuffix(cc.LogView.GetText(false), "\n")
+		text = text[:strings.LastIndex(text, "\n")+1]
+		cc.LogView.SetText(text + lastLine + "\n")
+	}
+	if len(lines) > 0 {
+		var appended strings.Builder
+		for _, line := range lines {
+			appended.WriteString(line)
+			appended.WriteString("\n")
+		}
+		fmt.Fprint(cc.LogView, appended.String())
+	}
+
+	cc.LogView.SetTitle(cc.title())
+	if follow {
+		cc.LogView.ScrollToEnd()
 	}
 }
 
 // SetSelected updates the visual selection state
 func (cc *ContainerContext) SetSelected(selected bool) {
+	cc.mu.Lock()
+	changed := cc.IsSelected != selected
 	cc.IsSelected = selected
+	truncate := cc.truncate
+	cc.mu.Unlock()
+
+	// Truncated lines expand while the pane is selected
+	if changed && truncate {
+		cc.rerender()
+	}
 	if cc.LogView != nil {
 		if selected {
 			cc.LogView.SetBorderColor(tcell.NewRGBColor(255, 140, 0)) // Orange for focus
@@ -156,14 +1155,55 @@ func (cc *ContainerContext) GetLogBuffer() []docker.LogEntry {
 	return buffer
 }
 
-// Cleanup stops log streaming and cleans up resources
+// UpdateSparkline recomputes the per-second log rate shown in the pane title
+func (cc *ContainerContext) UpdateSparkline(now time.Time) {
+	cc.mu.Lock()
+	cc.sparkline = sparkline(rateBuckets(cc.LogBuffer, now, sparklineWindow))
+	cc.mu.Unlock()
+	cc.refreshTitle()
+}
+
+// ClearSparkline removes the log rate from the pane title
+func (cc *ContainerContext) ClearSparkline() {
+	cc.mu.Lock()
+	cc.sparkline = ""
+	cc.mu.Unlock()
+	cc.refreshTitle()
+}
+
+// refreshTitle redraws the pane title (thread-safe)
+func (cc *ContainerContext) refreshTitle() {
+	if cc.LogView != nil && cc.app != nil {
+		cc.app.QueueUpdateDraw(func() {
+			cc.LogView.SetTitle(cc.title())
+		})
+	}
+}
+
+// LastLogEntry returns the most recent buffered entry, if any
+func (cc *ContainerContext) LastLogEntry() (docker.LogEntry, bool) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+
+	if len(cc.LogBuffer) == 0 {
+		return docker.LogEntry{}, false
+	}
+	return cc.LogBuffer[len(cc.LogBuffer)-1], true
+}
+
+// ErrorCount returns the number of error-level lines seen since the stream started
+func (cc *ContainerContext) ErrorCount() int {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.errorCount
+}
+
+// Cleanup stops log streaming and cleans up resources. The log channel is closed
+// by the stream itself once it sees the cancellation.
 func (cc *ContainerContext) Cleanup() {
 	if cc.cancel != nil {
 		cc.cancel()
 	}
-	if cc.LogChannel != nil {
-		close(cc.LogChannel)
-	}
 }
 
 // colorToTviewColor converts tcell.Color to tview color string
@@ -198,6 +1238,16 @@ func (cc *ContainerContext) colorToTviewColor(color tcell.Color) string {
 
 // ContainerContextManager manages all container contexts
 type ContainerContextManager struct {
+	lazy          bool // create contexts without starting their streams
+	logFiles      bool // tail configured in-container log files (docker exec)
+	bufferSize    int  // log entries kept per container; 0 = DefaultBufferSize
+	titleWidth    int  // container name characters shown in pane titles; 0 = all
+	fullIDs       bool // show full container IDs in pane titles
+	keepRepeats   bool // show identical consecutive lines separately instead of collapsed
+	noColor       bool // show messages without level colors
+	reconnect     bool // reopen streams that end while their container runs
+	renderer      *frameRenderer // shared by all panes so they draw in the same frames
+	startSlots    chan struct{}  // limits streams being opened at once; nil = no limit
 	contexts      map[string]*ContainerContext
 	orderedIDs    []string
 	colors        []tcell.Color
@@ -212,21 +1262,128 @@ func NewContainerContextManager() *ContainerContextManager {
 		orderedIDs: make([]string, 0),
 		colors:     GetContainerColors(),
 		colorIndex: 0,
+		titleWidth: DefaultTitleWidth,
+		startSlots: make(chan struct{}, DefaultStreamStarts),
+	}
+}
+
+// SetLazy makes new contexts start without streaming; callers start streams with
+// ContainerContext.StartStreaming as panes are selected
+func (ccm *ContainerContextManager) SetLazy(lazy bool) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.lazy = lazy
+}
+
+// SetLogFiles enables tailing the in-container log files configured for each
+// container (see docker.LogFiles) through docker exec
+func (ccm *ContainerContextManager) SetLogFiles(enabled bool) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.logFiles = enabled
+}
+
+// SetReconnect enables reopening log streams that end while their container still
+// runs, e.g. because the Docker daemon restarted
+func (ccm *ContainerContextManager) SetReconnect(enabled bool) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.reconnect = enabled
+}
+
+// SetBufferSize sets how many recent log entries new contexts keep
+func (ccm *ContainerContextManager) SetBufferSize(size int) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.bufferSize = size
+}
+
+// SetTitleWidth sets how many characters of container names new pane titles show;
+// 0 shows whole names
+func (ccm *ContainerContextManager) SetTitleWidth(width int) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.titleWidth = width
+}
+
+// SetFullIDs makes new pane titles show the full container ID after the name
+func (ccm *ContainerContextManager) SetFullIDs(enabled bool) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.fullIDs = enabled
+}
+
+// SetStreamStartLimit sets how many log streams are opened at once (0 = no limit);
+// further streams queue and open as earlier ones are established. Applies to
+// contexts created afterwards.
+func (ccm *ContainerContextManager) SetStreamStartLimit(n int) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.startSlots = nil
+	if n > 0 {
+		ccm.startSlots = make(chan struct{}, n)
 	}
 }
 
+// SetNoColor shows the messages of new panes without their level colors, for users
+// who can't tell them apart or don't want them
+func (ccm *ContainerContextManager) SetNoColor(enabled bool) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.noColor = enabled
+}
+
+// SetCollapseRepeats turns collapsing identical consecutive lines on or off for all
+// panes, including ones created later
+func (ccm *ContainerContextManager) SetCollapseRepeats(enabled bool) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+	ccm.keepRepeats = !enabled
+	for _, context := range ccm.contexts {
+		context.SetCollapseRepeats(enabled)
+	}
+}
+
+// newContext creates and initializes the context for a container. Callers hold ccm.mu.
+func (ccm *ContainerContextManager) newContext(container docker.Container, source docker.LogSource, app *tview.Application) (*ContainerContext, error) {
+	color := ccm.colors[ccm.colorIndex%len(ccm.colors)]
+	ccm.colorIndex++
+
+	context := NewContainerContext(container, color, app, ccm.bufferSize)
+	context.titleWidth = ccm.titleWidth
+	context.fullID = ccm.fullIDs
+	context.collapse = !ccm.keepRepeats
+	context.noColor = ccm.noColor
+	context.reconnect = ccm.reconnect
+	context.startSlots = ccm.startSlots
+	if app != nil {
+		if ccm.renderer == nil {
+			ccm.renderer = newFrameRenderer(app)
+		}
+		context.renderer = ccm.renderer
+	}
+	if ccm.logFiles {
+		context.logFiles = docker.LogFiles(container)
+	}
+	if ccm.lazy {
+		context.InitializeLazy(source)
+		return context, nil
+	}
+	if err := context.Initialize(source); err != nil {
+		return nil, fmt.Errorf("failed to initialize context for %s: %w", container.Name, err)
+	}
+	return context, nil
+}
+
 // InitializeContexts creates contexts for all containers
-func (ccm *ContainerContextManager) InitializeContexts(containers []docker.Container, dockerService *docker.DockerService, app *tview.Application) error {
+func (ccm *ContainerContextManager) InitializeContexts(containers []docker.Container, source docker.LogSource, app *tview.Application) error {
 	ccm.mu.Lock()
 	defer ccm.mu.Unlock()
 	
 	for _, container := range containers {
-		color := ccm.colors[ccm.colorIndex%len(ccm.colors)]
-		ccm.colorIndex++
-		
-		context := NewContainerContext(container, color, app)
-		if err := context.Initialize(dockerService); err != nil {
-			return fmt.Errorf("failed to initialize context for %s: %w", container.Name, err)
+		context, err := ccm.newContext(container, source, app)
+		if err != nil {
+			return err
 		}
 		
 		ccm.contexts[container.ID] = context
@@ -236,6 +1393,49 @@ func (ccm *ContainerContextManager) InitializeContexts(containers []docker.Conta
 	return nil
 }
 
+// Reconcile brings the contexts in line with the given running containers: contexts
+// are created for new containers and cleaned up for containers that are gone.
+// Existing contexts keep their order; new ones are appended.
+func (ccm *ContainerContextManager) Reconcile(containers []docker.Container, source docker.LogSource, app *tview.Application) (added, removed []docker.Container, err error) {
+	ccm.mu.Lock()
+	defer ccm.mu.Unlock()
+
+	running := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		running[container.ID] = true
+	}
+
+	orderedIDs := make([]string, 0, len(containers))
+	for _, id := range ccm.orderedIDs {
+		context := ccm.contexts[id]
+		if running[id] {
+			orderedIDs = append(orderedIDs, id)
+			continue
+		}
+		context.Cleanup()
+		delete(ccm.contexts, id)
+		removed = append(removed, context.Container)
+	}
+	ccm.orderedIDs = orderedIDs
+
+	for _, container := range containers {
+		if _, exists := ccm.contexts[container.ID]; exists {
+			continue
+		}
+
+		context, err := ccm.newContext(container, source, app)
+		if err != nil {
+			return added, removed, err
+		}
+
+		ccm.contexts[container.ID] = context
+		ccm.orderedIDs = append(ccm.orderedIDs, container.ID)
+		added = append(added, container)
+	}
+
+	return added, removed, nil
+}
+
 // GetContext returns the context for a specific container ID
 func (ccm *ContainerContextManager) GetContext(containerID string) (*ContainerContext, bool) {
 	ccm.mu.RLock()
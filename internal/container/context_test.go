@@ -0,0 +1,406 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rivo/tview"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+func TestFormatLogLineEscapesTags(t *testing.T) {
+	message := "[red]ALERT[-] [::b]bold[white] hidden[#000000:#000000]text"
+	entry := docker.LogEntry{
+		Timestamp: time.Date(2025, 1, 1, 10, 4, 5, 0, time.UTC),
+		Message:   message,
+	}
+
+	line := formatLogLine(entry, docker.LevelError, false, false)
+
+	// Rendering must show the message verbatim: only colog's own timestamp tags are interpreted
+	view := tview.NewTextView().SetDynamicColors(true)
+	view.SetText(line)
+	if got, want := view.GetText(true), "10:04:05 "+message; got != want {
+		t.Errorf("rendered %q, want %q", got, want)
+	}
+}
+
+func TestFormatLogLineLevelColors(t *testing.T) {
+	entry := docker.LogEntry{Timestamp: time.Date(2025, 1, 1, 10, 4, 5, 0, time.UTC), Message: "panic: boom"}
+	level := docker.NewLevelMatcher().MatchEntry(entry)
+
+	if line := formatLogLine(entry, level, false, false); !strings.Contains(line, "[red:#000000] panic: boom") {
+		t.Errorf("panic line %q isn't red", line)
+	}
+	if line := formatLogLine(entry, level, true, false); strings.Contains(line, "[red") {
+		t.Errorf("line %q is colored with colors off", line)
+	}
+}
+
+func TestFormatLogLineMarksStderr(t *testing.T) {
+	entry := docker.LogEntry{Timestamp: time.Date(2025, 1, 1, 10, 4, 5, 0, time.UTC), Message: "retrying", Stream: "stderr"}
+
+	if line := formatLogLine(entry, docker.LevelInfo, false, true); !strings.Contains(line, "[red:"+stderrBackground+"] retrying") {
+		t.Errorf("marked stderr line %q lacks the stderr background", line)
+	}
+	if line := formatLogLine(entry, docker.LevelInfo, false, false); strings.Contains(line, stderrBackground) {
+		t.Errorf("unmarked stderr line %q has the stderr background", line)
+	}
+	entry.Stream = "stdout"
+	if line := formatLogLine(entry, docker.LevelInfo, false, true); strings.Contains(line, stderrBackground) {
+		t.Errorf("stdout line %q has the stderr background", line)
+	}
+
+	cc := NewContainerContext(docker.Container{ID: "abc", Name: "web"}, 0, nil, 0)
+	cc.SetMarkStderr(true)
+	if !cc.MarksStderr() || !strings.Contains(cc.title(), "[stderr[]") {
+		t.Errorf("title %q doesn't show the pane marks stderr", cc.title())
+	}
+}
+
+func TestPauseAndFreezeHoldLinesIndependently(t *testing.T) {
+	cc := NewContainerContext(docker.Container{ID: "abc", Name: "web"}, 0, nil, 0)
+
+	cc.SetPaused(true)
+	cc.AppendLog("held while paused")
+	cc.SetFrozen(true)
+	cc.SetPaused(false)
+	if len(cc.pendingLines) != 1 {
+		t.Fatalf("resuming a frozen pane flushed its lines: %v", cc.pendingLines)
+	}
+
+	cc.SetFrozen(false)
+	if cc.pendingLines != nil {
+		t.Errorf("lines still pending after unfreezing: %v", cc.pendingLines)
+	}
+
+	cc.SetPaused(true)
+	if title := cc.title(); !strings.Contains(title, "PAUSED") {
+		t.Errorf("title %q lacks the paused indicator", title)
+	}
+}
+
+func TestLogBufferKeepsConfiguredSize(t *testing.T) {
+	cc := NewContainerContext(docker.Container{ID: "abc", Name: "web"}, 0, nil, 3)
+
+	logCh := make(chan docker.LogEntry, 5)
+	for i := 1; i <= 5; i++ {
+		logCh <- docker.LogEntry{Timestamp: time.Unix(int64(i), 0), Message: fmt.Sprintf("line %d", i)}
+	}
+	close(logCh)
+	cc.processLogs(context.Background(), logCh, time.Time{})
+
+	buffer := cc.GetLogBuffer()
+	if len(buffer) != 3 || buffer[0].Message != "line 3" || buffer[2].Message != "line 5" {
+		t.Errorf("buffer = %v, want lines 3 to 5", buffer)
+	}
+
+	if got := NewContainerContext(docker.Container{}, 0, nil, 0).bufferSize; got != DefaultBufferSize {
+		t.Errorf("default buffer size = %d, want %d", got, DefaultBufferSize)
+	}
+}
+
+func TestHealthDotInTitle(t *testing.T) {
+	cc := NewContainerContext(docker.Container{ID: "abc", Name: "web"}, 0, nil, 0)
+	if title := cc.title(); strings.Contains(title, "●") {
+		t.Errorf("title %q shows a health dot without a healthcheck", title)
+	}
+
+	cc.SetHealth("unhealthy")
+	if title := cc.title(); !strings.Contains(title, "[red]●") {
+		t.Errorf("title %q lacks the red health dot", title)
+	}
+}
+
+func TestUptimeLabel(t *testing.T) {
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		state docker.ContainerState
+		want  string
+	}{
+		{docker.ContainerState{Running: true, StartedAt: now.Add(-3*time.Hour - 12*time.Minute - 5*time.Second)}, "up 3h12m"},
+		{docker.ContainerState{Running: true, StartedAt: now.Add(-45 * time.Second)}, "up 45s"},
+		{docker.ContainerState{Running: true, StartedAt: now.Add(-50 * time.Hour)}, "up 2d2h"},
+		{docker.ContainerState{StartedAt: now.Add(-time.Hour), FinishedAt: now.Add(-5 * time.Minute)}, "exited 5m ago"},
+		{docker.ContainerState{}, ""},
+	}
+	for _, tt := range tests {
+		if got := uptimeLabel(tt.state, now); got != tt.want {
+			t.Errorf("uptimeLabel(%+v) = %q, want %q", tt.state, got, tt.want)
+		}
+	}
+
+	cc := NewContainerContext(docker.Container{ID: "abc", Name: "web"}, 0, nil, 0)
+	cc.SetState(docker.ContainerState{Health: "healthy", Running: true, StartedAt: time.Now().Add(-2 * time.Minute)})
+	if title := cc.title(); !strings.Contains(title, "up 2m") || !strings.Contains(title, "[green]●") {
+		t.Errorf("title %q lacks the uptime or health dot", title)
+	}
+}
+
+func TestNoteInTitle(t *testing.T) {
+	cc := NewContainerContext(docker.Container{ID: "abc", Name: "web"}, 0, nil, 0)
+	cc.SetNote("  the suspect [db]  ")
+	if cc.Note() != "the suspect [db]" {
+		t.Errorf("Note() = %q, want it trimmed", cc.Note())
+	}
+	if title := cc.title(); !strings.Contains(title, tview.Escape("✎ the suspect [db]")) {
+		t.Errorf("title %q lacks the escaped note", title)
+	}
+
+	cc.SetNote("")
+	if title := cc.title(); strings.Contains(title, "✎") {
+		t.Errorf("title %q still shows the removed note", title)
+	}
+}
+
+func TestTitleTruncatesByRune(t *testing.T) {
+	name := "データベース-サーバー-プライマリ-東京リージョン"
+	cc := NewContainerContext(docker.Container{ID: "0123456789abcdef0123", Name: name}, 0, nil, 0)
+	cc.titleWidth = 10
+
+	title := cc.title()
+	if !utf8.ValidString(title) {
+		t.Fatalf("title %q is not valid UTF-8", title)
+	}
+	if !strings.Contains(title, "データベース-サーバ...") {
+		t.Errorf("title %q does not keep the first 10 characters", title)
+	}
+
+	cc.titleWidth = 0
+	cc.fullID = true
+	if title := cc.title(); !strings.Contains(title, name+" 0123456789abcdef0123") {
+		t.Errorf("title %q lacks the whole name and full ID", title)
+	}
+}
+
+func TestStreamStartSlotsQueue(t *testing.T) {
+	manager := NewContainerContextManager()
+	manager.SetStreamStartLimit(2)
+	var panes []*ContainerContext
+	for i := 0; i < 3; i++ {
+		cc := NewContainerContext(docker.Container{ID: fmt.Sprint(i)}, 0, nil, 0)
+		cc.startSlots = manager.startSlots
+		panes = append(panes, cc)
+	}
+
+	ctx := context.Background()
+	if !panes[0].acquireStartSlot(ctx) || !panes[1].acquireStartSlot(ctx) {
+		t.Fatal("streams within the limit had to wait")
+	}
+
+	// The third waits for a slot, and gives up when its stream is cancelled
+	waitCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if panes[2].acquireStartSlot(waitCtx) {
+		t.Fatal("a stream over the limit started")
+	}
+
+	acquired := make(chan bool)
+	go func() { acquired <- panes[2].acquireStartSlot(ctx) }()
+	panes[0].releaseStartSlot()
+	if !<-acquired {
+		t.Error("a queued stream didn't start once a slot freed")
+	}
+
+	manager.SetStreamStartLimit(0)
+	unlimited := NewContainerContext(docker.Container{}, 0, nil, 0)
+	unlimited.startSlots = manager.startSlots
+	if !unlimited.acquireStartSlot(waitCtx) {
+		t.Error("no limit still waited")
+	}
+}
+
+// sliceSource is a LogSource serving fixed lines per container ID
+type sliceSource map[string][]string
+
+func (s sliceSource) List(ctx context.Context) ([]docker.Container, error) {
+	var containers []docker.Container
+	for id := range s {
+		containers = append(containers, docker.Container{ID: id, Name: "c-" + id})
+	}
+	return containers, nil
+}
+
+func (s sliceSource) Stream(ctx context.Context, containerID string, opts docker.LogStreamOptions) (<-chan docker.LogEntry, error) {
+	logCh := make(chan docker.LogEntry, len(s[containerID]))
+	for i, message := range s[containerID] {
+		logCh <- docker.LogEntry{ContainerID: containerID, Timestamp: time.Unix(int64(i+1), 0), Message: message}
+	}
+	close(logCh)
+	return logCh, nil
+}
+
+// restartingSource is a LogSource whose first stream ends as if the daemon went away,
+// and whose state can't be read while down is set
+type restartingSource struct {
+	mu      sync.Mutex
+	streams int
+	checks  int // State calls
+	down    bool
+}
+
+func (s *restartingSource) List(ctx context.Context) ([]docker.Container, error) {
+	return []docker.Container{{ID: "a", Name: "web"}}, nil
+}
+
+func (s *restartingSource) Stream(ctx context.Context, containerID string, opts docker.LogStreamOptions) (<-chan docker.LogEntry, error) {
+	s.mu.Lock()
+	s.streams++
+	n := s.streams
+	s.mu.Unlock()
+	logCh := make(chan docker.LogEntry, 1)
+	logCh <- docker.LogEntry{ContainerID: containerID, Timestamp: time.Unix(int64(n), 0), Message: fmt.Sprintf("line %d", n)}
+	if n == 1 {
+		close(logCh)
+	}
+	return logCh, nil
+}
+
+func (s *restartingSource) State(ctx context.Context, containerID string) (docker.ContainerState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks++
+	if s.down {
+		return docker.ContainerState{}, errors.New("daemon unreachable")
+	}
+	return docker.ContainerState{Running: true}, nil
+}
+
+func TestContextReconnectsAfterDaemonRestart(t *testing.T) {
+	defer func(delay, maxDelay time.Duration) { reconnectDelay, maxReconnectDelay = delay, maxDelay }(reconnectDelay, maxReconnectDelay)
+	reconnectDelay, maxReconnectDelay = 5*time.Millisecond, 20*time.Millisecond
+
+	source := &restartingSource{down: true}
+	containers, _ := source.List(context.Background())
+	manager := NewContainerContextManager()
+	manager.SetReconnect(true)
+	if err := manager.InitializeContexts(containers, source, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Cleanup()
+	cc, _ := manager.GetContext("a")
+
+	waitFor := func(what string, done func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !done() {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s; buffer %v", what, cc.GetLogBuffer())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFor("no retries while the daemon was down", func() bool {
+		source.mu.Lock()
+		defer source.mu.Unlock()
+		if source.checks >= 2 {
+			source.down = false
+			return true
+		}
+		return false
+	})
+	waitFor("the stream wasn't reopened once the daemon was back", func() bool {
+		buffer := cc.GetLogBuffer()
+		return len(buffer) == 2 && buffer[1].Message == "line 2"
+	})
+	if state := cc.StreamState(); state != docker.StreamActive {
+		t.Errorf("reopened stream is %s, want active", state)
+	}
+}
+
+func TestContextStreamsFromLogSource(t *testing.T) {
+	source := sliceSource{"a": {"one", "two"}}
+	containers, _ := source.List(context.Background())
+	manager := NewContainerContextManager()
+	if err := manager.InitializeContexts(containers, source, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Cleanup()
+
+	cc, ok := manager.GetContext("a")
+	if !ok {
+		t.Fatal("no context for the listed container")
+	}
+	deadline := time.Now().Add(time.Second)
+	for cc.StreamState() != docker.StreamEnded && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	buffer := cc.GetLogBuffer()
+	if cc.StreamState() != docker.StreamEnded || len(buffer) != 2 || buffer[1].Message != "two" {
+		t.Errorf("state %s, buffer %v; want the source's lines and an ended stream", cc.StreamState(), buffer)
+	}
+}
+
+// idleSource is a LogSource whose first stream stays silent until stopped; later
+// streams send one line and stay open
+type idleSource struct {
+	mu      sync.Mutex
+	streams int
+}
+
+func (s *idleSource) List(ctx context.Context) ([]docker.Container, error) {
+	return []docker.Container{{ID: "a", Name: "web"}}, nil
+}
+
+func (s *idleSource) Stream(ctx context.Context, containerID string, opts docker.LogStreamOptions) (<-chan docker.LogEntry, error) {
+	s.mu.Lock()
+	s.streams++
+	n := s.streams
+	s.mu.Unlock()
+	logCh := make(chan docker.LogEntry, 1)
+	if n > 1 {
+		logCh <- docker.LogEntry{ContainerID: containerID, Timestamp: time.Unix(int64(n), 0), Message: "resumed"}
+	}
+	go func() {
+		<-ctx.Done()
+		close(logCh)
+	}()
+	return logCh, nil
+}
+
+func TestContextRestartsAfterStopWithEmptyBuffer(t *testing.T) {
+	source := &idleSource{}
+	containers, _ := source.List(context.Background())
+	manager := NewContainerContextManager()
+	if err := manager.InitializeContexts(containers, source, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer manager.Cleanup()
+	cc, _ := manager.GetContext("a")
+
+	waitFor := func(what string, done func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !done() {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s; state %s, buffer %v", what, cc.StreamState(), cc.GetLogBuffer())
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFor("the first stream wasn't opened", func() bool {
+		source.mu.Lock()
+		defer source.mu.Unlock()
+		return source.streams == 1
+	})
+
+	// Idle with nothing buffered, then selected again
+	cc.StopStreaming()
+	if err := cc.StartStreaming(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("the restarted stream didn't deliver its line", func() bool {
+		buffer := cc.GetLogBuffer()
+		return len(buffer) > 0 && buffer[len(buffer)-1].Message == "resumed"
+	})
+	if state := cc.StreamState(); state != docker.StreamActive {
+		t.Errorf("restarted stream is %s, want active", state)
+	}
+}
@@ -0,0 +1,74 @@
+package container
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// frameInterval bounds how often the panes are redrawn, however fast lines arrive
+const frameInterval = time.Second / 30
+
+// frameRenderer coalesces view updates from all panes into at most one draw per
+// frame. Panes queue their lines and mark themselves dirty; the next frame flushes
+// every dirty pane in a single QueueUpdateDraw instead of one draw per line.
+type frameRenderer struct {
+	app      *tview.Application
+	interval time.Duration
+	mu       sync.Mutex
+	dirty    map[*ContainerContext]struct{}
+	running  bool // whether the frame loop is running; it stops once nothing is dirty
+}
+
+func newFrameRenderer(app *tview.Application) *frameRenderer {
+	return &frameRenderer{
+		app:      app,
+		interval: frameInterval,
+		dirty:    make(map[*ContainerContext]struct{}),
+	}
+}
+
+// schedule flushes the pane in the next frame, starting the frame loop if idle
+func (r *frameRenderer) schedule(cc *ContainerContext) {
+	r.mu.Lock()
+	r.dirty[cc] = struct{}{}
+	if r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = true
+	r.mu.Unlock()
+
+	go r.run()
+}
+
+// run draws a frame, waits out the frame interval and repeats while panes are
+// dirty. The first frame after an idle period is drawn right away.
+func (r *frameRenderer) run() {
+	for {
+		r.mu.Lock()
+		if len(r.dirty) == 0 {
+			r.running = false
+			r.mu.Unlock()
+			return
+		}
+		panes := r.dirty
+		r.dirty = make(map[*ContainerContext]struct{})
+		r.mu.Unlock()
+
+		r.app.QueueUpdateDraw(func() {
+			for cc := range panes {
+				cc.flushView()
+			}
+		})
+		time.Sleep(r.interval)
+	}
+}
+
+// idle reports whether no frame is pending
+func (r *frameRenderer) idle() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.running
+}
@@ -0,0 +1,174 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+// gridHarness runs an application with one pane per container on a simulated
+// screen, counting draws
+type gridHarness struct {
+	app   *tview.Application
+	panes []*ContainerContext
+	draws atomic.Int64
+}
+
+func newGridHarness(tb testing.TB, containers int, coalesce bool) *gridHarness {
+	tb.Helper()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		tb.Fatal(err)
+	}
+	screen.SetSize(240, 80)
+
+	h := &gridHarness{app: tview.NewApplication().SetScreen(screen)}
+	renderer := newFrameRenderer(h.app)
+	grid := tview.NewFlex()
+	for i := 0; i < containers; i++ {
+		cc := NewContainerContext(docker.Container{ID: fmt.Sprint(i), Name: fmt.Sprintf("c%d", i)}, 0, h.app, 0)
+		cc.setupLogView()
+		if coalesce {
+			cc.renderer = renderer
+		}
+		grid.AddItem(cc.LogView, 0, 1, false)
+		h.panes = append(h.panes, cc)
+	}
+	h.app.SetRoot(grid, true).SetAfterDrawFunc(func(tcell.Screen) { h.draws.Add(1) })
+
+	go h.app.Run()
+	tb.Cleanup(h.app.Stop)
+	h.sync()
+	return h
+}
+
+// appendLines appends lines to every pane, interleaved like concurrent streams
+func (h *gridHarness) appendLines(lines int) {
+	for i := 0; i < lines; i++ {
+		for _, cc := range h.panes {
+			cc.AppendLog(fmt.Sprintf("12:00:00 line %d of %s", i, cc.Container.Name))
+		}
+	}
+}
+
+// drain waits until every queued line has been drawn
+func (h *gridHarness) drain() {
+	for {
+		pending := false
+		for _, cc := range h.panes {
+			cc.mu.RLock()
+			pending = pending || len(cc.unflushed) > 0
+			cc.mu.RUnlock()
+			if cc.renderer != nil && !cc.renderer.idle() {
+				pending = true
+			}
+		}
+		if !pending {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	h.sync()
+}
+
+// sync waits for the updates queued so far to be drawn
+func (h *gridHarness) sync() {
+	done := make(chan struct{})
+	h.app.QueueUpdateDraw(func() { close(done) })
+	<-done
+}
+
+func TestFrameRendererCoalescesDraws(t *testing.T) {
+	h := newGridHarness(t, 10, true)
+	before := h.draws.Load()
+
+	h.appendLines(100)
+	h.drain()
+
+	// 1000 lines arrive within a few frames; drawing each would take 1000 draws
+	if draws := h.draws.Load() - before; draws > 20 {
+		t.Errorf("1000 lines took %d draws", draws)
+	}
+	for _, cc := range h.panes {
+		text := cc.LogView.GetText(true)
+		if n := strings.Count(text, "line "); n != 100 {
+			t.Errorf("%s shows %d lines, want 100", cc.Container.Name, n)
+		}
+		if !strings.Contains(text, "line 99 of "+cc.Container.Name+"\n") {
+			t.Errorf("%s lacks its last line", cc.Container.Name)
+		}
+	}
+}
+
+// benchmarkGrid pushes one second of logs from containers × linesPerSec into the
+// grid per iteration, as fast as they come, and reports the draws it took
+func benchmarkGrid(b *testing.B, containers, linesPerSec int, coalesce bool) {
+	h := newGridHarness(b, containers, coalesce)
+	before := h.draws.Load()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h.appendLines(linesPerSec)
+		h.drain()
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(h.draws.Load()-before)/float64(b.N), "draws/op")
+}
+
+// Each line triggering its own draw, as before the frame renderer
+func BenchmarkGridDrawPerLine(b *testing.B) {
+	benchmarkGrid(b, 12, 200, false)
+}
+
+func BenchmarkGridFrameRenderer(b *testing.B) {
+	benchmarkGrid(b, 12, 200, true)
+}
+
+func TestRepeatedLinesCollapse(t *testing.T) {
+	h := newGridHarness(t, 1, true)
+	cc := h.panes[0]
+
+	at := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	feed := func(messages ...string) {
+		logCh := make(chan docker.LogEntry, len(messages))
+		for _, message := range messages {
+			at = at.Add(time.Second)
+			logCh <- docker.LogEntry{Timestamp: at, Message: message, Stream: "stdout"}
+		}
+		close(logCh)
+		cc.processLogs(context.Background(), logCh, time.Time{})
+		h.drain()
+	}
+
+	feed("connection retry", "connection retry")
+	feed("connection retry", "ready", "ready")
+	text := cc.LogView.GetText(true)
+	if strings.Count(text, "connection retry") != 1 || !strings.Contains(text, "connection retry (x3)\n") {
+		t.Errorf("view doesn't collapse the repeats:\n%s", text)
+	}
+	if !strings.HasSuffix(text, "ready (x2)\n") {
+		t.Errorf("view doesn't end with the collapsed last line:\n%s", text)
+	}
+
+	buffer := cc.GetLogBuffer()
+	if len(buffer) != 2 || buffer[0].Repeats != 3 || buffer[1].Repeats != 2 {
+		t.Errorf("buffer = %+v, want 2 entries counting 3 and 2 lines", buffer)
+	}
+	if !buffer[0].Timestamp.Equal(time.Date(2025, 3, 4, 12, 0, 3, 0, time.UTC)) {
+		t.Errorf("collapsed entry has timestamp %v, want its last repeat's", buffer[0].Timestamp)
+	}
+
+	cc.SetCollapseRepeats(false)
+	feed("ready")
+	if n := len(cc.GetLogBuffer()); n != 3 {
+		t.Errorf("buffer has %d entries with collapsing off, want 3", n)
+	}
+}
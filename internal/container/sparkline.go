@@ -0,0 +1,46 @@
+package container
+
+import (
+	"time"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+// sparkBlocks are the bar heights used to draw a sparkline, lowest first
+var sparkBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// sparklineWindow is how many one-second buckets a pane sparkline shows
+const sparklineWindow = 20
+
+// rateBuckets counts entries per second for the window seconds ending at now
+func rateBuckets(entries []docker.LogEntry, now time.Time, window int) []int {
+	counts := make([]int, window)
+	end := now.Truncate(time.Second)
+	for _, entry := range entries {
+		age := int(end.Sub(entry.Timestamp.Truncate(time.Second)) / time.Second)
+		if age >= 0 && age < window {
+			counts[window-1-age]++
+		}
+	}
+	return counts
+}
+
+// sparkline renders counts as unicode block characters scaled to the largest count
+func sparkline(counts []int) string {
+	peak := 0
+	for _, count := range counts {
+		if count > peak {
+			peak = count
+		}
+	}
+
+	line := make([]rune, len(counts))
+	for i, count := range counts {
+		level := 0
+		if peak > 0 && count > 0 {
+			level = 1 + count*(len(sparkBlocks)-2)/peak
+		}
+		line[i] = sparkBlocks[level]
+	}
+	return string(line)
+}
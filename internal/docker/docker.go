@@ -2,26 +2,46 @@ package docker
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/client"
 )
 
 type Container struct {
-	ID     string
-	Name   string
-	Image  string
-	Status string
+	ID      string
+	Name    string
+	Image   string
+	ImageID string
+	Status  string
+	Created time.Time
+	Host    string // label of the Docker host running the container; empty with a single host
+	Origin  string // label of the imported recording the container comes from; empty when live
+	Labels  map[string]string
 }
 
 type DockerService struct {
+	client *client.Client // the first host's client, used when there is only one
+	hosts  []dockerHost   // every connected host; more than one only with COLOG_DOCKER_HOSTS
+
+	mu     sync.RWMutex
+	owners map[string]dockerHost // container ID → host running it, filled by listings
+}
+
+// dockerHost is one Docker daemon of a multi-host setup
+type dockerHost struct {
+	label  string
 	client *client.Client
 }
 
@@ -38,6 +58,10 @@ func NewDockerService() (*DockerService, error) {
 }
 
 func NewDockerServiceWithSelection(interactive bool) (*DockerService, error) {
+	if hosts := os.Getenv("COLOG_DOCKER_HOSTS"); hosts != "" {
+		return connectToDockerHosts(hosts)
+	}
+
 	endpoints := discoverDockerEndpoints()
 	
 	if len(endpoints) == 0 {
@@ -231,7 +255,8 @@ func connectToDockerEndpoint(endpoint DockerEndpoint) (*DockerService, error) {
 	cli, err := client.NewClientWithOpts(
 		client.WithHost(endpoint.Host),
 		client.WithAPIVersionNegotiation(),
-		client.WithTimeout(5*time.Second),
+		// No client-wide timeout: it would also cut off followed log streams. The
+		// ping below is bounded by its context instead.
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Docker client for %s: %w", endpoint.Name, err)
@@ -247,111 +272,341 @@ func connectToDockerEndpoint(endpoint DockerEndpoint) (*DockerService, error) {
 	}
 	
 	fmt.Printf("✓ Connected to Docker via %s (%s)\n", endpoint.Name, endpoint.Description)
-	return &DockerService{client: cli}, nil
+	return newDockerService([]dockerHost{{client: cli}}), nil
+}
+
+// NewDockerServiceFromClient wraps an already connected client, so a program with its
+// own endpoint selection reads logs the same way colog does
+func NewDockerServiceFromClient(cli *client.Client) *DockerService {
+	return newDockerService([]dockerHost{{client: cli}})
+}
+
+func newDockerService(hosts []dockerHost) *DockerService {
+	return &DockerService{
+		client: hosts[0].client,
+		hosts:  hosts,
+		owners: make(map[string]dockerHost),
+	}
+}
+
+// connectToDockerHosts connects to every host in a comma-separated list of
+// [label=]host entries such as "web=tcp://10.0.0.5:2375,unix:///var/run/docker.sock".
+// Containers are labeled with the entry's label, or the host name when it has none.
+func connectToDockerHosts(list string) (*DockerService, error) {
+	var hosts []dockerHost
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		label, host := hostLabel(entry)
+
+		cli, err := client.NewClientWithOpts(
+			client.WithHost(host),
+			client.WithAPIVersionNegotiation(),
+		)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			_, err = cli.Ping(ctx)
+			cancel()
+			if err != nil {
+				cli.Close()
+			}
+		}
+		if err != nil {
+			for _, connected := range hosts {
+				connected.client.Close()
+			}
+			return nil, fmt.Errorf("failed to connect to Docker host %s (%s): %w", label, host, err)
+		}
+
+		fmt.Printf("✓ Connected to Docker host %s (%s)\n", label, host)
+		hosts = append(hosts, dockerHost{label: label, client: cli})
+	}
+
+	if len(hosts) == 0 {
+		return nil, fmt.Errorf("COLOG_DOCKER_HOSTS has no hosts")
+	}
+	if len(hosts) == 1 {
+		// A single host needs no labels
+		hosts[0].label = ""
+	}
+	return newDockerService(hosts), nil
+}
+
+// hostLabel splits a "label=host" entry; without a label the host name is used
+// ("local" for unix sockets)
+func hostLabel(entry string) (label, host string) {
+	if before, after, found := strings.Cut(entry, "="); found && !strings.Contains(before, "://") {
+		return strings.TrimSpace(before), strings.TrimSpace(after)
+	}
+	if parsed, err := url.Parse(entry); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname(), entry
+	}
+	return "local", entry
 }
 
 func (ds *DockerService) Close() error {
-	return ds.client.Close()
+	var firstErr error
+	for _, host := range ds.hosts {
+		if err := host.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// clientFor returns the client of the host running containerID. With several hosts
+// the owner is learned from container listings, or found by asking each host.
+func (ds *DockerService) clientFor(ctx context.Context, containerID string) (*client.Client, error) {
+	host, err := ds.hostFor(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	return host.client, nil
+}
+
+// hostFor finds the Docker host running containerID
+func (ds *DockerService) hostFor(ctx context.Context, containerID string) (dockerHost, error) {
+	if len(ds.hosts) == 1 {
+		return ds.hosts[0], nil
+	}
+	if host, ok := ds.ownerOf(containerID); ok {
+		return host, nil
+	}
+
+	for _, host := range ds.hosts {
+		if info, err := host.client.ContainerInspect(ctx, containerID); err == nil {
+			ds.mu.Lock()
+			ds.owners[info.ID] = host
+			ds.mu.Unlock()
+			return host, nil
+		}
+	}
+	return dockerHost{}, fmt.Errorf("container %s not found on any Docker host", containerID)
+}
+
+// ownerOf looks up the host of a listed container by full or short ID
+func (ds *DockerService) ownerOf(containerID string) (dockerHost, bool) {
+	ds.mu.RLock()
+	defer ds.mu.RUnlock()
+	if host, ok := ds.owners[containerID]; ok {
+		return host, true
+	}
+	for id, host := range ds.owners {
+		if strings.HasPrefix(id, containerID) {
+			return host, true
+		}
+	}
+	return dockerHost{}, false
+}
+
+// DaemonHost returns the address of the Docker daemon running containerID, for
+// pointing the docker CLI (DOCKER_HOST) at the right host
+func (ds *DockerService) DaemonHost(ctx context.Context, containerID string) (string, error) {
+	cli, err := ds.clientFor(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	return cli.DaemonHost(), nil
+}
+
+// IsMultiHost reports whether containers come from more than one Docker host
+func (ds *DockerService) IsMultiHost() bool {
+	return len(ds.hosts) > 1
+}
+
+// ContainerSummary is Docker's container list entry plus the label of its host
+type ContainerSummary struct {
+	container.Summary
+	Host string
 }
 
 func (ds *DockerService) ListRunningContainers(ctx context.Context) ([]Container, error) {
-	containers, err := ds.client.ContainerList(ctx, container.ListOptions{})
+	return ds.listContainers(ctx, false)
+}
+
+// ListAllContainers lists running and stopped containers
+func (ds *DockerService) ListAllContainers(ctx context.Context) ([]Container, error) {
+	return ds.listContainers(ctx, true)
+}
+
+func (ds *DockerService) listContainers(ctx context.Context, all bool) ([]Container, error) {
+	summaries, err := ds.ListContainerSummaries(ctx, all)
 	if err != nil {
 		return nil, err
 	}
 
 	var result []Container
-	for _, ctr := range containers {
-		name := strings.TrimPrefix(ctr.Names[0], "/")
+	for _, ctr := range summaries {
 		result = append(result, Container{
-			ID:     ctr.ID[:12],
-			Name:   name,
-			Image:  ctr.Image,
-			Status: ctr.Status,
+			ID:      ctr.ID,
+			Name:    SummaryName(ctr.Summary),
+			Image:   ctr.Image,
+			ImageID: ctr.ImageID,
+			Status:  ctr.Status,
+			Created: time.Unix(ctr.Created, 0),
+			Host:    ctr.Host,
+			Labels:  ctr.Labels,
 		})
 	}
 
 	return result, nil
 }
 
-func (ds *DockerService) StreamLogs(ctx context.Context, containerID string, logCh chan<- LogEntry) error {
-	// Use docker command directly - we know this works!
-	cmd := exec.Command("docker", "logs", "-f", "--timestamps", "--tail", "100", containerID)
-	
-	stdout, err := cmd.StdoutPipe()
-	if err != nil {
-		return err
+// ListContainerSummaries returns Docker's summaries (image ID, labels, ports, mounts,
+// networks) of running containers, or of all containers when all is set, across all
+// hosts. Hosts that can't be reached are skipped unless every host fails.
+func (ds *DockerService) ListContainerSummaries(ctx context.Context, all bool) ([]ContainerSummary, error) {
+	var result []ContainerSummary
+	var lastErr error
+	reached := 0
+	for _, host := range ds.hosts {
+		summaries, err := host.client.ContainerList(ctx, container.ListOptions{All: all})
+		if err != nil {
+			lastErr = fmt.Errorf("failed to list containers on %s: %w", host.label, err)
+			continue
+		}
+		reached++
+
+		ds.mu.Lock()
+		for _, summary := range summaries {
+			ds.owners[summary.ID] = host
+			result = append(result, ContainerSummary{Summary: summary, Host: host.label})
+		}
+		ds.mu.Unlock()
 	}
-	
-	stderr, err := cmd.StderrPipe()
+
+	if reached == 0 {
+		return nil, lastErr
+	}
+	return result, nil
+}
+
+// DisplayName returns the container name, prefixed with its host label when
+// containers come from several hosts and with its recording's label when imported
+// (capture:web)
+func (c Container) DisplayName() string {
+	name := c.Name
+	if c.Host != "" {
+		name = c.Host + "/" + name
+	}
+	if c.Origin != "" {
+		name = c.Origin + ":" + name
+	}
+	return name
+}
+
+// ShortID returns the 12-character form of a container ID used for display
+func ShortID(id string) string {
+	if utf8.RuneCountInString(id) > 12 {
+		return string([]rune(id)[:12])
+	}
+	return id
+}
+
+// Truncate shortens s for display to at most width characters, ending in "..." when
+// cut. It counts runes, not bytes, so multibyte names are never cut mid-character.
+func Truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width <= 3 {
+		return string(runes[:max(width, 0)])
+	}
+	return string(runes[:width-3]) + "..."
+}
+
+// SummaryName returns a listed container's name. Some runtimes list containers
+// without names; those fall back to the short ID, or the image without an ID.
+func SummaryName(summary container.Summary) string {
+	if len(summary.Names) > 0 && summary.Names[0] != "" {
+		return strings.TrimPrefix(summary.Names[0], "/")
+	}
+	if summary.ID != "" {
+		return ShortID(summary.ID)
+	}
+	return summary.Image
+}
+
+// LogStreamOptions selects what StreamLogs reads, like the flags of docker logs
+type LogStreamOptions struct {
+	Tail       int       // start with this many of the most recent lines; negative = all
+	Since      time.Time // only lines logged since then (zero = no limit)
+	Until      time.Time // only lines logged before then (zero = no limit)
+	Follow     bool      // keep streaming new lines until ctx is cancelled
+	Timestamps bool      // have Docker prefix each line with its timestamp
+	// Streams to read; when neither is set, both are
+	Stdout bool
+	Stderr bool
+}
+
+// DefaultLogStreamOptions follows the last 100 lines of both streams with timestamps,
+// as the panes do
+func DefaultLogStreamOptions() LogStreamOptions {
+	return LogStreamOptions{Tail: 100, Follow: true, Timestamps: true}
+}
+
+// dockerOptions translates the options for the Docker API
+func (o LogStreamOptions) dockerOptions() container.LogsOptions {
+	options := container.LogsOptions{
+		ShowStdout: o.Stdout || !o.Stderr,
+		ShowStderr: o.Stderr || !o.Stdout,
+		Follow:     o.Follow,
+		Timestamps: o.Timestamps,
+		Tail:       "all",
+	}
+	if o.Tail >= 0 {
+		options.Tail = strconv.Itoa(o.Tail)
+	}
+	if !o.Since.IsZero() {
+		options.Since = o.Since.Format(time.RFC3339Nano)
+	}
+	if !o.Until.IsZero() {
+		options.Until = o.Until.Format(time.RFC3339Nano)
+	}
+	return options
+}
+
+// StreamLogs reads a container's logs as opts selects and sends each entry to logCh,
+// which is closed when the stream ends (at once for a non-following read once the
+// lines are sent). Cancelling ctx stops the stream and closes the underlying reader.
+func (ds *DockerService) StreamLogs(ctx context.Context, containerID string, opts LogStreamOptions, logCh chan<- LogEntry) error {
+	host, err := ds.hostFor(ctx, containerID)
 	if err != nil {
 		return err
 	}
-	
-	if err := cmd.Start(); err != nil {
-		return err
+	cli := host.client
+
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container %s: %w", containerID, err)
 	}
 
-	// Function to handle scanning from a pipe
-	scanPipe := func(scanner *bufio.Scanner, stream string) {
-		for scanner.Scan() {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				line := scanner.Text()
-				if line != "" {
-					entry := parseLogEntry(containerID, line)
-					if entry.Message != "" {
-						entry.Stream = stream
-						select {
-						case logCh <- entry:
-						case <-ctx.Done():
-							return
-						}
-					}
-				}
-			}
-		}
+	reader, err := cli.ContainerLogs(ctx, containerID, opts.dockerOptions())
+	if err != nil {
+		return fmt.Errorf("failed to stream logs for container %s: %w", containerID, err)
 	}
 
 	go func() {
 		defer close(logCh)
-		defer stdout.Close()
-		defer stderr.Close()
-		defer cmd.Process.Kill()
-		
-		// Create scanners for both stdout and stderr
-		stdoutScanner := bufio.NewScanner(stdout)
-		stderrScanner := bufio.NewScanner(stderr)
-		
-		buf := make([]byte, 0, 64*1024)
-		stdoutScanner.Buffer(buf, 1024*1024)
-		stderrScanner.Buffer(buf, 1024*1024)
-		
-		// Start goroutines to read from both streams
-		done := make(chan bool, 2)
-		
-		go func() {
-			scanPipe(stdoutScanner, "stdout")
-			done <- true
-		}()
-		
-		go func() {
-			scanPipe(stderrScanner, "stderr")
-			done <- true
-		}()
-		
-		// Wait for either context cancellation or both streams to finish
-		finished := 0
-		for finished < 2 {
+		defer reader.Close()
+
+		// Reads block until the container logs again, so closing the reader is what
+		// actually stops the stream on cancellation
+		stop := context.AfterFunc(ctx, func() { reader.Close() })
+		defer stop()
+
+		// The same demuxing as GetLogs, so followed lines match one-shot reads and
+		// keep their order across stdout and stderr
+		tty := info.Config != nil && info.Config.Tty
+		readLogEntries(reader, tty, containerID, host.label, func(entry LogEntry) {
 			select {
+			case logCh <- entry:
 			case <-ctx.Done():
-				return
-			case <-done:
-				finished++
 			}
-		}
+		})
 	}()
 
 	return nil
@@ -359,25 +614,181 @@ func (ds *DockerService) StreamLogs(ctx context.Context, containerID string, log
 
 // RestartContainer restarts a running container
 func (ds *DockerService) RestartContainer(ctx context.Context, containerID string) error {
-	return ds.client.ContainerRestart(ctx, containerID, container.StopOptions{})
+	cli, err := ds.clientFor(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return cli.ContainerRestart(ctx, containerID, container.StopOptions{})
 }
 
 // KillContainer forcefully kills a running container
 func (ds *DockerService) KillContainer(ctx context.Context, containerID string) error {
-	return ds.client.ContainerKill(ctx, containerID, "SIGKILL")
+	cli, err := ds.clientFor(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	return cli.ContainerKill(ctx, containerID, "SIGKILL")
+}
+
+// Inspect returns the container's full inspect data with secret-looking
+// environment variable values redacted
+func (ds *DockerService) Inspect(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	cli, err := ds.clientFor(ctx, containerID)
+	if err != nil {
+		return container.InspectResponse{}, err
+	}
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return info, err
+	}
+	if info.Config != nil {
+		info.Config.Env = RedactEnv(info.Config.Env)
+	}
+	return info, nil
+}
+
+// ContainerState is the part of a container's inspected state shown in its pane
+type ContainerState struct {
+	Health     string // healthcheck status, empty without a healthcheck
+	Running    bool
+	StartedAt  time.Time
+	FinishedAt time.Time // zero while the container has never exited
+}
+
+// State inspects the container's health and when it last started and exited
+func (ds *DockerService) State(ctx context.Context, containerID string) (ContainerState, error) {
+	cli, err := ds.clientFor(ctx, containerID)
+	if err != nil {
+		return ContainerState{}, err
+	}
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return ContainerState{}, err
+	}
+	if info.State == nil {
+		return ContainerState{}, nil
+	}
+
+	state := ContainerState{Running: info.State.Running}
+	if info.State.Health != nil && info.State.Health.Status != container.NoHealthcheck {
+		state.Health = string(info.State.Health.Status)
+	}
+	// Docker reports "0001-01-01T00:00:00Z" for times that never happened
+	state.StartedAt, _ = time.Parse(time.RFC3339Nano, info.State.StartedAt)
+	state.FinishedAt, _ = time.Parse(time.RFC3339Nano, info.State.FinishedAt)
+	return state, nil
+}
+
+// Health returns the container's healthcheck status ("healthy", "unhealthy" or
+// "starting"), or "" when it has no healthcheck
+func (ds *DockerService) Health(ctx context.Context, containerID string) (string, error) {
+	state, err := ds.State(ctx, containerID)
+	return state.Health, err
+}
+
+// RestartPolicy returns the container's restart policy name ("no", "always",
+// "unless-stopped" or "on-failure")
+func (ds *DockerService) RestartPolicy(ctx context.Context, containerID string) (string, error) {
+	cli, err := ds.clientFor(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	if info.HostConfig == nil || info.HostConfig.RestartPolicy.Name == "" {
+		return "no", nil
+	}
+	return string(info.HostConfig.RestartPolicy.Name), nil
+}
+
+// ImageDigest returns the repo digest ("nginx@sha256:…") of the image a container
+// runs, or "" for images that were built locally and never pushed or pulled
+func (ds *DockerService) ImageDigest(ctx context.Context, containerID, image, imageID string) (string, error) {
+	cli, err := ds.clientFor(ctx, containerID)
+	if err != nil {
+		return "", err
+	}
+	info, err := cli.ImageInspect(ctx, imageID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect image %s: %w", imageID, err)
+	}
+	return pickRepoDigest(image, info.RepoDigests), nil
+}
+
+// pickRepoDigest picks the digest of image's repository from an image's repo
+// digests; images pushed to several registries have one per repository
+func pickRepoDigest(image string, digests []string) string {
+	repo, _, _ := strings.Cut(image, "@")
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i] // drop the tag, but not a registry port
+	}
+	for _, digest := range digests {
+		if strings.HasPrefix(digest, repo+"@") {
+			return digest
+		}
+	}
+	if len(digests) > 0 {
+		return digests[0]
+	}
+	return ""
+}
+
+// RestartsAfterKill reports whether Docker brings a container with this restart
+// policy straight back after it is killed
+func RestartsAfterKill(policy string) bool {
+	return policy == "always" || policy == "unless-stopped"
+}
+
+// secretEnvMarkers identify environment variable names whose values are redacted
+var secretEnvMarkers = []string{"PASSWORD", "PASSWD", "SECRET", "TOKEN", "KEY", "CREDENTIAL", "AUTH"}
+
+// RedactEnv masks the values of KEY=VALUE entries whose key looks like a secret
+func RedactEnv(env []string) []string {
+	redacted := make([]string, len(env))
+	for i, entry := range env {
+		redacted[i] = entry
+		key, _, found := strings.Cut(entry, "=")
+		if !found {
+			continue
+		}
+		upper := strings.ToUpper(key)
+		for _, marker := range secretEnvMarkers {
+			if strings.Contains(upper, marker) {
+				redacted[i] = key + "=***"
+				break
+			}
+		}
+	}
+	return redacted
 }
 
 // GetRecentLogs gets a specific number of recent log entries from a container using Docker SDK
 func (ds *DockerService) GetRecentLogs(ctx context.Context, containerID string, tail int) ([]LogEntry, error) {
+	return ds.GetLogs(ctx, containerID, LogStreamOptions{Tail: tail, Timestamps: true})
+}
+
+// GetLogs reads a container's logs once, as opts selects (Follow is ignored), and
+// returns them when Docker has sent them all. Prefer it to a StreamLogs read for
+// one-shot reads: nothing is left running and no timeout is needed.
+func (ds *DockerService) GetLogs(ctx context.Context, containerID string, opts LogStreamOptions) ([]LogEntry, error) {
 	// Use Docker SDK - this works regardless of PATH issues
-	options := container.LogsOptions{
-		ShowStdout: true,
-		ShowStderr: true,
-		Timestamps: true,
-		Tail:       fmt.Sprintf("%d", tail),
-	}
+	opts.Follow = false
+	options := opts.dockerOptions()
 	
-	out, err := ds.client.ContainerLogs(ctx, containerID, options)
+	host, err := ds.hostFor(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	cli := host.client
+
+	info, err := cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect container %s: %w", containerID, err)
+	}
+
+	out, err := cli.ContainerLogs(ctx, containerID, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get logs for container %s: %w", containerID, err)
 	}
@@ -385,44 +796,13 @@ func (ds *DockerService) GetRecentLogs(ctx context.Context, containerID string,
 	
 	var logs []LogEntry
 	
-	// Docker API returns logs with a special header format
-	// Read raw bytes and handle the multiplexed stream
-	buf := make([]byte, 8) // Header is 8 bytes
-	var logData []byte
-	
-	for {
-		// Read header
-		n, err := out.Read(buf)
-		if err != nil || n == 0 {
-			break // End of stream
-		}
-		
-		// Extract payload size from header (bytes 4-7, big-endian)
-		if n >= 8 {
-			payloadSize := int(buf[4])<<24 | int(buf[5])<<16 | int(buf[6])<<8 | int(buf[7])
-			
-			// Read payload
-			payload := make([]byte, payloadSize)
-			n, err := out.Read(payload)
-			if err != nil || n != payloadSize {
-				break
-			}
-			
-			logData = append(logData, payload...)
-		}
-	}
-	
-	// Parse the collected log data
-	lines := strings.Split(string(logData), "\n")
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		
-		logEntry := parseLogEntry(containerID, line)
-		if !logEntry.Timestamp.IsZero() {
-			logs = append(logs, logEntry)
-		}
+	// Docker API returns logs with a special header format unless the container has a TTY
+	tty := info.Config != nil && info.Config.Tty
+	err = readLogEntries(out, tty, containerID, host.label, func(entry LogEntry) {
+		logs = append(logs, entry)
+	})
+	if err != nil {
+		return logs, fmt.Errorf("failed to read logs for container %s: %w", containerID, err)
 	}
 	
 	return logs, nil
@@ -433,6 +813,18 @@ type LogEntry struct {
 	Timestamp   time.Time
 	Message     string
 	Stream      string
+	// Host is the label of the Docker host the container runs on, empty unless
+	// several hosts are watched (COLOG_DOCKER_HOSTS)
+	Host string `json:",omitempty"`
+	// Source is the in-container log file the line was read from (see
+	// StreamLogFiles), empty for stdout/stderr
+	Source string `json:",omitempty"`
+	// Repeats is how many identical consecutive lines this entry stands for when
+	// repeats are collapsed (Timestamp is then the last one's); 0 for a single line
+	Repeats int `json:",omitempty"`
+	// Syslog is the parsed header of a syslog-formatted line (whose Message is then
+	// just the syslog message), nil for other lines
+	Syslog *SyslogHeader `json:",omitempty"`
 }
 
 func parseLogEntry(containerID, line string) LogEntry {
@@ -447,14 +839,14 @@ func parseLogEntry(containerID, line string) LogEntry {
 	
 	originalLine := line
 	
-	// Check if this line has the Docker multiplexed header
-	if len(line) >= 8 {
+	// Check if this line still has the Docker multiplexed header; raw TTY output
+	// has none and is always stdout
+	stream := "stdout"
+	if hasStreamHeader(line) {
 		// First byte indicates stream type: 1 for stdout, 2 for stderr
-		firstByte := byte(line[0])
-		if firstByte == 1 || firstByte == 2 {
-			// Skip the 8-byte header
-			line = line[8:]
-		}
+		stream = streamName(line[0])
+		line = line[8:]
+		originalLine = line
 	}
 	
 	// Trim whitespace after header removal
@@ -467,32 +859,18 @@ func parseLogEntry(containerID, line string) LogEntry {
 	parts := strings.SplitN(line, " ", 2)
 	var timestamp time.Time
 	var message string
+	hasTimestamp := false
 	
-	if len(parts) >= 2 {
-		// Try multiple timestamp formats
-		timestampFormats := []string{
-			time.RFC3339Nano,
-			time.RFC3339,
-			"2006-01-02T15:04:05.000000000Z",
-			"2006-01-02T15:04:05.000Z",
-		}
-		
-		parsed := false
-		for _, format := range timestampFormats {
-			if ts, err := time.Parse(format, parts[0]); err == nil {
-				timestamp = ts
-				message = parts[1]
-				parsed = true
-				break
-			}
-		}
-		
-		if !parsed {
-			// No valid timestamp found, treat entire line as message
-			timestamp = time.Now()
-			message = line
+	if ts, ok := parseLogTimestamp(parts[0]); ok {
+		if len(parts) < 2 {
+			// Docker sends an empty line as a bare timestamp
+			return LogEntry{}
 		}
+		timestamp = ts
+		message = parts[1]
+		hasTimestamp = true
 	} else {
+		// No valid timestamp found, treat entire line as message
 		timestamp = time.Now()
 		message = line
 	}
@@ -502,10 +880,125 @@ func parseLogEntry(containerID, line string) LogEntry {
 		message = strings.TrimSpace(originalLine)
 	}
 
-	return LogEntry{
+	entry := LogEntry{
 		ContainerID: containerID,
 		Timestamp:   timestamp,
 		Message:     message,
-		Stream:      "stdout",
+		Stream:      stream,
+	}
+
+	if syslogEnabled() {
+		if header, syslogMessage, ok := ParseSyslog(message); ok {
+			entry.Syslog = &header
+			entry.Message = syslogMessage
+			// Docker's timestamp is more precise, use the syslog one only as a fallback
+			if !hasTimestamp && !header.Timestamp.IsZero() {
+				entry.Timestamp = header.Timestamp
+			}
+		}
+	}
+
+	return entry
+}
+
+// logTimestampFormats are the timestamp formats accepted in front of a log line
+var logTimestampFormats = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05.000000000Z",
+	"2006-01-02T15:04:05.000Z",
+}
+
+// parseLogTimestamp parses the timestamp Docker puts in front of each line. Only the
+// first token of a line is ever tried, so a message that itself starts with a date
+// keeps it.
+func parseLogTimestamp(token string) (time.Time, bool) {
+	for _, format := range logTimestampFormats {
+		if ts, err := time.Parse(format, token); err == nil {
+			return ts, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// hasStreamHeader reports whether line starts with an 8-byte stdcopy header: a stream
+// byte of 1 or 2 followed by three zero bytes of padding
+func hasStreamHeader(line string) bool {
+	return len(line) >= 8 && (line[0] == 1 || line[0] == 2) && line[1] == 0 && line[2] == 0 && line[3] == 0
+}
+
+// streamName maps a stdcopy stream byte to the LogEntry.Stream value
+func streamName(streamType byte) string {
+	if streamType == 2 {
+		return "stderr"
+	}
+	return "stdout"
+}
+
+// readLogEntries parses a ContainerLogs response into entries of containerID on the
+// host labeled host, skipping blank lines. GetLogs and StreamLogs both read with it.
+func readLogEntries(r io.Reader, tty bool, containerID, host string, fn func(LogEntry)) error {
+	return readLogLines(r, tty, func(line, stream string) {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		entry := parseLogEntry(containerID, line)
+		if entry.Message == "" {
+			return
+		}
+		entry.Stream = stream
+		entry.Host = host
+		fn(entry)
+	})
+}
+
+// readLogLines splits a ContainerLogs response into lines tagged with their stream.
+// Containers with a TTY send raw output, which is all stdout; otherwise each frame
+// starts with an 8-byte header (stream byte, padding, big-endian payload length).
+func readLogLines(r io.Reader, tty bool, fn func(line, stream string)) error {
+	if tty {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			fn(scanner.Text(), "stdout")
+		}
+		return scanner.Err()
 	}
-}
\ No newline at end of file
+
+	// A line may be split across frames, so keep the unfinished tail per stream
+	pending := make(map[string][]byte)
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				break
+			}
+			return err
+		}
+
+		stream := streamName(header[0])
+		payloadSize := int(header[4])<<24 | int(header[5])<<16 | int(header[6])<<8 | int(header[7])
+		payload := make([]byte, payloadSize)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return err
+		}
+
+		data := append(pending[stream], payload...)
+		for {
+			i := bytes.IndexByte(data, '\n')
+			if i < 0 {
+				break
+			}
+			fn(string(data[:i]), stream)
+			data = data[i+1:]
+		}
+		pending[stream] = data
+	}
+
+	for _, stream := range []string{"stdout", "stderr"} {
+		if len(pending[stream]) > 0 {
+			fn(string(pending[stream]), stream)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,324 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func frame(streamType byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = streamType
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+type taggedLine struct {
+	line, stream string
+}
+
+func TestReadLogLinesMultiplexed(t *testing.T) {
+	var data []byte
+	data = append(data, frame(1, "out one\n")...)
+	data = append(data, frame(2, "err one\nerr ")...)
+	data = append(data, frame(1, "out two\n")...)
+	data = append(data, frame(2, "two\n")...)
+
+	var got []taggedLine
+	err := readLogLines(bytes.NewReader(data), false, func(line, stream string) {
+		got = append(got, taggedLine{line, stream})
+	})
+	if err != nil {
+		t.Fatalf("readLogLines: %v", err)
+	}
+
+	want := []taggedLine{
+		{"out one", "stdout"},
+		{"err one", "stderr"},
+		{"out two", "stdout"},
+		{"err two", "stderr"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReadLogEntriesKeepsStreamOrder(t *testing.T) {
+	long := strings.Repeat("x", 2*1024*1024) // over a scanner's 1 MiB line limit
+	var data []byte
+	data = append(data, frame(2, "2024-01-15T10:30:00Z err one\n")...)
+	data = append(data, frame(1, "2024-01-15T10:30:01Z "+long+"\n\n")...)
+	data = append(data, frame(2, "2024-01-15T10:30:02Z err two\n")...)
+
+	var got []LogEntry
+	err := readLogEntries(bytes.NewReader(data), false, "abc", "staging", func(entry LogEntry) {
+		got = append(got, entry)
+	})
+	if err != nil {
+		t.Fatalf("readLogEntries: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d entries, want 3 (blank lines skipped)", len(got))
+	}
+	for i, want := range []string{"stderr", "stdout", "stderr"} {
+		if got[i].Stream != want || got[i].Host != "staging" {
+			t.Errorf("entry %d: stream %s, host %q; want %s on staging", i, got[i].Stream, got[i].Host, want)
+		}
+	}
+	if got[1].Message != long || got[2].Message != "err two" {
+		t.Errorf("long line or the stderr line after it was lost")
+	}
+}
+
+func TestReadLogLinesTTYIsStdout(t *testing.T) {
+	// Raw TTY output that happens to start with a stream-like byte must not be demuxed
+	input := "\x02 not a header\nplain line\n"
+
+	var got []taggedLine
+	err := readLogLines(strings.NewReader(input), true, func(line, stream string) {
+		got = append(got, taggedLine{line, stream})
+	})
+	if err != nil {
+		t.Fatalf("readLogLines: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d lines, want 2", len(got))
+	}
+	for _, line := range got {
+		if line.stream != "stdout" {
+			t.Errorf("%q tagged %s, want stdout", line.line, line.stream)
+		}
+	}
+}
+
+func TestParseLogEntryStream(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{"stdout header", string(frame(1, "2024-01-02T03:04:05Z hello")), "stdout"},
+		{"stderr header", string(frame(2, "2024-01-02T03:04:05Z oops")), "stderr"},
+		{"no header", "2024-01-02T03:04:05Z plain", "stdout"},
+		{"stream byte without padding", "\x02abcdefgh message", "stdout"},
+	}
+	for _, tt := range tests {
+		if got := parseLogEntry("abc", tt.line).Stream; got != tt.want {
+			t.Errorf("%s: Stream = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseLogEntryTimestamp(t *testing.T) {
+	at := func(nsec int, loc *time.Location) time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, nsec, loc)
+	}
+	tests := []struct {
+		name    string
+		line    string
+		want    time.Time // zero when the line has no timestamp (parsed as now)
+		message string
+		stream  string
+	}{
+		{"RFC3339Nano", "2024-01-02T03:04:05.123456789Z hello", at(123456789, time.UTC), "hello", "stdout"},
+		{"RFC3339", "2024-01-02T03:04:05Z hello", at(0, time.UTC), "hello", "stdout"},
+		{"RFC3339 with offset", "2024-01-02T03:04:05+02:00 hello", at(0, time.FixedZone("", 2*3600)), "hello", "stdout"},
+		{"fixed nanoseconds", "2024-01-02T03:04:05.000000000Z hello", at(0, time.UTC), "hello", "stdout"},
+		{"milliseconds", "2024-01-02T03:04:05.120Z hello", at(120000000, time.UTC), "hello", "stdout"},
+		{"multiplexed header", string(frame(2, "2024-01-02T03:04:05Z oops")), at(0, time.UTC), "oops", "stderr"},
+		{"no timestamp", "plain message here", time.Time{}, "plain message here", "stdout"},
+		{"single word", "ready", time.Time{}, "ready", "stdout"},
+		{"message starts with a date", "2024-01-02T03:04:05Z 2023-12-31T23:59:59Z job started", at(0, time.UTC), "2023-12-31T23:59:59Z job started", "stdout"},
+		{"date without time", "2024-01-02 03:04:05 started", time.Time{}, "2024-01-02 03:04:05 started", "stdout"},
+		{"invalid date", "2024-13-45T03:04:05Z not a timestamp", time.Time{}, "2024-13-45T03:04:05Z not a timestamp", "stdout"},
+		{"no zone", "2024-01-02T03:04:05 local time", time.Time{}, "2024-01-02T03:04:05 local time", "stdout"},
+		{"keeps indentation", "2024-01-02T03:04:05Z   at main.go:12", at(0, time.UTC), "  at main.go:12", "stdout"},
+	}
+	for _, tt := range tests {
+		before := time.Now()
+		entry := parseLogEntry("abc", tt.line)
+		if entry.Message != tt.message || entry.Stream != tt.stream || entry.ContainerID != "abc" {
+			t.Errorf("%s: got %q on %s, want %q on %s", tt.name, entry.Message, entry.Stream, tt.message, tt.stream)
+		}
+		if tt.want.IsZero() {
+			if entry.Timestamp.Before(before) {
+				t.Errorf("%s: Timestamp = %v, want the time it was read", tt.name, entry.Timestamp)
+			}
+		} else if !entry.Timestamp.Equal(tt.want) {
+			t.Errorf("%s: Timestamp = %v, want %v", tt.name, entry.Timestamp, tt.want)
+		}
+	}
+}
+
+func TestParseLogEntryEmpty(t *testing.T) {
+	for _, line := range []string{
+		"",
+		"   ",
+		string(frame(1, "")),
+		string(frame(1, " \n")),
+		"2024-01-02T03:04:05.123456789Z",  // an empty line, as Docker sends it
+		"2024-01-02T03:04:05.123456789Z ", // the same with the separator
+	} {
+		if entry := parseLogEntry("abc", line); entry.Message != "" || !entry.Timestamp.IsZero() {
+			t.Errorf("parseLogEntry(%q) = %+v, want an empty entry", line, entry)
+		}
+	}
+}
+
+func TestHostLabel(t *testing.T) {
+	tests := []struct {
+		entry, label, host string
+	}{
+		{"staging=tcp://10.0.0.5:2375", "staging", "tcp://10.0.0.5:2375"},
+		{"tcp://build-box:2376", "build-box", "tcp://build-box:2376"},
+		{"unix:///var/run/docker.sock", "local", "unix:///var/run/docker.sock"},
+		{"tcp://host:2375/?a=b", "host", "tcp://host:2375/?a=b"},
+	}
+	for _, tt := range tests {
+		label, host := hostLabel(tt.entry)
+		if label != tt.label || host != tt.host {
+			t.Errorf("hostLabel(%q) = %q, %q; want %q, %q", tt.entry, label, host, tt.label, tt.host)
+		}
+	}
+}
+
+func TestContainerDisplayName(t *testing.T) {
+	if got := (Container{Name: "web"}).DisplayName(); got != "web" {
+		t.Errorf("single host: got %q", got)
+	}
+	if got := (Container{Name: "web", Host: "staging"}).DisplayName(); got != "staging/web" {
+		t.Errorf("multi host: got %q", got)
+	}
+}
+
+func TestPickRepoDigest(t *testing.T) {
+	digests := []string{
+		"ghcr.io/acme/web@sha256:aaa",
+		"registry:5000/web@sha256:bbb",
+		"nginx@sha256:ccc",
+	}
+	tests := []struct {
+		image string
+		want  string
+	}{
+		{"nginx:1.27", "nginx@sha256:ccc"},
+		{"registry:5000/web", "registry:5000/web@sha256:bbb"},
+		{"registry:5000/web:latest", "registry:5000/web@sha256:bbb"},
+		{"ghcr.io/acme/web@sha256:aaa", "ghcr.io/acme/web@sha256:aaa"},
+		{"sha256:deadbeef", "ghcr.io/acme/web@sha256:aaa"}, // no repository: first digest
+	}
+	for _, tt := range tests {
+		if got := pickRepoDigest(tt.image, digests); got != tt.want {
+			t.Errorf("pickRepoDigest(%q) = %q, want %q", tt.image, got, tt.want)
+		}
+	}
+	if got := pickRepoDigest("local:dev", nil); got != "" {
+		t.Errorf("local image: got %q, want empty", got)
+	}
+}
+
+func TestSummaryName(t *testing.T) {
+	id := "4f66ad9a0b2e1c3d5e7f9a1b3c5d7e9f0a2b4c6d8e0f1a3b5c7d9e1f3a5b7c9d"
+	tests := []struct {
+		name    string
+		summary container.Summary
+		want    string
+	}{
+		{"named", container.Summary{ID: id, Names: []string{"/web", "/alias"}, Image: "nginx"}, "web"},
+		{"no names", container.Summary{ID: id, Image: "nginx"}, "4f66ad9a0b2e"},
+		{"empty name", container.Summary{ID: id, Names: []string{""}, Image: "nginx"}, "4f66ad9a0b2e"},
+		{"no names or ID", container.Summary{Image: "nginx"}, "nginx"},
+	}
+	for _, tt := range tests {
+		if got := SummaryName(tt.summary); got != tt.want {
+			t.Errorf("%s: SummaryName = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"web", 20, "web"},
+		{"exactly-twenty-chars", 20, "exactly-twenty-chars"},
+		{"a-very-long-container-name", 20, "a-very-long-conta..."},
+		{"データベース-サーバー-プライマリ", 10, "データベース-..."},
+		{"Up 5 minutes – café ☕ and more", 22, "Up 5 minutes – café..."},
+		{"日本語", 3, "日本語"},
+		{"日本語です", 2, "日本"},
+		{"anything", 0, ""},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if !utf8.ValidString(got) || utf8.RuneCountInString(got) > tt.width {
+			t.Errorf("Truncate(%q, %d) = %q is invalid or wider than %d", tt.s, tt.width, got, tt.width)
+		}
+	}
+}
+
+func TestShortIDMultibyte(t *testing.T) {
+	if got := ShortID("コンテナ名前がとても長いです"); got != "コンテナ名前がとても長い" {
+		t.Errorf("ShortID = %q, want the first 12 characters", got)
+	}
+	if got := ShortID("4f66ad9a0b2e1c3d"); got != "4f66ad9a0b2e" {
+		t.Errorf("ShortID = %q", got)
+	}
+}
+
+func TestLogStreamOptionsDockerOptions(t *testing.T) {
+	got := DefaultLogStreamOptions().dockerOptions()
+	want := container.LogsOptions{ShowStdout: true, ShowStderr: true, Follow: true, Timestamps: true, Tail: "100"}
+	if got != want {
+		t.Errorf("default options = %+v, want %+v", got, want)
+	}
+
+	since := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	got = LogStreamOptions{Tail: -1, Since: since, Until: since.Add(time.Hour), Stderr: true}.dockerOptions()
+	want = container.LogsOptions{ShowStderr: true, Tail: "all", Since: "2025-03-04T12:00:00Z", Until: "2025-03-04T13:00:00Z"}
+	if got != want {
+		t.Errorf("options = %+v, want %+v", got, want)
+	}
+}
+
+// staticLogs is a LogReader returning the same lines for any read
+type staticLogs []LogEntry
+
+func (s staticLogs) GetLogs(ctx context.Context, containerID string, opts LogStreamOptions) ([]LogEntry, error) {
+	return s, nil
+}
+
+func TestProbeStall(t *testing.T) {
+	lastSeen := time.Now().Add(-time.Minute)
+	cases := []struct {
+		name string
+		logs staticLogs
+		want StreamState
+	}{
+		{"nothing new", staticLogs{{Timestamp: lastSeen, Message: "last delivered"}}, StreamQuiet},
+		{"undelivered line", staticLogs{{Timestamp: lastSeen}, {Timestamp: lastSeen.Add(30 * time.Second)}}, StreamStalled},
+		{"line still in flight", staticLogs{{Timestamp: time.Now()}}, StreamQuiet},
+	}
+	for _, c := range cases {
+		got, err := ProbeStall(context.Background(), c.logs, "web", lastSeen)
+		if err != nil || got != c.want {
+			t.Errorf("%s: got %q, %v; want %q", c.name, got, err, c.want)
+		}
+	}
+}
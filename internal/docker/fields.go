@@ -0,0 +1,178 @@
+package docker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LogFields names the JSON fields that hold a structured log line's level, message
+// and timestamp. Keys are checked in order; dotted keys such as "log.level" also
+// match nested objects.
+type LogFields struct {
+	Level   []string
+	Message []string
+	Time    []string
+}
+
+// NewLogFields returns the default field names, overridable with the comma-separated
+// COLOG_LEVEL_FIELDS, COLOG_MSG_FIELDS and COLOG_TS_FIELDS
+func NewLogFields() *LogFields {
+	return &LogFields{
+		Level:   fieldsFromEnv("COLOG_LEVEL_FIELDS", []string{"level", "lvl", "severity", "log.level", "loglevel"}),
+		Message: fieldsFromEnv("COLOG_MSG_FIELDS", []string{"msg", "message", "log"}),
+		Time:    fieldsFromEnv("COLOG_TS_FIELDS", []string{"ts", "time", "timestamp", "@timestamp"}),
+	}
+}
+
+// StructuredLog is a JSON log line split into its well-known fields
+type StructuredLog struct {
+	Level    LogLevel
+	HasLevel bool      // whether a level field held a recognizable level
+	Message  string    // the message field, empty when there is none
+	Time     time.Time // the timestamp field, zero when missing or unparseable
+	Extra    string    // the remaining fields as sorted key=value pairs
+}
+
+// ParseJSONLog parses message as a JSON object log line, using the default field
+// names when fields is nil. ok is false when the message isn't a JSON object.
+func ParseJSONLog(message string, fields *LogFields) (log StructuredLog, ok bool) {
+	if fields == nil {
+		fields = NewLogFields()
+	}
+	trimmed := strings.TrimSpace(message)
+	if !strings.HasPrefix(trimmed, "{") {
+		return log, false
+	}
+	var object map[string]interface{}
+	if json.Unmarshal([]byte(trimmed), &object) != nil {
+		return log, false
+	}
+
+	used := make(map[string]bool)
+	if key, value, found := lookupField(object, fields.Level); found {
+		if name, isString := value.(string); isString {
+			log.Level, log.HasLevel = ParseLogLevel(name)
+			used[key] = log.HasLevel
+		}
+	}
+	if key, value, found := lookupField(object, fields.Message); found {
+		if text, isString := value.(string); isString {
+			log.Message = text
+			used[key] = true
+		}
+	}
+	if key, value, found := lookupField(object, fields.Time); found {
+		if ts, parsed := parseFieldTime(value); parsed {
+			log.Time = ts
+			used[key] = true
+		}
+	}
+
+	var keys []string
+	for key := range object {
+		if !used[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+fieldString(object[key]))
+	}
+	log.Extra = strings.Join(pairs, " ")
+
+	return log, true
+}
+
+// Display renders the line for a log view: the message followed by the remaining
+// fields, or just the fields when there is no message field
+func (l StructuredLog) Display() string {
+	if l.Message == "" {
+		return l.Extra
+	}
+	if l.Extra == "" {
+		return l.Message
+	}
+	return l.Message + "  " + l.Extra
+}
+
+// lookupField returns the first of keys present in object, following dots into
+// nested objects when the dotted key itself isn't present. The returned key is the
+// top-level key the value lives under.
+func lookupField(object map[string]interface{}, keys []string) (string, interface{}, bool) {
+	for _, key := range keys {
+		if value, ok := object[key]; ok {
+			return key, value, true
+		}
+		if !strings.Contains(key, ".") {
+			continue
+		}
+		var current interface{} = object
+		for _, part := range strings.Split(key, ".") {
+			nested, isObject := current.(map[string]interface{})
+			if !isObject {
+				current = nil
+				break
+			}
+			current = nested[part]
+		}
+		if current != nil {
+			root, _, _ := strings.Cut(key, ".")
+			return root, current, true
+		}
+	}
+	return "", nil, false
+}
+
+// parseFieldTime accepts RFC3339 strings and Unix timestamps in seconds or milliseconds
+func parseFieldTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return ts, true
+		}
+	case float64:
+		if v > 1e12 {
+			return time.UnixMilli(int64(v)), true
+		}
+		sec := int64(v)
+		return time.Unix(sec, int64((v-float64(sec))*1e9)), true
+	}
+	return time.Time{}, false
+}
+
+func fieldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		if strings.ContainsAny(v, " \t\"") {
+			return fmt.Sprintf("%q", v)
+		}
+		return v
+	case nil:
+		return "null"
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+func fieldsFromEnv(key string, defaults []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaults
+	}
+
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
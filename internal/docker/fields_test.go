@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseJSONLog(t *testing.T) {
+	fields := &LogFields{
+		Level:   []string{"level", "log.level"},
+		Message: []string{"msg"},
+		Time:    []string{"ts"},
+	}
+
+	log, ok := ParseJSONLog(`{"level":"warn","msg":"slow query","ts":"2025-01-02T03:04:05Z","ms":812,"user":"a b"}`, fields)
+	if !ok {
+		t.Fatal("expected a JSON log")
+	}
+	if !log.HasLevel || log.Level != LevelWarn {
+		t.Errorf("level = %v (%v), want WARN", log.Level, log.HasLevel)
+	}
+	if log.Message != "slow query" {
+		t.Errorf("Message = %q", log.Message)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC); !log.Time.Equal(want) {
+		t.Errorf("Time = %v, want %v", log.Time, want)
+	}
+	if got, want := log.Display(), `slow query  ms=812 user="a b"`; got != want {
+		t.Errorf("Display() = %q, want %q", got, want)
+	}
+
+	nested, ok := ParseJSONLog(`{"log":{"level":"ERROR"},"msg":"boom"}`, fields)
+	if !ok || !nested.HasLevel || nested.Level != LevelError {
+		t.Errorf("nested level = %v (%v), want ERROR", nested.Level, nested.HasLevel)
+	}
+
+	if _, ok := ParseJSONLog("level=error not json", fields); ok {
+		t.Error("logfmt line parsed as JSON")
+	}
+}
+
+func TestLevelMatcherPrefersJSONLevel(t *testing.T) {
+	matcher := &LevelMatcher{Error: []string{"error"}, Warn: []string{"warn"}, Fields: NewLogFields()}
+
+	tests := []struct {
+		message string
+		want    LogLevel
+	}{
+		// The level field wins over keywords in the message text
+		{`{"level":"info","msg":"recovered from error"}`, LevelInfo},
+		{`{"severity":"ERROR","message":"timeout"}`, LevelError},
+		// Without a level field the keywords still apply
+		{`{"msg":"warn: cache cold"}`, LevelWarn},
+		{"plain error line", LevelError},
+	}
+	for _, tt := range tests {
+		if got := matcher.Match(tt.message); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+}
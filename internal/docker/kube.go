@@ -0,0 +1,93 @@
+package docker
+
+import (
+	"path"
+	"sort"
+)
+
+// Labels the kubelet puts on containers it runs through a Docker-compatible runtime
+const (
+	LabelPodName       = "io.kubernetes.pod.name"
+	LabelPodNamespace  = "io.kubernetes.pod.namespace"
+	LabelContainerName = "io.kubernetes.container.name"
+	labelDockerType    = "io.kubernetes.docker.type"
+)
+
+// PodName is the Kubernetes pod the container belongs to, empty outside Kubernetes
+func (c Container) PodName() string {
+	return c.Labels[LabelPodName]
+}
+
+// Namespace is the Kubernetes namespace of the container's pod
+func (c Container) Namespace() string {
+	return c.Labels[LabelPodNamespace]
+}
+
+// isPodSandbox reports whether the container is a pod's pause container, which
+// only holds the pod's namespaces and never logs anything
+func (c Container) isPodSandbox() bool {
+	return c.Labels[labelDockerType] == "podsandbox" || c.Labels[LabelContainerName] == "POD"
+}
+
+// podLabel names a pod container "pod/container", prefixed with the namespace
+// outside of "default"
+func (c Container) podLabel() string {
+	name := c.Labels[LabelContainerName]
+	if name == "" {
+		name = c.Name
+	}
+	label := c.PodName() + "/" + name
+	if namespace := c.Namespace(); namespace != "" && namespace != "default" {
+		label = namespace + "/" + label
+	}
+	return label
+}
+
+// PodFilter selects pod containers by namespace and pod name. Empty fields match
+// everything; both accept glob patterns such as "web-*".
+type PodFilter struct {
+	Namespace string
+	Pod       string
+}
+
+// Match reports whether the container belongs to a pod selected by the filter
+func (f PodFilter) Match(c Container) bool {
+	if c.PodName() == "" || c.isPodSandbox() {
+		return false
+	}
+	return globMatch(f.Namespace, c.Namespace()) && globMatch(f.Pod, c.PodName())
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
+
+// PodContainers keeps the pod containers selected by filter, renames them after
+// their pod and container ("pod/container") and orders them by namespace and pod so
+// the containers of a pod sit next to each other
+func PodContainers(containers []Container, filter PodFilter) []Container {
+	var result []Container
+	for _, ctr := range containers {
+		if !filter.Match(ctr) {
+			continue
+		}
+		ctr.Name = ctr.podLabel()
+		result = append(result, ctr)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Namespace() != b.Namespace() {
+			return a.Namespace() < b.Namespace()
+		}
+		if a.PodName() != b.PodName() {
+			return a.PodName() < b.PodName()
+		}
+		return a.Name < b.Name
+	})
+	return result
+}
@@ -0,0 +1,43 @@
+package docker
+
+import "testing"
+
+func podContainer(id, namespace, pod, name string) Container {
+	return Container{
+		ID:   id,
+		Name: "k8s_" + name + "_" + pod,
+		Labels: map[string]string{
+			LabelPodNamespace:  namespace,
+			LabelPodName:       pod,
+			LabelContainerName: name,
+		},
+	}
+}
+
+func TestPodContainers(t *testing.T) {
+	sandbox := podContainer("5", "default", "web-1", "POD")
+	containers := []Container{
+		podContainer("1", "shop", "web-2", "app"),
+		{ID: "2", Name: "plain"},
+		podContainer("3", "default", "web-1", "sidecar"),
+		podContainer("4", "default", "web-1", "app"),
+		sandbox,
+		podContainer("6", "default", "db-0", "postgres"),
+	}
+
+	got := PodContainers(containers, PodFilter{})
+	want := []string{"db-0/postgres", "web-1/app", "web-1/sidecar", "shop/web-2/app"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d containers, want %d: %+v", len(got), len(want), got)
+	}
+	for i, name := range want {
+		if got[i].Name != name {
+			t.Errorf("container %d = %q, want %q", i, got[i].Name, name)
+		}
+	}
+
+	got = PodContainers(containers, PodFilter{Namespace: "default", Pod: "web-*"})
+	if len(got) != 2 || got[0].ID != "4" || got[1].ID != "3" {
+		t.Errorf("filtered = %+v, want the web-1 app and sidecar", got)
+	}
+}
@@ -0,0 +1,169 @@
+package docker
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// LogLevel is the severity detected for a log message
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// String returns the display name of the level
+func (l LogLevel) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	default:
+		return "INFO"
+	}
+}
+
+// ParseLogLevel converts a level name such as "warn" or "ERROR" to a LogLevel
+func ParseLogLevel(name string) (LogLevel, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug", "trace":
+		return LevelDebug, true
+	case "info", "notice", "all", "":
+		return LevelInfo, true
+	case "warn", "warning":
+		return LevelWarn, true
+	case "error", "err", "fatal", "critical", "crit", "panic":
+		return LevelError, true
+	}
+	return LevelInfo, false
+}
+
+var (
+	// logfmtLevelPattern matches level=warn, lvl="error", severity=INFO
+	logfmtLevelPattern = regexp.MustCompile(`(?i)\b(?:level|lvl|severity)="?([a-z]+)"?`)
+	// prefixLevelPattern matches a leading level such as "[ERROR]", "WARN:", "<info>",
+	// optionally after a timestamp
+	prefixLevelPattern = regexp.MustCompile(`(?i)^\s*(?:[\d\-/T:.,Z+]+\s+)?[\[<(]?(trace|debug|info|notice|warn|warning|error|err|fatal|critical|crit|panic)[\]>)]?(?::|\s|$)`)
+)
+
+// DetectLevel parses the level a log message states: the level field of a JSON
+// line (named by fields; nil skips JSON), a logfmt level=... pair, or a leading
+// "[ERROR]"/"WARN:"/"panic:" prefix. ok is false when the message states no level.
+func DetectLevel(message string, fields *LogFields) (level LogLevel, ok bool) {
+	trimmed := strings.TrimSpace(message)
+
+	if fields != nil {
+		if structured, ok := ParseJSONLog(trimmed, fields); ok && structured.HasLevel {
+			return structured.Level, true
+		}
+	}
+
+	if match := logfmtLevelPattern.FindStringSubmatch(trimmed); match != nil {
+		if level, ok := ParseLogLevel(match[1]); ok {
+			return level, true
+		}
+	}
+
+	if match := prefixLevelPattern.FindStringSubmatch(trimmed); match != nil {
+		if level, ok := ParseLogLevel(match[1]); ok {
+			return level, true
+		}
+	}
+
+	return LevelInfo, false
+}
+
+// DetectEntryLevel is DetectLevel for a log entry: syslog lines use their severity
+func DetectEntryLevel(entry LogEntry, fields *LogFields) (level LogLevel, ok bool) {
+	if entry.Syslog != nil {
+		return entry.Syslog.Level(), true
+	}
+	return DetectLevel(entry.Message, fields)
+}
+
+// LevelMatcher detects log levels from the level stated in log lines (see
+// DetectLevel), falling back to message keywords. The TUI and the SDK share it, so
+// a line has the same level in a pane as in an export or level filter.
+type LevelMatcher struct {
+	Error  []string
+	Warn   []string
+	Debug  []string
+	Fields *LogFields // JSON field names; nil matches keywords only
+}
+
+// NewLevelMatcher returns a matcher with the default keywords, overridable with the
+// comma-separated COLOG_ERROR_PATTERNS, COLOG_WARN_PATTERNS and COLOG_DEBUG_PATTERNS
+func NewLevelMatcher() *LevelMatcher {
+	return &LevelMatcher{
+		Error:  patternsFromEnv("COLOG_ERROR_PATTERNS", []string{"error", "exception", "fail", "fatal", "panic", "critical"}),
+		Warn:   patternsFromEnv("COLOG_WARN_PATTERNS", []string{"warn", "deprecated"}),
+		Debug:  patternsFromEnv("COLOG_DEBUG_PATTERNS", []string{"debug", "trace"}),
+		Fields: NewLogFields(),
+	}
+}
+
+// Match returns the level a log line states, or otherwise the most severe level
+// whose keywords appear in the message. Messages without any keyword are treated
+// as info. A matcher without Fields only matches keywords.
+func (m *LevelMatcher) Match(message string) LogLevel {
+	if m.Fields != nil {
+		if level, ok := DetectLevel(message, m.Fields); ok {
+			return level
+		}
+	}
+
+	return m.matchKeywords(message)
+}
+
+// MatchEntry is Match for a log entry, preferring the severity of syslog lines
+func (m *LevelMatcher) MatchEntry(entry LogEntry) LogLevel {
+	if entry.Syslog != nil {
+		return entry.Syslog.Level()
+	}
+	return m.Match(entry.Message)
+}
+
+func (m *LevelMatcher) matchKeywords(message string) LogLevel {
+	lower := strings.ToLower(message)
+	if containsAny(lower, m.Error) {
+		return LevelError
+	}
+	if containsAny(lower, m.Warn) {
+		return LevelWarn
+	}
+	if containsAny(lower, m.Debug) {
+		return LevelDebug
+	}
+	return LevelInfo
+}
+
+func containsAny(text string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if strings.Contains(text, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func patternsFromEnv(key string, defaults []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaults
+	}
+
+	var patterns []string
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.ToLower(strings.TrimSpace(pattern)); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
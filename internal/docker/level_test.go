@@ -0,0 +1,28 @@
+package docker
+
+import "testing"
+
+func TestLevelMatcherPrefersStatedLevel(t *testing.T) {
+	matcher := NewLevelMatcher()
+	tests := []struct {
+		message string
+		want    LogLevel
+	}{
+		{"INFO: retrying after failed attempt", LevelInfo},
+		{"[WARN] panic averted", LevelWarn},
+		{"panic: runtime error: index out of range", LevelError},
+		{"level=debug msg=\"error budget ok\"", LevelDebug},
+		{"connection failed", LevelError},
+		{"GET /health 200", LevelInfo},
+	}
+	for _, tt := range tests {
+		if got := matcher.Match(tt.message); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.message, got, tt.want)
+		}
+	}
+
+	keywordsOnly := &LevelMatcher{Error: []string{"fail"}}
+	if got := keywordsOnly.Match("INFO: retrying after failed attempt"); got != LevelError {
+		t.Errorf("a matcher without fields matched %v, want keywords only", got)
+	}
+}
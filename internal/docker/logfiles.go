@@ -0,0 +1,122 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// LogFilesLabel lists in-container log files (comma-separated paths) to tail next
+// to a container's stdout/stderr, for apps that log to files
+const LogFilesLabel = "colog.log-files"
+
+var (
+	envLogFilesOnce sync.Once
+	envLogFiles     map[string][]string
+)
+
+// LogFiles returns the in-container log files configured for a container: those in
+// its colog.log-files label, then COLOG_LOG_FILES entries for its name
+// ("name=/path,name=/other/path,...")
+func LogFiles(c Container) []string {
+	envLogFilesOnce.Do(func() {
+		envLogFiles = parseLogFilesEnv(os.Getenv("COLOG_LOG_FILES"))
+	})
+
+	var paths []string
+	for _, path := range strings.Split(c.Labels[LogFilesLabel], ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return append(paths, envLogFiles[c.Name]...)
+}
+
+func parseLogFilesEnv(value string) map[string][]string {
+	files := make(map[string][]string)
+	for _, entry := range strings.Split(value, ",") {
+		name, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || name == "" || path == "" {
+			continue
+		}
+		files[name] = append(files[name], path)
+	}
+	return files
+}
+
+// StreamLogFiles follows in-container log files by running tail -F through docker
+// exec, starting with the last tail lines of each. Entries carry the file in Source
+// and the time they were read as Timestamp. logCh is closed when the stream ends.
+//
+// Closing the exec connection doesn't kill the tail process; it exits on its next
+// write to the closed pipe.
+func (ds *DockerService) StreamLogFiles(ctx context.Context, containerID string, paths []string, tail int, logCh chan<- LogEntry) error {
+	host, err := ds.hostFor(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	cmd := append([]string{"tail", "-n", fmt.Sprintf("%d", tail), "-F", "--"}, paths...)
+	exec, err := host.client.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          cmd,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to exec tail in container %s: %w", containerID, err)
+	}
+	resp, err := host.client.ContainerExecAttach(ctx, exec.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to attach to tail in container %s: %w", containerID, err)
+	}
+
+	go func() {
+		defer close(logCh)
+		defer resp.Close()
+
+		stop := context.AfterFunc(ctx, resp.Close)
+		defer stop()
+
+		// With several files tail announces each switch with a "==> path <==" line
+		source := paths[0]
+		readLogLines(resp.Reader, false, func(line, stream string) {
+			if file, ok := tailFileHeader(line); ok {
+				source = file
+				return
+			}
+			if strings.TrimSpace(line) == "" {
+				return
+			}
+
+			entry := LogEntry{
+				ContainerID: containerID,
+				Timestamp:   time.Now(),
+				Message:     line,
+				Stream:      stream,
+				Host:        host.label,
+			}
+			if stream == "stdout" {
+				entry.Source = source
+			}
+			select {
+			case logCh <- entry:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	return nil
+}
+
+// tailFileHeader parses the "==> path <==" line tail prints before each file's lines
+func tailFileHeader(line string) (string, bool) {
+	if !strings.HasPrefix(line, "==> ") || !strings.HasSuffix(line, " <==") {
+		return "", false
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(line, "==> "), " <=="), true
+}
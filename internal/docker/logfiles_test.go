@@ -0,0 +1,32 @@
+package docker
+
+import "testing"
+
+func TestParseLogFilesEnv(t *testing.T) {
+	files := parseLogFilesEnv("web=/var/log/app.log, web=/var/log/err.log,api=/srv/api.log,broken,=/nope")
+	if got := files["web"]; len(got) != 2 || got[0] != "/var/log/app.log" || got[1] != "/var/log/err.log" {
+		t.Errorf("web = %v", got)
+	}
+	if got := files["api"]; len(got) != 1 || got[0] != "/srv/api.log" {
+		t.Errorf("api = %v", got)
+	}
+	if len(files) != 2 {
+		t.Errorf("got %d containers, want 2: %v", len(files), files)
+	}
+}
+
+func TestLogFilesFromLabel(t *testing.T) {
+	c := Container{Name: "db", Labels: map[string]string{LogFilesLabel: "/var/log/a.log, /var/log/b.log,"}}
+	if got := LogFiles(c); len(got) != 2 || got[1] != "/var/log/b.log" {
+		t.Errorf("LogFiles = %v", got)
+	}
+}
+
+func TestTailFileHeader(t *testing.T) {
+	if file, ok := tailFileHeader("==> /var/log/app.log <=="); !ok || file != "/var/log/app.log" {
+		t.Errorf("header: got %q, %v", file, ok)
+	}
+	if _, ok := tailFileHeader("==> not a header"); ok {
+		t.Error("plain line parsed as a header")
+	}
+}
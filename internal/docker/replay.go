@@ -0,0 +1,168 @@
+package docker
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// replayRecord is one line of a JSON Lines export (colog sdk export --format jsonl)
+type replayRecord struct {
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name"`
+	Host          string    `json:"host"`
+	Image         string    `json:"image"`
+	Timestamp     time.Time `json:"timestamp"`
+	Message       string    `json:"message"`
+	Stream        string    `json:"stream"`
+}
+
+// Replay plays back a recorded JSON Lines export as if its containers were logging
+// again, so a captured incident can be reviewed with the same tools as live logs.
+// Like DockerService, it is a LogSource.
+type Replay struct {
+	containers []Container
+	entries    map[string][]LogEntry // by container ID, oldest first
+	first      time.Time             // the recording's earliest line
+	speed      float64               // 1 = as recorded, 10 = ten times faster, 0 = all at once
+
+	startOnce sync.Once
+	started   time.Time // when the first stream began; all streams keep to this clock
+}
+
+// OpenReplay loads a recording from a JSON Lines export file, gzipped when its name
+// ends in .gz, to be played back at speed (see LoadReplay)
+func OpenReplay(path string, speed float64) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress %s: %w", path, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+	return LoadReplay(reader, speed)
+}
+
+// LoadReplay reads a JSON Lines export to be played back at speed: 1 keeps the
+// recorded gaps between lines, 10 plays ten times faster and 0 sends every line at
+// once. Containers appear in the order of their first line.
+func LoadReplay(r io.Reader, speed float64) (*Replay, error) {
+	if speed < 0 {
+		return nil, fmt.Errorf("invalid replay speed %v", speed)
+	}
+	replay := &Replay{entries: make(map[string][]LogEntry), speed: speed}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record replayRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if record.ContainerID == "" {
+			return nil, fmt.Errorf("line %d: no container_id", line)
+		}
+
+		if _, seen := replay.entries[record.ContainerID]; !seen {
+			replay.containers = append(replay.containers, Container{
+				ID:     record.ContainerID,
+				Name:   record.ContainerName,
+				Image:  record.Image,
+				Status: "Replay",
+				Host:   record.Host,
+			})
+		}
+		replay.entries[record.ContainerID] = append(replay.entries[record.ContainerID], LogEntry{
+			ContainerID: record.ContainerID,
+			Timestamp:   record.Timestamp,
+			Message:     record.Message,
+			Stream:      record.Stream,
+			Host:        record.Host,
+		})
+		if replay.first.IsZero() || record.Timestamp.Before(replay.first) {
+			replay.first = record.Timestamp
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(replay.containers) == 0 {
+		return nil, fmt.Errorf("no log lines to replay")
+	}
+
+	for _, entries := range replay.entries {
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	}
+	return replay, nil
+}
+
+// Containers returns the recorded containers
+func (r *Replay) Containers() []Container {
+	return r.containers
+}
+
+// StreamLogs plays a recorded container's lines to logCh, closing it at the end of
+// the recording. Following streams send each line when its time comes, all
+// containers keeping to one clock started by the first stream; other reads send the
+// opts.Tail last lines at once. Lines keep their recorded timestamps.
+func (r *Replay) StreamLogs(ctx context.Context, containerID string, opts LogStreamOptions, logCh chan<- LogEntry) error {
+	recorded, ok := r.entries[containerID]
+	if !ok {
+		return fmt.Errorf("container %s is not in the recording", containerID)
+	}
+	r.startOnce.Do(func() { r.started = time.Now() })
+
+	var entries []LogEntry
+	for _, entry := range recorded {
+		if (!opts.Since.IsZero() && entry.Timestamp.Before(opts.Since)) || (!opts.Until.IsZero() && entry.Timestamp.After(opts.Until)) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if !opts.Follow && opts.Tail >= 0 && len(entries) > opts.Tail {
+		entries = entries[len(entries)-opts.Tail:]
+	}
+
+	go func() {
+		defer close(logCh)
+		for _, entry := range entries {
+			if opts.Follow && r.speed > 0 {
+				due := r.started.Add(time.Duration(float64(entry.Timestamp.Sub(r.first)) / r.speed))
+				if wait := time.Until(due); wait > 0 {
+					timer := time.NewTimer(wait)
+					select {
+					case <-timer.C:
+					case <-ctx.Done():
+						timer.Stop()
+						return
+					}
+				}
+			}
+			select {
+			case logCh <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,105 @@
+package docker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+const recording = `{"container_id":"b2","container_name":"worker","image":"busybox","timestamp":"2025-03-04T12:00:01Z","message":"job started","stream":"stdout"}
+{"container_id":"a1","container_name":"web","image":"nginx","timestamp":"2025-03-04T12:00:02Z","message":"GET / 500","stream":"stderr"}
+
+{"container_id":"b2","container_name":"worker","image":"busybox","timestamp":"2025-03-04T12:00:00Z","message":"booting","stream":"stdout"}
+{"container_id":"b2","container_name":"worker","image":"busybox","timestamp":"2025-03-04T12:00:03Z","message":"job failed","stream":"stderr"}
+`
+
+func drainReplay(t *testing.T, replay *Replay, id string, opts LogStreamOptions) []string {
+	t.Helper()
+	logCh := make(chan LogEntry, 10)
+	if err := replay.StreamLogs(context.Background(), id, opts, logCh); err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for entry := range logCh {
+		messages = append(messages, entry.Message)
+	}
+	return messages
+}
+
+func TestLoadReplay(t *testing.T) {
+	replay, err := LoadReplay(strings.NewReader(recording), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	containers := replay.Containers()
+	if len(containers) != 2 || containers[0].Name != "worker" || containers[1].Image != "nginx" {
+		t.Errorf("containers = %+v, want worker then web", containers)
+	}
+	if got := drainReplay(t, replay, "b2", DefaultLogStreamOptions()); strings.Join(got, ",") != "booting,job started,job failed" {
+		t.Errorf("worker replays %v, want its lines in time order", got)
+	}
+
+	since := time.Date(2025, 3, 4, 12, 0, 1, 0, time.UTC)
+	if got := drainReplay(t, replay, "b2", LogStreamOptions{Tail: 1, Since: since}); strings.Join(got, ",") != "job failed" {
+		t.Errorf("one-shot tail 1 since 12:00:01 = %v", got)
+	}
+
+	if err := replay.StreamLogs(context.Background(), "zz", DefaultLogStreamOptions(), make(chan LogEntry)); err == nil {
+		t.Error("streaming a container missing from the recording succeeded")
+	}
+	if _, err := LoadReplay(strings.NewReader("not json\n"), 1); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Errorf("malformed recording error = %v", err)
+	}
+}
+
+func TestReplayKeepsRecordedPace(t *testing.T) {
+	// 3 recorded seconds at 100x take 30ms
+	replay, err := LoadReplay(strings.NewReader(recording), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if got := drainReplay(t, replay, "b2", DefaultLogStreamOptions()); len(got) != 3 {
+		t.Fatalf("replayed %v", got)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond || elapsed > time.Second {
+		t.Errorf("replay took %v, want about 30ms", elapsed)
+	}
+}
+
+func TestMultiSourceLabelsImportedContainers(t *testing.T) {
+	live, err := LoadReplay(strings.NewReader(recording), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := LoadReplay(strings.NewReader(recording), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sources := NewMultiSource(live)
+	sources.Add("capture", imported)
+
+	containers, err := sources.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(containers) != 4 || containers[0].ID != "b2" || containers[2].ID != "capture:b2" || containers[2].DisplayName() != "capture:worker" {
+		t.Fatalf("containers = %+v, want the live ones then the labeled imported ones", containers)
+	}
+
+	logCh, err := sources.Stream(context.Background(), "capture:a1", DefaultLogStreamOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for entry := range logCh {
+		if entry.ContainerID != "capture:a1" || entry.Message != "GET / 500" {
+			t.Errorf("imported entry %+v", entry)
+		}
+	}
+	if state, err := sources.State(context.Background(), "capture:a1"); err != nil || state.Running {
+		t.Errorf("imported container state = %+v, %v; want not running", state, err)
+	}
+}
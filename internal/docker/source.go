@@ -0,0 +1,166 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LogSource is where containers and their log lines come from. DockerService reads
+// them from the Docker daemons and Replay from a recording; the TUI and SDK only
+// need a LogSource to show and follow logs.
+type LogSource interface {
+	// List returns the containers whose logs can be streamed
+	List(ctx context.Context) ([]Container, error)
+	// Stream reads a container's logs as opts selects; the channel is closed when
+	// the stream ends or ctx is cancelled
+	Stream(ctx context.Context, containerID string, opts LogStreamOptions) (<-chan LogEntry, error)
+}
+
+// streamBuffer is how many entries a Stream channel holds before the stream waits
+// for its reader
+const streamBuffer = 100
+
+// List returns the running containers, like ListRunningContainers
+func (ds *DockerService) List(ctx context.Context) ([]Container, error) {
+	return ds.ListRunningContainers(ctx)
+}
+
+// Stream reads a container's logs into a new channel, see StreamLogs
+func (ds *DockerService) Stream(ctx context.Context, containerID string, opts LogStreamOptions) (<-chan LogEntry, error) {
+	logCh := make(chan LogEntry, streamBuffer)
+	if err := ds.StreamLogs(ctx, containerID, opts, logCh); err != nil {
+		return nil, err
+	}
+	return logCh, nil
+}
+
+// List returns the recorded containers
+func (r *Replay) List(ctx context.Context) ([]Container, error) {
+	return r.Containers(), nil
+}
+
+// Stream plays a recorded container's lines into a new channel, see StreamLogs
+func (r *Replay) Stream(ctx context.Context, containerID string, opts LogStreamOptions) (<-chan LogEntry, error) {
+	logCh := make(chan LogEntry, streamBuffer)
+	if err := r.StreamLogs(ctx, containerID, opts, logCh); err != nil {
+		return nil, err
+	}
+	return logCh, nil
+}
+
+// MultiSource shows the containers of several LogSources together, such as the live
+// containers next to an imported recording. The first source's containers are as it
+// lists them; the others' carry their source's label as Origin and have their IDs
+// prefixed with "label:", so a recording of a running container gets a pane of its
+// own.
+type MultiSource struct {
+	primary LogSource
+	others  []labeledSource
+}
+
+type labeledSource struct {
+	label  string
+	source LogSource
+}
+
+// NewMultiSource returns a MultiSource showing primary's containers as they are
+func NewMultiSource(primary LogSource) *MultiSource {
+	return &MultiSource{primary: primary}
+}
+
+// Add shows source's containers too, labeled with label
+func (m *MultiSource) Add(label string, source LogSource) {
+	m.others = append(m.others, labeledSource{label: label, source: source})
+}
+
+// List returns the containers of every source, the primary's first
+func (m *MultiSource) List(ctx context.Context) ([]Container, error) {
+	containers, err := m.primary.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range m.others {
+		labeled, err := other.source.List(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", other.label, err)
+		}
+		for _, container := range labeled {
+			container.ID = other.label + ":" + container.ID
+			container.Origin = other.label
+			containers = append(containers, container)
+		}
+	}
+	return containers, nil
+}
+
+// Stream reads a container's logs from the source listing it
+func (m *MultiSource) Stream(ctx context.Context, containerID string, opts LogStreamOptions) (<-chan LogEntry, error) {
+	source, id := m.route(containerID)
+	logCh, err := source.Stream(ctx, id, opts)
+	if err != nil || source == m.primary {
+		return logCh, err
+	}
+
+	// Entries carry the ID the pane knows the container by
+	labeled := make(chan LogEntry, streamBuffer)
+	go func() {
+		defer close(labeled)
+		for entry := range logCh {
+			entry.ContainerID = containerID
+			select {
+			case labeled <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return labeled, nil
+}
+
+// GetLogs reads a primary container's logs when the primary source can (see
+// LogReader); other sources' containers can only be streamed
+func (m *MultiSource) GetLogs(ctx context.Context, containerID string, opts LogStreamOptions) ([]LogEntry, error) {
+	if source, id := m.route(containerID); source == m.primary {
+		if reader, ok := source.(LogReader); ok {
+			return reader.GetLogs(ctx, id, opts)
+		}
+	}
+	return nil, fmt.Errorf("container %s can only be streamed", containerID)
+}
+
+// State returns a primary container's state when the primary source knows it, like
+// DockerService.State; other sources' containers aren't running
+func (m *MultiSource) State(ctx context.Context, containerID string) (ContainerState, error) {
+	source, id := m.route(containerID)
+	stater, ok := source.(interface {
+		State(ctx context.Context, containerID string) (ContainerState, error)
+	})
+	if source != m.primary || !ok {
+		return ContainerState{}, nil
+	}
+	return stater.State(ctx, id)
+}
+
+// StreamLogFiles tails a primary container's in-container log files when the primary
+// source can, like DockerService.StreamLogFiles
+func (m *MultiSource) StreamLogFiles(ctx context.Context, containerID string, paths []string, tail int, logCh chan<- LogEntry) error {
+	source, id := m.route(containerID)
+	files, ok := source.(interface {
+		StreamLogFiles(ctx context.Context, containerID string, paths []string, tail int, logCh chan<- LogEntry) error
+	})
+	if source != m.primary || !ok {
+		return fmt.Errorf("container %s has no log files to tail", containerID)
+	}
+	return files.StreamLogFiles(ctx, id, paths, tail, logCh)
+}
+
+// route returns the source listing a container and the container's ID there
+func (m *MultiSource) route(containerID string) (LogSource, string) {
+	for _, other := range m.others {
+		if id, ok := strings.CutPrefix(containerID, other.label+":"); ok {
+			return other.source, id
+		}
+	}
+	return m.primary, containerID
+}
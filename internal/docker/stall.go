@@ -0,0 +1,47 @@
+package docker
+
+import (
+	"context"
+	"time"
+)
+
+// StreamState tells what a follow stream that went silent is doing
+type StreamState string
+
+const (
+	// StreamActive streams have delivered lines recently
+	StreamActive StreamState = "active"
+	// StreamQuiet streams are silent because the container logged nothing new
+	StreamQuiet StreamState = "quiet"
+	// StreamStalled streams are silent although the container logged new lines:
+	// the stream is wedged (e.g. by a daemon issue) and needs restarting
+	StreamStalled StreamState = "stalled"
+	// StreamEnded streams reached EOF, usually because the container stopped
+	StreamEnded StreamState = "ended"
+)
+
+// stallGrace is how old a line must be before a stream that hasn't delivered it
+// counts as stalled, so lines still in flight don't trip the check
+const stallGrace = 5 * time.Second
+
+// LogReader reads a container's logs once; DockerService implements it
+type LogReader interface {
+	GetLogs(ctx context.Context, containerID string, opts LogStreamOptions) ([]LogEntry, error)
+}
+
+// ProbeStall tells a quiet container from a wedged stream once a stream has been
+// silent for a while: a one-shot read finding lines logged after lastSeen (the last
+// line the stream delivered) means the stream should have delivered them and is
+// stalled; otherwise the container is quiet.
+func ProbeStall(ctx context.Context, reader LogReader, containerID string, lastSeen time.Time) (StreamState, error) {
+	logs, err := reader.GetLogs(ctx, containerID, LogStreamOptions{Tail: 10, Since: lastSeen, Timestamps: true})
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range logs {
+		if entry.Timestamp.After(lastSeen) && time.Since(entry.Timestamp) > stallGrace {
+			return StreamStalled, nil
+		}
+	}
+	return StreamQuiet, nil
+}
@@ -0,0 +1,71 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ContainerStats is a single resource usage sample for a container
+type ContainerStats struct {
+	ContainerID   string  `json:"container_id"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryUsage   uint64  `json:"memory_usage"`
+	MemoryLimit   uint64  `json:"memory_limit"`
+	MemoryPercent float64 `json:"memory_percent"`
+	NetworkRx     uint64  `json:"network_rx_bytes"` // summed over all interfaces
+	NetworkTx     uint64  `json:"network_tx_bytes"`
+}
+
+// GetContainerStats takes a single (non-streaming) resource usage sample
+func (ds *DockerService) GetContainerStats(ctx context.Context, containerID string) (*ContainerStats, error) {
+	// A one-shot read still includes the previous CPU sample, which CPU percent needs
+	cli, err := ds.clientFor(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := cli.ContainerStats(ctx, containerID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats for container %s: %w", containerID, err)
+	}
+	defer reader.Body.Close()
+
+	var raw container.StatsResponse
+	if err := json.NewDecoder(reader.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode stats for container %s: %w", containerID, err)
+	}
+
+	stats := statsFromResponse(raw)
+	stats.ContainerID = containerID
+	return &stats, nil
+}
+
+// statsFromResponse computes percentages and network totals from a raw stats sample
+func statsFromResponse(raw container.StatsResponse) ContainerStats {
+	stats := ContainerStats{
+		MemoryUsage: raw.MemoryStats.Usage,
+		MemoryLimit: raw.MemoryStats.Limit,
+	}
+
+	cpuDelta := float64(raw.CPUStats.CPUUsage.TotalUsage) - float64(raw.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(raw.CPUStats.SystemUsage) - float64(raw.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(raw.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(raw.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		stats.CPUPercent = cpuDelta / systemDelta * onlineCPUs * 100
+	}
+	if stats.MemoryLimit > 0 {
+		stats.MemoryPercent = float64(stats.MemoryUsage) / float64(stats.MemoryLimit) * 100
+	}
+
+	for _, network := range raw.Networks {
+		stats.NetworkRx += network.RxBytes
+		stats.NetworkTx += network.TxBytes
+	}
+	return stats
+}
@@ -0,0 +1,45 @@
+package docker
+
+import (
+	"math"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+func TestStatsFromResponse(t *testing.T) {
+	var raw container.StatsResponse
+	raw.CPUStats.CPUUsage.TotalUsage = 300
+	raw.CPUStats.SystemUsage = 2000
+	raw.CPUStats.OnlineCPUs = 2
+	raw.PreCPUStats.CPUUsage.TotalUsage = 100
+	raw.PreCPUStats.SystemUsage = 1000
+	raw.MemoryStats.Usage = 256
+	raw.MemoryStats.Limit = 1024
+	raw.Networks = map[string]container.NetworkStats{
+		"eth0": {RxBytes: 100, TxBytes: 10},
+		"eth1": {RxBytes: 50, TxBytes: 5},
+	}
+
+	stats := statsFromResponse(raw)
+	if math.Abs(stats.CPUPercent-40) > 1e-9 {
+		t.Errorf("CPUPercent = %v, want 40", stats.CPUPercent)
+	}
+	if stats.MemoryPercent != 25 {
+		t.Errorf("MemoryPercent = %v, want 25", stats.MemoryPercent)
+	}
+	if stats.NetworkRx != 150 || stats.NetworkTx != 15 {
+		t.Errorf("network = %d/%d, want 150/15", stats.NetworkRx, stats.NetworkTx)
+	}
+}
+
+func TestStatsFromResponseFirstSample(t *testing.T) {
+	// Without a previous sample there is no delta, so CPU percent stays 0
+	var raw container.StatsResponse
+	raw.CPUStats.CPUUsage.TotalUsage = 300
+	raw.CPUStats.SystemUsage = 2000
+
+	if stats := statsFromResponse(raw); stats.CPUPercent != 0 || stats.MemoryPercent != 0 {
+		t.Errorf("got %+v, want zero percentages", stats)
+	}
+}
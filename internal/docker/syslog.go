@@ -0,0 +1,123 @@
+package docker
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyslogHeader is the parsed header of a syslog-formatted log line
+type SyslogHeader struct {
+	Priority  int
+	Facility  int
+	Severity  int       // 0 (emergency) to 7 (debug)
+	Timestamp time.Time // zero when the line has none
+	Hostname  string
+	Tag       string // program or app name
+	PID       string
+}
+
+// Level maps the syslog severity to a LogLevel: emergency through error are errors,
+// warning is warn, notice and informational are info, debug is debug
+func (h SyslogHeader) Level() LogLevel {
+	switch {
+	case h.Severity <= 3:
+		return LevelError
+	case h.Severity == 4:
+		return LevelWarn
+	case h.Severity == 7:
+		return LevelDebug
+	default:
+		return LevelInfo
+	}
+}
+
+var (
+	// rfc5424Pattern matches <PRI>1 TIMESTAMP HOST APP PROCID MSGID [SD] MSG
+	rfc5424Pattern = regexp.MustCompile(`^<(\d{1,3})>1 (\S+) (\S+) (\S+) (\S+) (\S+) (-|(?:\[[^\]]*\])+)(?: (.*))?$`)
+	// rfc3164Pattern matches <PRI>Mmm dd hh:mm:ss [HOST] TAG[PID]: MSG
+	rfc3164Pattern = regexp.MustCompile(`^<(\d{1,3})>([A-Z][a-z]{2} [ \d]?\d \d\d:\d\d:\d\d) (?:(\S+) )?([^\s:\[]+)(?:\[(\d+)\])?: ?(.*)$`)
+	// syslogPriorityPattern matches a bare <PRI> prefix
+	syslogPriorityPattern = regexp.MustCompile(`^<(\d{1,3})>(.*)$`)
+)
+
+var (
+	syslogOnce sync.Once
+	syslogOn   bool
+)
+
+// syslogEnabled reports whether syslog detection is on: it is unless COLOG_SYSLOG=off.
+// The variable is read on first use, after startup profiles have set the environment.
+func syslogEnabled() bool {
+	syslogOnce.Do(func() {
+		syslogOn = !strings.EqualFold(os.Getenv("COLOG_SYSLOG"), "off")
+	})
+	return syslogOn
+}
+
+// ParseSyslog parses an RFC 5424 or RFC 3164 syslog line (or one with just a <PRI>
+// prefix) and returns its header and message. ok is false for other lines.
+func ParseSyslog(line string) (header SyslogHeader, message string, ok bool) {
+	if !strings.HasPrefix(line, "<") {
+		return header, "", false
+	}
+
+	if match := rfc5424Pattern.FindStringSubmatch(line); match != nil {
+		if !header.setPriority(match[1]) {
+			return header, "", false
+		}
+		if ts, err := time.Parse(time.RFC3339Nano, match[2]); err == nil {
+			header.Timestamp = ts
+		}
+		header.Hostname = nilValue(match[3])
+		header.Tag = nilValue(match[4])
+		header.PID = nilValue(match[5])
+		return header, strings.TrimPrefix(match[8], "\ufeff"), true
+	}
+
+	if match := rfc3164Pattern.FindStringSubmatch(line); match != nil {
+		if !header.setPriority(match[1]) {
+			return header, "", false
+		}
+		// RFC 3164 timestamps have no year or zone: assume the current year, local time
+		if ts, err := time.ParseInLocation(time.Stamp, match[2], time.Local); err == nil {
+			header.Timestamp = ts.AddDate(time.Now().Year(), 0, 0)
+		}
+		header.Hostname = match[3]
+		header.Tag = match[4]
+		header.PID = match[5]
+		return header, match[6], true
+	}
+
+	if match := syslogPriorityPattern.FindStringSubmatch(line); match != nil {
+		if !header.setPriority(match[1]) {
+			return header, "", false
+		}
+		return header, strings.TrimSpace(match[2]), true
+	}
+
+	return header, "", false
+}
+
+// setPriority splits a PRI value into facility and severity; false if out of range
+func (h *SyslogHeader) setPriority(value string) bool {
+	priority, err := strconv.Atoi(value)
+	if err != nil || priority > 191 {
+		return false
+	}
+	h.Priority = priority
+	h.Facility = priority / 8
+	h.Severity = priority % 8
+	return true
+}
+
+// nilValue maps the RFC 5424 nil value "-" to an empty string
+func nilValue(field string) string {
+	if field == "-" {
+		return ""
+	}
+	return field
+}
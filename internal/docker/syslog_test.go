@@ -0,0 +1,87 @@
+package docker
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSyslog(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		severity int
+		facility int
+		hostname string
+		tag      string
+		pid      string
+		message  string
+	}{
+		{"rfc3164 with host", "<13>Jan  2 15:04:05 web01 nginx[42]: connection reset", 5, 1, "web01", "nginx", "42", "connection reset"},
+		{"rfc3164 without host", "<11>Jan 12 15:04:05 cron: job failed", 3, 1, "", "cron", "", "job failed"},
+		{"rfc5424", "<165>1 2025-01-02T03:04:05.000Z host app 1234 ID47 - database is slow", 5, 20, "host", "app", "1234", "database is slow"},
+		{"rfc5424 with structured data", `<12>1 2025-01-02T03:04:05Z host app - - [meta seq="1"] disk almost full`, 4, 1, "host", "app", "", "disk almost full"},
+		{"bare priority", "<15> verbose output", 7, 1, "", "", "", "verbose output"},
+	}
+
+	for _, tt := range tests {
+		header, message, ok := ParseSyslog(tt.line)
+		if !ok {
+			t.Errorf("%s: not parsed", tt.name)
+			continue
+		}
+		if header.Severity != tt.severity || header.Facility != tt.facility {
+			t.Errorf("%s: severity/facility = %d/%d, want %d/%d", tt.name, header.Severity, header.Facility, tt.severity, tt.facility)
+		}
+		if header.Hostname != tt.hostname || header.Tag != tt.tag || header.PID != tt.pid {
+			t.Errorf("%s: host/tag/pid = %q/%q/%q, want %q/%q/%q", tt.name, header.Hostname, header.Tag, header.PID, tt.hostname, tt.tag, tt.pid)
+		}
+		if message != tt.message {
+			t.Errorf("%s: message = %q, want %q", tt.name, message, tt.message)
+		}
+	}
+
+	for _, line := range []string{"plain line", "<html> tag", "<999>Jan  2 15:04:05 app: x"} {
+		if _, _, ok := ParseSyslog(line); ok {
+			t.Errorf("%q parsed as syslog", line)
+		}
+	}
+}
+
+func TestParseSyslogTimestamp(t *testing.T) {
+	header, _, _ := ParseSyslog("<165>1 2025-01-02T03:04:05Z host app - - - hi")
+	if want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC); !header.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", header.Timestamp, want)
+	}
+
+	header, _, _ = ParseSyslog("<13>Mar  4 05:06:07 app: hi")
+	if header.Timestamp.Year() != time.Now().Year() || header.Timestamp.Month() != time.March || header.Timestamp.Day() != 4 {
+		t.Errorf("Timestamp = %v, want March 4 of this year", header.Timestamp)
+	}
+}
+
+func TestSyslogEntryLevel(t *testing.T) {
+	entry := parseLogEntry("abc", "2025-01-02T03:04:05Z <11>Jan  2 15:04:05 app: all good")
+	if entry.Syslog == nil {
+		t.Fatal("syslog header not parsed")
+	}
+	if entry.Message != "all good" {
+		t.Errorf("Message = %q", entry.Message)
+	}
+	// Severity 3 (error) wins over the keyword-free message
+	if got := NewLevelMatcher().MatchEntry(entry); got != LevelError {
+		t.Errorf("MatchEntry = %v, want ERROR", got)
+	}
+}
+
+func TestSyslogDetectionOff(t *testing.T) {
+	// Read on first use, so a profile's environment set after startup applies
+	t.Setenv("COLOG_SYSLOG", "off")
+	syslogOnce = sync.Once{}
+	defer func() { syslogOnce = sync.Once{} }()
+
+	entry := parseLogEntry("abc", "2025-01-02T03:04:05Z <11>Jan  2 15:04:05 app: all good")
+	if entry.Syslog != nil || entry.Message != "<11>Jan  2 15:04:05 app: all good" {
+		t.Errorf("COLOG_SYSLOG=off still parsed syslog: %+v", entry)
+	}
+}
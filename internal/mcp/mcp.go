@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/berkantay/colog/v2/internal/docker"
+	"github.com/berkantay/colog/v2/internal/sdk"
 )
 
 // MCP Protocol Types for stdio transport
@@ -44,6 +45,16 @@ type ToolDefinition struct {
 type MCPStdioServer struct {
 	dockerService *docker.DockerService
 	ctx           context.Context
+	readOnly      bool // hide and reject tools that mutate containers
+	allowControl  bool // expose restart_container/kill_container (COLOG_MCP_ALLOW_CONTROL)
+}
+
+// mutatingTools lists tools that change container state and are disabled in read-only mode
+var mutatingTools = map[string]bool{
+	"restart_container": true,
+	"kill_container":    true,
+	"stop_container":    true,
+	"exec_container":    true,
 }
 
 func NewMCPStdioServer() (*MCPStdioServer, error) {
@@ -52,9 +63,17 @@ func NewMCPStdioServer() (*MCPStdioServer, error) {
 	return &MCPStdioServer{
 		dockerService: nil, // Initialize lazily when needed
 		ctx:           ctx,
+		readOnly:      os.Getenv("MCP_READ_ONLY") == "true",
+		allowControl:  os.Getenv("COLOG_MCP_ALLOW_CONTROL") == "true",
 	}, nil
 }
 
+// controlEnabled reports whether tools that mutate containers may run: they must be
+// allowed explicitly, and read-only mode always wins
+func (s *MCPStdioServer) controlEnabled() bool {
+	return s.allowControl && !s.readOnly
+}
+
 func (s *MCPStdioServer) getDockerService() (*docker.DockerService, error) {
 	if s.dockerService == nil {
 		dockerService, err := docker.NewDockerService()
@@ -166,7 +185,11 @@ func (s *MCPStdioServer) handleToolsList(req *MCPRequest) MCPResponse {
 					},
 					"since": map[string]interface{}{
 						"type":        "string",
-						"description": "Show logs since timestamp (e.g. 2013-01-02T13:23:37Z)",
+						"description": "Show logs since an RFC3339 timestamp (e.g. 2013-01-02T13:23:37Z) or a relative duration (e.g. 10m)",
+					},
+					"until": map[string]interface{}{
+						"type":        "string",
+						"description": "Show logs before an RFC3339 timestamp or a relative duration (e.g. 5m)",
 					},
 				},
 				"required": []string{"container_id"},
@@ -193,6 +216,20 @@ func (s *MCPStdioServer) handleToolsList(req *MCPRequest) MCPResponse {
 				},
 			},
 		},
+		{
+			Name:        "summarize_errors",
+			Description: "Rank running containers by the errors and warnings in their recent logs, with sample error lines; a token-efficient first look at what's broken",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"tail": map[string]interface{}{
+						"type":        "integer",
+						"description": "Number of recent log lines checked per container (default: 200)",
+						"default":     200,
+					},
+				},
+			},
+		},
 		{
 			Name:        "filter_containers",
 			Description: "Filter containers by various criteria",
@@ -211,11 +248,54 @@ func (s *MCPStdioServer) handleToolsList(req *MCPRequest) MCPResponse {
 						"type":        "string",
 						"description": "Filter by container name (partial match)",
 					},
+					"host": map[string]interface{}{
+						"type":        "string",
+						"description": "Filter by Docker host label (when watching several hosts)",
+					},
+				},
+			},
+		},
+		{
+			Name:        "restart_container",
+			Description: "Restart a Docker container (requires COLOG_MCP_ALLOW_CONTROL=true)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"container_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Container ID or name",
+					},
+				},
+				"required": []string{"container_id"},
+			},
+		},
+		{
+			Name:        "kill_container",
+			Description: "Kill a Docker container with SIGKILL (requires COLOG_MCP_ALLOW_CONTROL=true)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"container_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Container ID or name",
+					},
 				},
+				"required": []string{"container_id"},
 			},
 		},
 	}
 
+	// Servers without container control don't advertise destructive tools at all
+	if !s.controlEnabled() {
+		var readOnlyTools []ToolDefinition
+		for _, tool := range tools {
+			if !mutatingTools[tool.Name] {
+				readOnlyTools = append(readOnlyTools, tool)
+			}
+		}
+		tools = readOnlyTools
+	}
+
 	return MCPResponse{
 		ID: req.ID,
 		Result: map[string]interface{}{
@@ -235,6 +315,13 @@ func (s *MCPStdioServer) handleToolCall(req *MCPRequest) MCPResponse {
 		return s.createErrorResponse(req.ID, -32602, "Invalid params: missing tool name")
 	}
 
+	if s.readOnly && mutatingTools[toolName] {
+		return s.createErrorResponse(req.ID, -32002, fmt.Sprintf("Tool %s is disabled: server is running in read-only mode", toolName))
+	}
+	if !s.controlEnabled() && mutatingTools[toolName] {
+		return s.createErrorResponse(req.ID, -32002, fmt.Sprintf("Tool %s is disabled: set COLOG_MCP_ALLOW_CONTROL=true to allow container control", toolName))
+	}
+
 	switch toolName {
 	case "list_containers":
 		return s.handleListContainers(req.ID, params)
@@ -242,22 +329,33 @@ func (s *MCPStdioServer) handleToolCall(req *MCPRequest) MCPResponse {
 		return s.handleGetContainerLogs(req.ID, params)
 	case "export_logs_llm":
 		return s.handleExportLogsLLM(req.ID, params)
+	case "summarize_errors":
+		return s.handleSummarizeErrors(req.ID, params)
 	case "filter_containers":
 		return s.handleFilterContainers(req.ID, params)
+	case "restart_container":
+		return s.handleControlContainer(req.ID, params, "restart")
+	case "kill_container":
+		return s.handleControlContainer(req.ID, params, "kill")
 	default:
 		return s.createErrorResponse(req.ID, -32601, "Unknown tool: "+toolName)
 	}
 }
 
 func (s *MCPStdioServer) handleListContainers(id interface{}, args map[string]interface{}) MCPResponse {
-	_, _ = args["all"].(bool) // Note: currently only lists running containers
+	all, _ := args["all"].(bool)
 	
 	dockerService, err := s.getDockerService()
 	if err != nil {
 		return s.createErrorResponse(id, -32603, "Docker connection failed: "+err.Error())
 	}
 	
-	containers, err := dockerService.ListRunningContainers(s.ctx)
+	var containers []docker.Container
+	if all {
+		containers, err = dockerService.ListAllContainers(s.ctx)
+	} else {
+		containers, err = dockerService.ListRunningContainers(s.ctx)
+	}
 	if err != nil {
 		return s.createErrorResponse(id, -32603, "Failed to list containers: "+err.Error())
 	}
@@ -265,14 +363,16 @@ func (s *MCPStdioServer) handleListContainers(id interface{}, args map[string]in
 	// Format containers for display
 	var containerList []string
 	for _, container := range containers {
-		status := container.Status
-		if len(status) > 20 {
-			status = status[:20] + "..."
-		}
-		containerList = append(containerList, fmt.Sprintf("• %s (%s) - %s", container.Name, container.ID[:12], status))
+		status := docker.Truncate(container.Status, 23)
+		containerList = append(containerList, fmt.Sprintf("• %s (%s) - %s", container.DisplayName(), docker.ShortID(container.ID), status))
 	}
 	
 	response := fmt.Sprintf("Found %d containers:\n\n%s", len(containers), strings.Join(containerList, "\n"))
+
+	resource, err := containersResource(containers)
+	if err != nil {
+		return s.createErrorResponse(id, -32603, "Failed to encode containers: "+err.Error())
+	}
 	
 	return MCPResponse{
 		ID: id,
@@ -282,11 +382,52 @@ func (s *MCPStdioServer) handleListContainers(id interface{}, args map[string]in
 					"type": "text",
 					"text": response,
 				},
+				resource,
 			},
 		},
 	}
 }
 
+// containerJSON is a container as list_containers returns it in its JSON resource
+type containerJSON struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Image   string            `json:"image"`
+	ImageID string            `json:"image_id"`
+	Status  string            `json:"status"`
+	Host    string            `json:"host,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// containersResource returns a resource content block carrying the containers as a
+// JSON array, so clients can use typed data instead of parsing the text summary
+func containersResource(containers []docker.Container) (map[string]interface{}, error) {
+	list := make([]containerJSON, 0, len(containers))
+	for _, container := range containers {
+		list = append(list, containerJSON{
+			ID:      container.ID,
+			Name:    container.Name,
+			Image:   container.Image,
+			ImageID: container.ImageID,
+			Status:  container.Status,
+			Host:    container.Host,
+			Labels:  container.Labels,
+		})
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"type": "resource",
+		"resource": map[string]interface{}{
+			"uri":      "colog://containers",
+			"mimeType": "application/json",
+			"text":     string(data),
+		},
+	}, nil
+}
+
 func (s *MCPStdioServer) handleGetContainerLogs(id interface{}, args map[string]interface{}) MCPResponse {
 	containerID, ok := args["container_id"].(string)
 	if !ok {
@@ -298,13 +439,29 @@ func (s *MCPStdioServer) handleGetContainerLogs(id interface{}, args map[string]
 		tail = int(t)
 	}
 
+	options := sdk.LogOptions{Tail: tail, Timestamps: true}
+	if since, ok := args["since"].(string); ok && since != "" {
+		t, err := sdk.ParseTimeArg(since)
+		if err != nil {
+			return s.createErrorResponse(id, -32602, "Invalid since: "+err.Error())
+		}
+		options.Since = t
+	}
+	if until, ok := args["until"].(string); ok && until != "" {
+		t, err := sdk.ParseTimeArg(until)
+		if err != nil {
+			return s.createErrorResponse(id, -32602, "Invalid until: "+err.Error())
+		}
+		options.Until = t
+	}
+
 	dockerService, err := s.getDockerService()
 	if err != nil {
 		return s.createErrorResponse(id, -32603, "Docker connection failed: "+err.Error())
 	}
 
-	// Get recent logs directly
-	logs, err := dockerService.GetRecentLogs(s.ctx, containerID, tail)
+	// The SDK hands the time window to Docker along with the tail
+	logs, err := sdk.NewCologWithDockerService(s.ctx, dockerService).GetContainerLogs(containerID, options)
 	if err != nil {
 		return s.createErrorResponse(id, -32603, "Failed to get logs: "+err.Error())
 	}
@@ -337,7 +494,12 @@ func (s *MCPStdioServer) handleExportLogsLLM(id interface{}, args map[string]int
 		tail = int(t)
 	}
 
-	containers, err := s.dockerService.ListRunningContainers(s.ctx)
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return s.createErrorResponse(id, -32603, "Docker connection failed: "+err.Error())
+	}
+
+	containers, err := dockerService.ListRunningContainers(s.ctx)
 	if err != nil {
 		return s.createErrorResponse(id, -32603, "Failed to list containers: "+err.Error())
 	}
@@ -347,34 +509,14 @@ func (s *MCPStdioServer) handleExportLogsLLM(id interface{}, args map[string]int
 	output += fmt.Sprintf("Generated at: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
 
 	for _, container := range containers {
-		logCh := make(chan docker.LogEntry, 100)
-		
-		go func() {
-			defer close(logCh)
-			s.dockerService.StreamLogs(s.ctx, container.ID, logCh)
-		}()
-
-		var logs []docker.LogEntry
-		timeout := time.After(3 * time.Second)
-		collected := 0
-
-		for collected < tail {
-			select {
-			case entry, ok := <-logCh:
-				if !ok {
-					goto nextContainer
-				}
-				logs = append(logs, entry)
-				collected++
-			case <-timeout:
-				goto nextContainer
-			}
-		}
-
-	nextContainer:
+		logs, _ := dockerService.GetLogs(s.ctx, container.ID, docker.LogStreamOptions{Tail: tail, Timestamps: true})
 		if len(logs) > 0 {
-			output += fmt.Sprintf("## Container: %s\n", container.Name)
+			output += fmt.Sprintf("## Container: %s\n", container.DisplayName())
 			output += fmt.Sprintf("- Image: %s\n", container.Image)
+			output += fmt.Sprintf("- Image ID: %s\n", container.ImageID)
+			if digest, _ := dockerService.ImageDigest(s.ctx, container.ID, container.Image, container.ImageID); digest != "" {
+				output += fmt.Sprintf("- Image Digest: %s\n", digest)
+			}
 			output += fmt.Sprintf("- Status: %s\n\n", container.Status)
 			
 			output += "```\n"
@@ -399,8 +541,68 @@ func (s *MCPStdioServer) handleExportLogsLLM(id interface{}, args map[string]int
 	}
 }
 
+func (s *MCPStdioServer) handleSummarizeErrors(id interface{}, args map[string]interface{}) MCPResponse {
+	tail := 200
+	if t, ok := args["tail"].(float64); ok {
+		tail = int(t)
+	}
+
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return s.createErrorResponse(id, -32603, "Docker connection failed: "+err.Error())
+	}
+
+	report, err := sdk.NewCologWithDockerService(s.ctx, dockerService).SummarizeLogs(nil, sdk.LogOptions{Tail: tail, Timestamps: true})
+	if err != nil {
+		return s.createErrorResponse(id, -32603, "Failed to summarize logs: "+err.Error())
+	}
+	report.RankByErrors()
+
+	var text strings.Builder
+	sdk.WriteErrorSummary(&text, report)
+	resource, err := errorSummaryResource(report)
+	if err != nil {
+		return s.createErrorResponse(id, -32603, "Failed to encode summary: "+err.Error())
+	}
+
+	return MCPResponse{
+		ID: id,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": text.String(),
+				},
+				resource,
+			},
+		},
+	}
+}
+
+// errorSummaryResource returns a resource content block carrying the ranked summary
+// as JSON, like containersResource
+func errorSummaryResource(report *sdk.LogSummary) (map[string]interface{}, error) {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"type": "resource",
+		"resource": map[string]interface{}{
+			"uri":      "colog://errors",
+			"mimeType": "application/json",
+			"text":     string(data),
+		},
+	}, nil
+}
+
 func (s *MCPStdioServer) handleFilterContainers(id interface{}, args map[string]interface{}) MCPResponse {
-	containers, err := s.dockerService.ListRunningContainers(s.ctx)
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return s.createErrorResponse(id, -32603, "Docker connection failed: "+err.Error())
+	}
+
+	containers, err := dockerService.ListRunningContainers(s.ctx)
 	if err != nil {
 		return s.createErrorResponse(id, -32603, "Failed to list containers: "+err.Error())
 	}
@@ -410,6 +612,7 @@ func (s *MCPStdioServer) handleFilterContainers(id interface{}, args map[string]
 	status, hasStatus := args["status"].(string)
 	image, hasImage := args["image"].(string)
 	name, hasName := args["name"].(string)
+	host, hasHost := args["host"].(string)
 
 	for _, container := range containers {
 		match := true
@@ -423,6 +626,9 @@ func (s *MCPStdioServer) handleFilterContainers(id interface{}, args map[string]
 		if hasName && container.Name != name {
 			match = false
 		}
+		if hasHost && container.Host != host {
+			match = false
+		}
 
 		if match {
 			filtered = append(filtered, container)
@@ -432,17 +638,15 @@ func (s *MCPStdioServer) handleFilterContainers(id interface{}, args map[string]
 	// Format filtered containers for display
 	var containerList []string
 	for _, container := range filtered {
-		status := container.Status
-		if len(status) > 20 {
-			status = status[:20] + "..."
-		}
-		containerList = append(containerList, fmt.Sprintf("• %s (%s) - %s", container.Name, container.ID[:12], status))
+		status := docker.Truncate(container.Status, 23)
+		containerList = append(containerList, fmt.Sprintf("• %s (%s) - %s", container.DisplayName(), docker.ShortID(container.ID), status))
 	}
 	
 	filtersUsed := []string{}
 	if hasStatus { filtersUsed = append(filtersUsed, fmt.Sprintf("status=%s", status)) }
 	if hasImage { filtersUsed = append(filtersUsed, fmt.Sprintf("image=%s", image)) }
 	if hasName { filtersUsed = append(filtersUsed, fmt.Sprintf("name=%s", name)) }
+	if hasHost { filtersUsed = append(filtersUsed, fmt.Sprintf("host=%s", host)) }
 	
 	response := fmt.Sprintf("Found %d containers matching filters [%s]:\n\n%s", 
 		len(filtered), strings.Join(filtersUsed, ", "), strings.Join(containerList, "\n"))
@@ -460,6 +664,43 @@ func (s *MCPStdioServer) handleFilterContainers(id interface{}, args map[string]
 	}
 }
 
+// handleControlContainer restarts or kills the container named by container_id
+func (s *MCPStdioServer) handleControlContainer(id interface{}, args map[string]interface{}, action string) MCPResponse {
+	containerID, ok := args["container_id"].(string)
+	if !ok || containerID == "" {
+		return s.createErrorResponse(id, -32602, "Missing required parameter: container_id")
+	}
+
+	dockerService, err := s.getDockerService()
+	if err != nil {
+		return s.createErrorResponse(id, -32603, "Docker connection failed: "+err.Error())
+	}
+
+	var done string
+	if action == "kill" {
+		err = dockerService.KillContainer(s.ctx, containerID)
+		done = "Killed"
+	} else {
+		err = dockerService.RestartContainer(s.ctx, containerID)
+		done = "Restarted"
+	}
+	if err != nil {
+		return s.createErrorResponse(id, -32603, fmt.Sprintf("Failed to %s container %s: %v", action, containerID, err))
+	}
+
+	return MCPResponse{
+		ID: id,
+		Result: map[string]interface{}{
+			"content": []map[string]interface{}{
+				{
+					"type": "text",
+					"text": fmt.Sprintf("%s container %s", done, containerID),
+				},
+			},
+		},
+	}
+}
+
 func (s *MCPStdioServer) createErrorResponse(id interface{}, code int, message string) MCPResponse {
 	return MCPResponse{
 		ID: id,
@@ -514,8 +755,8 @@ func RunMCPStdio() error {
 func truncateContainerID(containerID string) string {
 	// If it's a hex ID (longer than 12 chars), truncate it
 	// If it's a name (shorter), keep it as is
-	if len(containerID) > 12 && isHexString(containerID) {
-		return containerID[:12]
+	if isHexString(containerID) {
+		return docker.ShortID(containerID)
 	}
 	return containerID
 }
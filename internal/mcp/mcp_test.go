@@ -0,0 +1,43 @@
+package mcp
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToolsConnectToDockerOnFirstCall(t *testing.T) {
+	// An unreachable daemon: each tool must report it rather than use a nil service
+	t.Setenv("COLOG_DOCKER_HOSTS", "unix://"+filepath.Join(t.TempDir(), "docker.sock"))
+
+	for _, tool := range []string{"export_logs_llm", "filter_containers", "summarize_errors"} {
+		server, err := NewMCPStdioServer()
+		if err != nil {
+			t.Fatal(err)
+		}
+		response := server.handleRequest(&MCPRequest{
+			ID:     1,
+			Method: "tools/call",
+			Params: map[string]interface{}{"name": tool, "arguments": map[string]interface{}{}},
+		})
+		if response.Error == nil || !strings.Contains(response.Error.Message, "Docker connection failed") {
+			t.Errorf("%s as the first call = %+v, want a Docker connection error", tool, response)
+		}
+	}
+}
+
+func TestGetContainerLogsRejectsInvalidTimeWindow(t *testing.T) {
+	server, err := NewMCPStdioServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, param := range []string{"since", "until"} {
+		response := server.handleGetContainerLogs(1, map[string]interface{}{
+			"container_id": "abc123",
+			param:          "yesterday",
+		})
+		if response.Error == nil || response.Error.Code != -32602 || !strings.Contains(response.Error.Message, "Invalid "+param) {
+			t.Errorf("%s=yesterday = %+v, want an invalid params error", param, response)
+		}
+	}
+}
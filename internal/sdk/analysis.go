@@ -0,0 +1,327 @@
+package sdk
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+// ExtractStats aggregates numeric values captured from log messages
+type ExtractStats struct {
+	Pattern string  `json:"pattern"`
+	Lines   int     `json:"lines"`   // log lines scanned
+	Matches int     `json:"matches"` // lines where the pattern matched
+	Count   int     `json:"count"`   // matches whose capture parsed as a number
+	Sum     float64 `json:"sum"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Avg     float64 `json:"avg"`
+	Skipped int     `json:"skipped"` // matches whose capture was not numeric
+}
+
+// ExtractNumeric applies pattern to each log message and aggregates the first capture
+// group (or the whole match when the pattern has no groups) as a number
+func ExtractNumeric(logs []docker.LogEntry, pattern *regexp.Regexp) ExtractStats {
+	stats := ExtractStats{
+		Pattern: pattern.String(),
+		Lines:   len(logs),
+		Min:     math.Inf(1),
+		Max:     math.Inf(-1),
+	}
+
+	for _, entry := range logs {
+		match := pattern.FindStringSubmatch(entry.Message)
+		if match == nil {
+			continue
+		}
+		stats.Matches++
+
+		captured := match[0]
+		if len(match) > 1 {
+			captured = match[1]
+		}
+		value, err := strconv.ParseFloat(captured, 64)
+		if err != nil {
+			stats.Skipped++
+			continue
+		}
+
+		stats.Count++
+		stats.Sum += value
+		stats.Min = math.Min(stats.Min, value)
+		stats.Max = math.Max(stats.Max, value)
+	}
+
+	if stats.Count == 0 {
+		stats.Min, stats.Max = 0, 0
+		return stats
+	}
+	stats.Avg = stats.Sum / float64(stats.Count)
+	return stats
+}
+
+// Aggregate returns the named aggregate (count, sum, min, max, avg)
+func (s ExtractStats) Aggregate(name string) (float64, error) {
+	switch name {
+	case "count":
+		return float64(s.Count), nil
+	case "sum":
+		return s.Sum, nil
+	case "min":
+		return s.Min, nil
+	case "max":
+		return s.Max, nil
+	case "avg", "mean":
+		return s.Avg, nil
+	}
+	return 0, fmt.Errorf("unsupported aggregate: %s (supported: count, sum, min, max, avg)", name)
+}
+
+// ExtractFromLogs retrieves a container's logs and aggregates the values captured by pattern
+func (c *Colog) ExtractFromLogs(containerID string, pattern string, options LogOptions) (*ExtractStats, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+
+	logs, err := c.GetContainerLogs(containerID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := ExtractNumeric(logs, re)
+	return &stats, nil
+}
+
+// HistogramBucket counts log lines (and error lines) starting at Start
+type HistogramBucket struct {
+	Start  time.Time `json:"start"`
+	Count  int       `json:"count"`
+	Errors int       `json:"errors"`
+}
+
+// maxHistogramBuckets caps the buckets of a histogram, so a small bucket over a long
+// span fails instead of allocating millions of them
+const maxHistogramBuckets = 10000
+
+// BuildHistogram buckets logs by timestamp into consecutive buckets of the given size,
+// including empty buckets between the first and last entry so gaps stay visible.
+// Entries without a timestamp are left out. It fails when the span would take more
+// than maxHistogramBuckets buckets.
+func BuildHistogram(logs []docker.LogEntry, bucket time.Duration, matcher *docker.LevelMatcher) ([]HistogramBucket, error) {
+	if bucket <= 0 {
+		return nil, nil
+	}
+
+	var first, last time.Time
+	for _, entry := range logs {
+		if entry.Timestamp.IsZero() {
+			continue
+		}
+		if first.IsZero() || entry.Timestamp.Before(first) {
+			first = entry.Timestamp
+		}
+		if entry.Timestamp.After(last) {
+			last = entry.Timestamp
+		}
+	}
+	if first.IsZero() {
+		return nil, nil
+	}
+
+	start := first.Truncate(bucket)
+	span := last.Sub(start)
+	if span/bucket >= maxHistogramBuckets {
+		suggested := (span/maxHistogramBuckets + 1).Round(time.Second)
+		if suggested < time.Second {
+			suggested = time.Second
+		}
+		return nil, fmt.Errorf("%s buckets over %s of logs would make more than %d buckets; use a larger --bucket, such as %s", bucket, span.Round(time.Second), maxHistogramBuckets, suggested)
+	}
+	buckets := make([]HistogramBucket, int(span/bucket)+1)
+	for i := range buckets {
+		buckets[i].Start = start.Add(time.Duration(i) * bucket)
+	}
+
+	for _, entry := range logs {
+		if entry.Timestamp.IsZero() {
+			continue
+		}
+		index := int(entry.Timestamp.Sub(start) / bucket)
+		buckets[index].Count++
+		if matcher.MatchEntry(entry) == docker.LevelError {
+			buckets[index].Errors++
+		}
+	}
+	return buckets, nil
+}
+
+// GetLogHistogram retrieves a container's logs and buckets them by time
+func (c *Colog) GetLogHistogram(containerID string, bucket time.Duration, options LogOptions) ([]HistogramBucket, error) {
+	logs, err := c.GetContainerLogs(containerID, options)
+	if err != nil {
+		return nil, err
+	}
+	return BuildHistogram(logs, bucket, docker.NewLevelMatcher())
+}
+
+// RepeatedMessage is a log message and how many times it was logged
+type RepeatedMessage struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// ContainerLogSummary is the local triage of one container's logs
+type ContainerLogSummary struct {
+	Container   ContainerInfo     `json:"container"`
+	Lines       int               `json:"lines"`
+	Errors      int               `json:"errors"`
+	Warnings    int               `json:"warnings"`
+	TopMessages []RepeatedMessage `json:"top_messages"` // most repeated messages, most frequent first
+	TimeRange   TimeRange         `json:"time_range"`
+	ErrorLines  []string          `json:"error_lines,omitempty"` // the latest error lines, oldest first
+	Error       string            `json:"error,omitempty"` // why the logs couldn't be read
+}
+
+// LogSummary triages several containers' logs without any external service
+type LogSummary struct {
+	GeneratedAt time.Time             `json:"generated_at"`
+	Containers  []ContainerLogSummary `json:"containers"`
+	Errors      int                   `json:"errors"`
+	Warnings    int                   `json:"warnings"`
+}
+
+// summaryTopMessages is how many repeated messages a container summary lists
+const summaryTopMessages = 5
+
+// summaryErrorLines is how many of its latest error lines a container summary keeps
+const summaryErrorLines = 3
+
+// SummarizeEntries counts a container's errors and warnings (structured levels first,
+// like exports), its most repeated messages and the time span of its logs
+func SummarizeEntries(container ContainerInfo, logs []docker.LogEntry, matcher *docker.LevelMatcher) ContainerLogSummary {
+	summary := ContainerLogSummary{Container: container, Lines: len(logs), TopMessages: []RepeatedMessage{}}
+
+	counts := make(map[string]int)
+	for _, entry := range logs {
+		switch matcher.MatchEntry(entry) {
+		case docker.LevelError:
+			summary.Errors++
+			summary.ErrorLines = append(summary.ErrorLines, entry.Message)
+			if len(summary.ErrorLines) > summaryErrorLines {
+				summary.ErrorLines = summary.ErrorLines[1:]
+			}
+		case docker.LevelWarn:
+			summary.Warnings++
+		}
+		counts[entry.Message] += max(entry.Repeats, 1)
+
+		if summary.TimeRange.Start.IsZero() || entry.Timestamp.Before(summary.TimeRange.Start) {
+			summary.TimeRange.Start = entry.Timestamp
+		}
+		if entry.Timestamp.After(summary.TimeRange.End) {
+			summary.TimeRange.End = entry.Timestamp
+		}
+	}
+
+	for message, count := range counts {
+		if count > 1 {
+			summary.TopMessages = append(summary.TopMessages, RepeatedMessage{Message: message, Count: count})
+		}
+	}
+	sort.Slice(summary.TopMessages, func(i, j int) bool {
+		a, b := summary.TopMessages[i], summary.TopMessages[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Message < b.Message
+	})
+	if len(summary.TopMessages) > summaryTopMessages {
+		summary.TopMessages = summary.TopMessages[:summaryTopMessages]
+	}
+	return summary
+}
+
+// SummarizeLogs triages the containers' logs locally: error and warning counts, the
+// most repeated messages and the time range per container. Containers are IDs, names
+// or name globs; none summarizes every running container. A container whose logs
+// can't be read is listed with the reason instead of failing the summary.
+func (c *Colog) SummarizeLogs(containerIDs []string, options LogOptions) (*LogSummary, error) {
+	var containers []ContainerInfo
+	var err error
+	if len(containerIDs) > 0 {
+		containers, err = c.ResolveContainers(containerIDs)
+	} else {
+		containers, err = c.ListRunningContainers()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LogSummary{GeneratedAt: timeNow(), Containers: []ContainerLogSummary{}}
+	matcher := docker.NewLevelMatcher()
+	for _, container := range containers {
+		logs, err := c.GetContainerLogs(container.ID, options)
+		summary := SummarizeEntries(container, logs, matcher)
+		if err != nil {
+			summary.Error = err.Error()
+		}
+		report.Containers = append(report.Containers, summary)
+		report.Errors += summary.Errors
+		report.Warnings += summary.Warnings
+	}
+	return report, nil
+}
+
+// RankByErrors orders the summary's containers by error count, then warning count,
+// most first, so the most broken containers lead it
+func (r *LogSummary) RankByErrors() {
+	sort.SliceStable(r.Containers, func(i, j int) bool {
+		a, b := r.Containers[i], r.Containers[j]
+		if a.Errors != b.Errors {
+			return a.Errors > b.Errors
+		}
+		return a.Warnings > b.Warnings
+	})
+}
+
+// WriteErrorSummary writes a short triage of the summary for agents and humans
+// asking what's broken: each container with errors or warnings in the summary's
+// order, with its latest error lines, and the containers whose logs couldn't be
+// read. Quiet containers are only counted.
+func WriteErrorSummary(w io.Writer, report *LogSummary) {
+	var broken, unreadable []ContainerLogSummary
+	for _, summary := range report.Containers {
+		switch {
+		case summary.Error != "":
+			unreadable = append(unreadable, summary)
+		case summary.Errors > 0 || summary.Warnings > 0:
+			broken = append(broken, summary)
+		}
+	}
+
+	if len(broken) == 0 {
+		fmt.Fprintf(w, "No errors or warnings in %d containers\n", len(report.Containers))
+	} else {
+		fmt.Fprintf(w, "%d errors and %d warnings in %d of %d containers:\n", report.Errors, report.Warnings, len(broken), len(report.Containers))
+		for i, summary := range broken {
+			fmt.Fprintf(w, "\n%d. %s (%s): %d errors, %d warnings in %d lines\n", i+1, summary.Container.DisplayName(), docker.ShortID(summary.Container.ID), summary.Errors, summary.Warnings, summary.Lines)
+			for _, line := range summary.ErrorLines {
+				fmt.Fprintf(w, "   > %s\n", docker.Truncate(line, 200))
+			}
+		}
+	}
+
+	if len(unreadable) > 0 {
+		fmt.Fprintln(w, "\nLogs couldn't be read from:")
+		for _, summary := range unreadable {
+			fmt.Fprintf(w, "- %s: %s\n", summary.Container.DisplayName(), summary.Error)
+		}
+	}
+}
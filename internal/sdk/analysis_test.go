@@ -0,0 +1,180 @@
+package sdk
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+func logsWith(messages ...string) []docker.LogEntry {
+	logs := make([]docker.LogEntry, len(messages))
+	for i, message := range messages {
+		logs[i] = docker.LogEntry{Message: message}
+	}
+	return logs
+}
+
+func TestExtractNumeric(t *testing.T) {
+	logs := logsWith(
+		"GET /api took 120ms",
+		"cache miss",
+		"GET /health took 4ms",
+		"POST /api took 20.5ms",
+		"GET /slow took ?ms",
+	)
+
+	stats := ExtractNumeric(logs, regexp.MustCompile(`took ([\d.?]+)ms`))
+
+	if stats.Lines != 5 || stats.Matches != 4 || stats.Count != 3 || stats.Skipped != 1 {
+		t.Fatalf("got lines=%d matches=%d count=%d skipped=%d, want 5/4/3/1", stats.Lines, stats.Matches, stats.Count, stats.Skipped)
+	}
+	if stats.Min != 4 || stats.Max != 120 || stats.Sum != 144.5 {
+		t.Errorf("got min=%v max=%v sum=%v, want 4/120/144.5", stats.Min, stats.Max, stats.Sum)
+	}
+	if avg, _ := stats.Aggregate("avg"); avg != 144.5/3 {
+		t.Errorf("got avg %v, want %v", avg, 144.5/3)
+	}
+}
+
+func TestExtractNumericWithoutMatches(t *testing.T) {
+	stats := ExtractNumeric(logsWith("nothing here"), regexp.MustCompile(`took (\d+)ms`))
+
+	if stats.Count != 0 || stats.Min != 0 || stats.Max != 0 || stats.Avg != 0 {
+		t.Errorf("got %+v, want zero aggregates", stats)
+	}
+}
+
+func TestExtractStatsAggregateRejectsUnknown(t *testing.T) {
+	if _, err := (ExtractStats{}).Aggregate("p99"); err == nil {
+		t.Error("expected an error for an unsupported aggregate")
+	}
+}
+
+func TestBuildHistogram(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	logs := []docker.LogEntry{
+		{Timestamp: base.Add(5 * time.Second), Message: "started"},
+		{Timestamp: base.Add(30 * time.Second), Message: "ERROR: connection refused"},
+		{Timestamp: base.Add(3*time.Minute + time.Second), Message: "request served"},
+	}
+
+	// An entry without a timestamp would stretch the range back to year 1
+	logs = append(logs, docker.LogEntry{Message: "no timestamp"})
+	buckets, err := BuildHistogram(logs, time.Minute, &docker.LevelMatcher{Error: []string{"error"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []HistogramBucket{
+		{Start: base, Count: 2, Errors: 1},
+		{Start: base.Add(time.Minute)},
+		{Start: base.Add(2 * time.Minute)},
+		{Start: base.Add(3 * time.Minute), Count: 1},
+	}
+	if len(buckets) != len(want) {
+		t.Fatalf("got %d buckets, want %d", len(buckets), len(want))
+	}
+	for i := range want {
+		if !buckets[i].Start.Equal(want[i].Start) || buckets[i].Count != want[i].Count || buckets[i].Errors != want[i].Errors {
+			t.Errorf("bucket %d: got %+v, want %+v", i, buckets[i], want[i])
+		}
+	}
+}
+
+func TestBuildHistogramEmpty(t *testing.T) {
+	if buckets, err := BuildHistogram(nil, time.Minute, docker.NewLevelMatcher()); buckets != nil || err != nil {
+		t.Errorf("got %v, %v; want nil", buckets, err)
+	}
+}
+
+func TestBuildHistogramCapsBuckets(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	logs := []docker.LogEntry{{Timestamp: base}, {Timestamp: base.Add(24 * time.Hour)}}
+
+	_, err := BuildHistogram(logs, time.Millisecond, docker.NewLevelMatcher())
+	if err == nil || !strings.Contains(err.Error(), "use a larger --bucket, such as 9s") {
+		t.Fatalf("1ms buckets over a day: error = %v, want a larger bucket suggested", err)
+	}
+	if buckets, err := BuildHistogram(logs, 9*time.Second, docker.NewLevelMatcher()); err != nil || len(buckets) > maxHistogramBuckets {
+		t.Errorf("suggested bucket: %d buckets, %v", len(buckets), err)
+	}
+}
+
+func TestSummarizeEntries(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	logs := []docker.LogEntry{
+		{Timestamp: base.Add(2 * time.Second), Message: "ERROR: connection refused"},
+		{Timestamp: base, Message: "GET /health 200", Repeats: 3},
+		{Timestamp: base.Add(time.Second), Message: "WARN slow query"},
+		{Timestamp: base.Add(3 * time.Second), Message: "ERROR: connection refused"},
+		{Timestamp: base.Add(4 * time.Second), Message: "started"},
+	}
+	matcher := &docker.LevelMatcher{Error: []string{"error"}, Warn: []string{"warn"}}
+
+	summary := SummarizeEntries(ContainerInfo{Name: "web"}, logs, matcher)
+	if summary.Lines != 5 || summary.Errors != 2 || summary.Warnings != 1 {
+		t.Errorf("got %d lines, %d errors, %d warnings; want 5, 2, 1", summary.Lines, summary.Errors, summary.Warnings)
+	}
+	want := []RepeatedMessage{{"GET /health 200", 3}, {"ERROR: connection refused", 2}}
+	if len(summary.TopMessages) != len(want) || summary.TopMessages[0] != want[0] || summary.TopMessages[1] != want[1] {
+		t.Errorf("top messages = %+v, want %+v", summary.TopMessages, want)
+	}
+	if len(summary.ErrorLines) != 2 || summary.ErrorLines[1] != "ERROR: connection refused" {
+		t.Errorf("error lines = %q", summary.ErrorLines)
+	}
+	if !summary.TimeRange.Start.Equal(base) || !summary.TimeRange.End.Equal(base.Add(4*time.Second)) {
+		t.Errorf("time range = %+v", summary.TimeRange)
+	}
+}
+
+func TestSummarizeLogs(t *testing.T) {
+	c := newFakeColog(t)
+	report, err := c.SummarizeLogs([]string{"web", "worker"}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Containers) != 2 || report.Containers[0].Container.Name != "web" {
+		t.Fatalf("summarized %+v", report.Containers)
+	}
+	if report.Errors != 2 || report.Warnings != 1 {
+		t.Errorf("report counts %d errors and %d warnings, want 2 and 1", report.Errors, report.Warnings)
+	}
+
+	if _, err := c.SummarizeLogs([]string{"nope"}, LogOptions{Tail: 100}); err == nil {
+		t.Error("unknown container accepted")
+	}
+}
+
+func TestWriteErrorSummary(t *testing.T) {
+	report := &LogSummary{Errors: 4, Warnings: 1, Containers: []ContainerLogSummary{
+		{Container: ContainerInfo{ID: "aaaaaaaaaaaa1111", Name: "quiet"}, Lines: 10},
+		{Container: ContainerInfo{ID: "bbbbbbbbbbbb2222", Name: "web"}, Lines: 10, Errors: 1, Warnings: 1, ErrorLines: []string{"ERROR timeout"}},
+		{Container: ContainerInfo{ID: "cccccccccccc3333", Name: "gone"}, Error: "no such container"},
+		{Container: ContainerInfo{ID: "dddddddddddd4444", Name: "db"}, Lines: 10, Errors: 3, ErrorLines: []string{"ERROR disk full"}},
+	}}
+	report.RankByErrors()
+	if names := report.Containers[0].Container.Name + "," + report.Containers[1].Container.Name; names != "db,web" {
+		t.Errorf("ranked %s first, want db,web", names)
+	}
+
+	var buf bytes.Buffer
+	WriteErrorSummary(&buf, report)
+	want := `4 errors and 1 warnings in 2 of 4 containers:
+
+1. db (dddddddddddd): 3 errors, 0 warnings in 10 lines
+   > ERROR disk full
+
+2. web (bbbbbbbbbbbb): 1 errors, 1 warnings in 10 lines
+   > ERROR timeout
+
+Logs couldn't be read from:
+- gone: no such container
+`
+	if buf.String() != want {
+		t.Errorf("summary:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -1,17 +1,35 @@
 package sdk
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/berkantay/colog/v2/internal/docker"
 )
 
 // Command-line interface for the SDK
 func RunSDKCommand(args []string) error {
+	return runSDKCommand(nil, args)
+}
+
+// RunSDKCommandWithColog runs an SDK command with an existing client instead of
+// connecting to Docker for it, so callers running several commands connect (and
+// discover endpoints) once. The client is left open.
+func RunSDKCommandWithColog(c *Colog, args []string) error {
+	return runSDKCommand(c, args)
+}
+
+// runSDKCommand runs a command with shared, or with a client of its own when nil
+func runSDKCommand(shared *Colog, args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("SDK command required. Use 'colog sdk --help' for usage")
 	}
@@ -23,18 +41,39 @@ func RunSDKCommand(args []string) error {
 		printSDKHelp()
 		return nil
 	case "list":
-		return runListCommand(args[1:])
+		return runListCommand(shared, args[1:])
 	case "logs":
-		return runLogsCommand(args[1:])
+		return runLogsCommand(shared, args[1:])
 	case "export":
-		return runExportCommand(args[1:])
+		return runExportCommand(shared, args[1:])
 	case "filter":
-		return runFilterCommand(args[1:])
+		return runFilterCommand(shared, args[1:])
+	case "extract":
+		return runExtractCommand(shared, args[1:])
+	case "histogram":
+		return runHistogramCommand(shared, args[1:])
+	case "stats":
+		return runStatsCommand(shared, args[1:])
+	case "summarize":
+		return runSummarizeCommand(shared, args[1:])
 	default:
 		return fmt.Errorf("unknown SDK command: %s", command)
 	}
 }
 
+// openColog returns shared, or connects a new client when it's nil; release closes
+// only a client opened here
+func openColog(shared *Colog) (*Colog, func(), error) {
+	if shared != nil {
+		return shared, func() {}, nil
+	}
+	sdk, err := NewColog(context.Background())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize SDK: %w", err)
+	}
+	return sdk, func() { sdk.Close() }, nil
+}
+
 func printSDKHelp() {
 	fmt.Println(`Colog SDK - Programmatic Docker Container Log Access
 
@@ -46,6 +85,10 @@ COMMANDS:
     logs              Get logs from containers
     export            Export logs for LLM analysis
     filter            Filter containers by criteria
+    extract           Aggregate numbers captured from log lines by a regex
+    histogram         Show log volume (and errors) over time
+    stats             Show a container's CPU, memory and network usage
+    summarize         Count errors and repeated messages per container (no AI needed)
     help              Show this help message
 
 EXAMPLES:
@@ -54,23 +97,37 @@ EXAMPLES:
     colog sdk logs <container_id> --tail 50     # Get last 50 log lines
     colog sdk export --format json --tail 100  # Export logs as JSON
     colog sdk filter --image nginx              # Filter containers by image
+    colog sdk extract abc123 --pattern 'took (\d+)ms' --agg avg
+    colog sdk histogram abc123 --bucket 1m      # Log volume per minute
+    colog sdk stats abc123                      # CPU/memory/network snapshot
+    colog sdk summarize --since 1h              # Offline triage of all containers
 
 For detailed usage of each command, use:
     colog sdk <command> --help`)
 }
 
-func runListCommand(args []string) error {
-	ctx := context.Background()
-	sdk, err := NewColog(ctx)
+func runListCommand(shared *Colog, args []string) error {
+	sdk, release, err := openColog(shared)
 	if err != nil {
-		return fmt.Errorf("failed to initialize SDK: %w", err)
+		return err
 	}
-	defer sdk.Close()
+	defer release()
 
 	showAll := false
-	for _, arg := range args {
+	fullIDs := false
+	quiet := false
+	host := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		if arg == "--all" || arg == "-a" {
 			showAll = true
+		} else if arg == "--quiet" || arg == "-q" {
+			quiet = true
+		} else if arg == "--full-ids" {
+			fullIDs = true
+		} else if arg == "--host" && i+1 < len(args) {
+			host = args[i+1]
+			i++
 		} else if arg == "--help" || arg == "-h" {
 			fmt.Println(`List containers
 
@@ -79,11 +136,16 @@ USAGE:
 
 OPTIONS:
     --all, -a         List all containers (including stopped)
+    --host <label>    Only list containers on this Docker host (see COLOG_DOCKER_HOSTS)
+    --full-ids        Show full container IDs instead of the 12-character short form
+    --quiet, -q       Only print full container IDs, one per line, like docker ps -q
     --help, -h        Show this help message
 
 EXAMPLES:
     colog sdk list                # List running containers
-    colog sdk list --all          # List all containers`)
+    colog sdk list --all          # List all containers
+    colog sdk list -q -a          # IDs of all containers, for scripts
+    colog sdk list --host staging # List running containers on the staging host`)
 			return nil
 		}
 	}
@@ -98,45 +160,43 @@ EXAMPLES:
 	if err != nil {
 		return fmt.Errorf("failed to list containers: %w", err)
 	}
+	containers = filterByHost(containers, host)
+
+	if quiet {
+		for _, container := range containers {
+			fmt.Println(container.ID)
+		}
+		return nil
+	}
 
 	if len(containers) == 0 {
 		fmt.Println("No containers found")
 		return nil
 	}
+	sdk.AttachHealth(containers)
 
-	fmt.Printf("%-12s %-20s %-30s %-15s\n", "ID", "NAME", "IMAGE", "STATUS")
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%-12s %-20s %-30s %-15s %s\n", "ID", "NAME", "IMAGE", "STATUS", "HEALTH")
+	fmt.Println(strings.Repeat("-", 90))
 	
 	for _, container := range containers {
 		id := container.ID
-		if len(id) > 12 {
-			id = id[:12]
-		}
-		name := container.Name
-		if len(name) > 20 {
-			name = name[:17] + "..."
-		}
-		image := container.Image
-		if len(image) > 30 {
-			image = image[:27] + "..."
-		}
-		status := container.Status
-		if len(status) > 15 {
-			status = status[:12] + "..."
+		if !fullIDs {
+			id = docker.ShortID(id)
 		}
+		name := docker.Truncate(container.DisplayName(), 20)
+		image := docker.Truncate(container.Image, 30)
+		status := docker.Truncate(container.Status, 15)
 		
-		fmt.Printf("%-12s %-20s %-30s %-15s\n", id, name, image, status)
+		fmt.Printf("%-12s %-20s %-30s %-15s %s\n", id, name, image, status, container.Health)
 	}
 
 	return nil
 }
 
-func runLogsCommand(args []string) error {
-	if len(args) < 1 {
-		return fmt.Errorf("container ID required")
-	}
-
-	containerID := args[0]
+func runLogsCommand(shared *Colog, args []string) error {
+	var refs []string
+	format := "text"
+	outputFile := ""
 	
 	// Parse options
 	options := LogOptions{
@@ -145,25 +205,39 @@ func runLogsCommand(args []string) error {
 		Timestamps: true,
 	}
 
-	for i := 1; i < len(args); i++ {
+	for i := 0; i < len(args); i++ {
 		switch args[i] {
 		case "--help", "-h":
-			fmt.Println(`Get logs from a container
+			fmt.Println(`Get logs from one or more containers
 
 USAGE:
-    colog sdk logs <container_id> [OPTIONS]
+    colog sdk logs <container>... [OPTIONS]
+
+    Containers are IDs, names or name globs ('web-*'). Logs of several
+    containers are interleaved by timestamp and prefixed with the container name.
 
 OPTIONS:
     --tail <n>        Number of log lines to retrieve (default: 50)
-    --follow, -f      Follow log output
-    --since <time>    Show logs since timestamp (RFC3339 format)
-    --until <time>    Show logs until timestamp (RFC3339 format)
+    --follow, -f      Follow log output (a single container only)
+    --since <time>    Show logs since a relative duration (10m, 2h30m) or RFC3339 timestamp
+    --until <time>    Show logs until a relative duration (5m = until 5 minutes ago) or RFC3339 timestamp
     --no-timestamps   Don't show timestamps
+    --min-level <lvl> Only show entries at or above debug, info, warn or error
+                      (lines without a detectable level count as info)
+    --drop-unleveled  Drop lines without a detectable level
+    --format <format> Output format: text, json (default: text); json is an array
+                      of log entries with their timestamp, stream and message
+    --output <file>   Write the logs to a file instead of stdout
     --help, -h        Show this help message
 
 EXAMPLES:
     colog sdk logs abc123 --tail 100           # Get last 100 log lines
+    colog sdk logs web api worker --tail 50    # Three services, interleaved
+    colog sdk logs 'web-*'                     # Every container named web-...
+    colog sdk logs abc123 --format json --output abc123.json
+    colog sdk logs abc123 --min-level error    # Only errors
     colog sdk logs abc123 --follow             # Follow logs in real-time
+    colog sdk logs abc123 --since 10m            # Logs from the last 10 minutes
     colog sdk logs abc123 --since 2024-01-01T10:00:00Z`)
 			return nil
 		case "--tail":
@@ -177,63 +251,227 @@ EXAMPLES:
 			options.Follow = true
 		case "--since":
 			if i+1 < len(args) {
-				if since, err := time.Parse(time.RFC3339, args[i+1]); err == nil {
-					options.Since = since
-					i++ // Skip the next argument
+				since, err := parseTimeArg(args[i+1], time.Now())
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
 				}
+				options.Since = since
+				i++ // Skip the next argument
 			}
 		case "--until":
 			if i+1 < len(args) {
-				if until, err := time.Parse(time.RFC3339, args[i+1]); err == nil {
-					options.Until = until
-					i++ // Skip the next argument
+				until, err := parseTimeArg(args[i+1], time.Now())
+				if err != nil {
+					return fmt.Errorf("invalid --until: %w", err)
 				}
+				options.Until = until
+				i++ // Skip the next argument
 			}
 		case "--no-timestamps":
 			options.Timestamps = false
+		case "--min-level":
+			if i+1 < len(args) {
+				options.MinLevel = args[i+1]
+				i++
+			}
+		case "--drop-unleveled":
+			options.DropUnleveled = true
+		case "--format":
+			if i+1 < len(args) {
+				format = strings.ToLower(args[i+1])
+				i++
+			}
+		case "--output":
+			if i+1 < len(args) {
+				outputFile = args[i+1]
+				i++
+			}
+		default:
+			if !strings.HasPrefix(args[i], "-") {
+				refs = append(refs, args[i])
+			}
 		}
 	}
+	if len(refs) == 0 {
+		return fmt.Errorf("container ID required")
+	}
+	if format != "text" && format != "json" {
+		return fmt.Errorf("unsupported format: %s (supported: text, json)", format)
+	}
 
-	ctx := context.Background()
-	sdk, err := NewColog(ctx)
+	sdk, release, err := openColog(shared)
 	if err != nil {
-		return fmt.Errorf("failed to initialize SDK: %w", err)
+		return err
 	}
-	defer sdk.Close()
+	defer release()
 
-	// Get container info first
-	container, err := sdk.GetContainerByID(containerID)
+	containers, err := sdk.ResolveContainers(refs)
 	if err != nil {
 		return fmt.Errorf("container not found: %w", err)
 	}
+	if options.Follow && len(containers) > 1 {
+		return fmt.Errorf("--follow takes a single container, got %d", len(containers))
+	}
 
-	fmt.Printf("Getting logs from container: %s (%s)\n", container.Name, container.ID[:12])
-	fmt.Println(strings.Repeat("-", 60))
+	// Only text on stdout gets a header; JSON and files stay machine-readable
+	toTerminal := format == "text" && outputFile == ""
+	if toTerminal {
+		if len(containers) == 1 {
+			fmt.Printf("Getting logs from container: %s (%s)\n", containers[0].DisplayName(), docker.ShortID(containers[0].ID))
+		} else {
+			names := make([]string, len(containers))
+			for i, container := range containers {
+				names[i] = container.DisplayName()
+			}
+			fmt.Printf("Getting logs from %d containers: %s\n", len(containers), strings.Join(names, ", "))
+		}
+		fmt.Println(strings.Repeat("-", 60))
+	}
 
-	logs, err := sdk.GetContainerLogs(container.ID, options)
-	if err != nil {
-		return fmt.Errorf("failed to get logs: %w", err)
+	var logs []docker.LogEntry
+	var prefixes map[string]string // container ID → line prefix, with several containers
+	if len(containers) == 1 {
+		logs, err = sdk.GetContainerLogs(containers[0].ID, options)
+		if err != nil {
+			return fmt.Errorf("failed to get logs: %w", err)
+		}
+	} else {
+		collections := make([]ContainerLogCollection, 0, len(containers))
+		for _, container := range containers {
+			containerLogs, err := sdk.GetContainerLogs(container.ID, options)
+			if err != nil {
+				return fmt.Errorf("failed to get logs from %s: %w", container.DisplayName(), err)
+			}
+			collections = append(collections, newLogCollection(container, containerLogs))
+		}
+		logs = interleaveLogs(collections)
+		prefixes = namePrefixes(containers)
 	}
 
-	if len(logs) == 0 {
+	if len(logs) == 0 && toTerminal {
 		fmt.Println("No logs found")
 		return nil
 	}
 
+	if outputFile == "" {
+		return writeLogs(os.Stdout, logs, format, options.Timestamps, prefixes)
+	}
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	if err := writeLogs(file, logs, format, options.Timestamps, prefixes); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	fmt.Printf("%d log lines written to %s (%s format)\n", len(logs), outputFile, format)
+	return nil
+}
+
+// interleaveLogs merges the collections' logs into one list ordered by timestamp.
+// Lines without a timestamp keep their place after the container's previous line.
+func interleaveLogs(collections []ContainerLogCollection) []docker.LogEntry {
+	type timedEntry struct {
+		at    time.Time
+		entry docker.LogEntry
+	}
+	var timed []timedEntry
+	for _, collection := range collections {
+		var at time.Time
+		for _, entry := range collection.Logs {
+			if !entry.Timestamp.IsZero() {
+				at = entry.Timestamp
+			}
+			entry.ContainerID = collection.Container.ID
+			timed = append(timed, timedEntry{at: at, entry: entry})
+		}
+	}
+	sort.SliceStable(timed, func(i, j int) bool { return timed[i].at.Before(timed[j].at) })
+
+	logs := make([]docker.LogEntry, len(timed))
+	for i, t := range timed {
+		logs[i] = t.entry
+	}
+	return logs
+}
+
+// namePrefixes returns each container's name padded to a common width, like
+// docker compose logs: "web    | "
+func namePrefixes(containers []ContainerInfo) map[string]string {
+	width := 0
+	for _, container := range containers {
+		width = max(width, utf8.RuneCountInString(container.DisplayName()))
+	}
+	prefixes := make(map[string]string, len(containers))
+	for _, container := range containers {
+		prefixes[container.ID] = fmt.Sprintf("%-*s | ", width, container.DisplayName())
+	}
+	return prefixes
+}
+
+// writeLogs writes log entries as text lines or, for format json, as a JSON array
+// of docker.LogEntry. Text lines start with their container's prefix when prefixes
+// is given.
+func writeLogs(w io.Writer, logs []docker.LogEntry, format string, timestamps bool, prefixes map[string]string) error {
+	if format == "json" {
+		if logs == nil {
+			logs = []docker.LogEntry{} // [] rather than null
+		}
+		data, err := json.MarshalIndent(logs, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(data))
+		return err
+	}
+
 	for _, logEntry := range logs {
-		if options.Timestamps {
-			fmt.Printf("[%s] %s\n", logEntry.Timestamp.Format("2006-01-02 15:04:05"), logEntry.Message)
+		var err error
+		prefix := prefixes[logEntry.ContainerID]
+		if timestamps {
+			_, err = fmt.Fprintf(w, "%s[%s] %s\n", prefix, logEntry.Timestamp.Format("2006-01-02 15:04:05"), logEntry.Message)
 		} else {
-			fmt.Println(logEntry.Message)
+			_, err = fmt.Fprintln(w, prefix+logEntry.Message)
+		}
+		if err != nil {
+			return err
 		}
 	}
-
 	return nil
 }
 
-func runExportCommand(args []string) error {
+// parseTimeArg resolves a --since/--until value. A Go duration such as "10m" or
+// "2h30m" means that long before now and is tried first; otherwise the value must
+// be an RFC3339 timestamp.
+func parseTimeArg(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		if duration < 0 {
+			return time.Time{}, fmt.Errorf("duration %q must not be negative", value)
+		}
+		return now.Add(-duration), nil
+	}
+	if timestamp, err := time.Parse(time.RFC3339, value); err == nil {
+		return timestamp, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a duration (e.g. 10m, 2h30m) nor an RFC3339 timestamp", value)
+}
+
+// ParseTimeArg resolves a since/until value relative to the current time, the
+// way the --since and --until flags do.
+func ParseTimeArg(value string) (time.Time, error) {
+	return parseTimeArg(value, time.Now())
+}
+
+func runExportCommand(shared *Colog, args []string) error {
 	format := "markdown"
 	outputFile := ""
+	outputDir := ""
+	host := ""
+	headerFile := ""
+	footerFile := ""
 	options := LogOptions{
 		Tail:       100,
 		Follow:     false,
@@ -251,16 +489,34 @@ USAGE:
     colog sdk export [OPTIONS]
 
 OPTIONS:
-    --format <format>     Output format: json, markdown (default: markdown)
-    --output <file>       Output file (default: stdout)
+    --format <format>     Output format: json, jsonl, markdown, html (default: markdown)
+                          jsonl writes one JSON object per log line; html is a
+                          self-contained report to share or email
+    --output <file>       Output file (default: stdout); gzip-compressed when it
+                          ends in .gz
+    --dir <dir>           Write one file per container to this directory instead
     --tail <n>           Number of log lines per container (default: 100)
     --containers <ids>   Comma-separated container IDs (default: all running)
+    --host <label>       Only export containers on this Docker host; names in
+                         --containers are then looked up on that host
+    --header-template <file>
+                         Go text/template replacing the markdown summary header,
+                         executed with the export (.Summary, .Containers, ...)
+    --footer-template <file>
+                         Go text/template appended to the markdown export
+    --line-numbers       Number each container's lines in markdown exports and
+                         label them stdout/stderr, so analyses can cite "line 42"
     --help, -h           Show this help message
 
 EXAMPLES:
     colog sdk export --format json --output logs.json
+    colog sdk export --format jsonl | jq 'select(.stream == "stderr")'
     colog sdk export --containers abc123,def456 --tail 50
-    colog sdk export --format markdown > analysis.md`)
+    colog sdk export --format markdown > analysis.md
+    colog sdk export --format html --output report.html
+    colog sdk export --format jsonl --output logs.jsonl.gz
+    colog sdk export --format markdown --dir ./logs
+    colog sdk export --header-template incident.tmpl --output incident.md`)
 			return nil
 		case "--format":
 			if i+1 < len(args) {
@@ -272,6 +528,23 @@ EXAMPLES:
 				outputFile = args[i+1]
 				i++
 			}
+		case "--dir":
+			if i+1 < len(args) {
+				outputDir = args[i+1]
+				i++
+			}
+		case "--header-template":
+			if i+1 < len(args) {
+				headerFile = args[i+1]
+				i++
+			}
+		case "--footer-template":
+			if i+1 < len(args) {
+				footerFile = args[i+1]
+				i++
+			}
+		case "--line-numbers":
+			options.LineNumbers = true
 		case "--tail":
 			if i+1 < len(args) {
 				if tail, err := strconv.Atoi(args[i+1]); err == nil {
@@ -284,64 +557,183 @@ EXAMPLES:
 				containerIDs = strings.Split(args[i+1], ",")
 				i++
 			}
+		case "--host":
+			if i+1 < len(args) {
+				host = args[i+1]
+				i++
+			}
 		}
 	}
 
-	ctx := context.Background()
-	sdk, err := NewColog(ctx)
+	sdk, release, err := openColog(shared)
 	if err != nil {
-		return fmt.Errorf("failed to initialize SDK: %w", err)
+		return err
 	}
-	defer sdk.Close()
+	defer release()
 
-	// If no specific containers specified, get all running containers
-	if len(containerIDs) == 0 {
-		containers, err := sdk.ListRunningContainers()
+	if headerFile != "" || footerFile != "" {
+		tmpl, err := loadMarkdownTemplate(headerFile, footerFile)
 		if err != nil {
-			return fmt.Errorf("failed to list containers: %w", err)
-		}
-		
-		for _, container := range containers {
-			containerIDs = append(containerIDs, container.ID)
+			return err
 		}
+		// A shared client keeps its own template for later commands
+		defer sdk.SetMarkdownTemplate(sdk.markdownTemplate)
+		sdk.SetMarkdownTemplate(tmpl)
 	}
 
-	if len(containerIDs) == 0 {
-		return fmt.Errorf("no containers found to export")
+	// List once; resolving the references and exporting both use this list
+	containers, err := sdk.ListAllContainers()
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
 	}
-
-	var output string
-	switch strings.ToLower(format) {
-	case "json":
-		output, err = sdk.ExportLogsAsJSON(containerIDs, options)
-	case "markdown", "md":
-		output, err = sdk.ExportLogsAsMarkdown(containerIDs, options)
-	default:
-		return fmt.Errorf("unsupported format: %s (supported: json, markdown)", format)
+	containerIDs, err = exportTargets(containers, containerIDs, host)
+	if err != nil {
+		return err
 	}
 
+	sink, closeOutput, err := exportSink(format, outputFile, outputDir)
+	if err != nil {
+		return err
+	}
+	err = sdk.ExportContainersToSink(containers, containerIDs, options, sink)
+	if closeErr := sink.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := closeOutput(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write output file: %w", closeErr)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to export logs: %w", err)
 	}
 
-	// Output to file or stdout
-	if outputFile != "" {
-		err = os.WriteFile(outputFile, []byte(output), 0644)
+	switch {
+	case outputDir != "":
+		fmt.Printf("Logs exported to %s (%s format, one file per container)\n", outputDir, format)
+	case outputFile != "":
+		fmt.Printf("Logs exported to %s (%s format)\n", outputFile, format)
+	}
+	return nil
+}
+
+// loadMarkdownTemplate reads the export's header and footer templates from files;
+// an empty name keeps the default
+func loadMarkdownTemplate(headerFile, footerFile string) (*MarkdownTemplate, error) {
+	var texts [2]string
+	for i, file := range []string{headerFile, footerFile} {
+		if file == "" {
+			continue
+		}
+		data, err := os.ReadFile(file)
 		if err != nil {
-			return fmt.Errorf("failed to write output file: %w", err)
+			return nil, fmt.Errorf("failed to read template: %w", err)
 		}
-		fmt.Printf("Logs exported to %s (%s format, %d characters)\n", 
-			outputFile, format, len(output))
-	} else {
-		fmt.Println(output)
+		texts[i] = string(data)
 	}
+	return ParseMarkdownTemplate(texts[0], texts[1])
+}
+
+// exportTargets picks the containers to export from all containers: refs, resolved
+// on host when one is given (the same name can exist on several hosts), or every
+// running container (on host)
+func exportTargets(containers []ContainerInfo, refs []string, host string) ([]string, error) {
+	containers = filterByHost(containers, host)
+	if len(refs) > 0 {
+		if host == "" {
+			return refs, nil
+		}
+		resolved := make([]string, len(refs))
+		for i, ref := range refs {
+			container, exists := findContainer(containers, ref)
+			if !exists {
+				return nil, fmt.Errorf("%w: %s on host %s", ErrContainerNotFound, ref, host)
+			}
+			resolved[i] = container.ID
+		}
+		return resolved, nil
+	}
+
+	var running []string
+	for _, container := range containers {
+		// Like ListRunningContainers, which paused containers are part of
+		if container.State == "running" || container.State == "paused" {
+			running = append(running, container.ID)
+		}
+	}
+	if len(running) == 0 {
+		return nil, fmt.Errorf("no containers found to export")
+	}
+	return running, nil
+}
 
+// exportSink builds the sink for the export command's flags: one file per container
+// in outputDir, or a single export to outputFile (gzip-compressed when it ends in
+// .gz) or stdout. closeOutput closes the output file once the sink is closed.
+func exportSink(format, outputFile, outputDir string) (sink ExportSink, closeOutput func() error, err error) {
+	closeOutput = func() error { return nil }
+	if outputDir != "" {
+		sink, err = NewDirSink(outputDir, format)
+		return sink, closeOutput, err
+	}
+	if outputFile == "" {
+		sink, err = NewFormatSink(os.Stdout, format)
+		return sink, closeOutput, err
+	}
+
+	// Check the format before creating the file
+	if _, err := NewFormatSink(io.Discard, format); err != nil {
+		return nil, nil, err
+	}
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %w", err)
+	}
+	var w io.Writer = file
+	closeOutput = file.Close
+	if strings.HasSuffix(outputFile, ".gz") {
+		compressed := gzip.NewWriter(file)
+		w = compressed
+		closeOutput = func() error {
+			if err := compressed.Close(); err != nil {
+				file.Close()
+				return err
+			}
+			return file.Close()
+		}
+	}
+	sink, _ = NewFormatSink(w, format)
+	return sink, closeOutput, nil
+}
+
+// filterByHost keeps the containers on the given Docker host; all of them when host is empty
+func filterByHost(containers []ContainerInfo, host string) []ContainerInfo {
+	if host == "" {
+		return containers
+	}
+	var filtered []ContainerInfo
+	for _, container := range containers {
+		if container.Host == host {
+			filtered = append(filtered, container)
+		}
+	}
+	return filtered
+}
+
+// addLabelFilter adds a --label "key=value" argument to labels. The value may itself
+// contain "=" and may be empty; the key may not.
+func addLabelFilter(labels map[string]string, arg string) error {
+	key, value, ok := strings.Cut(arg, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("invalid --label %q: expected key=value (e.g. com.docker.compose.project=myapp)", arg)
+	}
+	labels[key] = value
 	return nil
 }
 
-func runFilterCommand(args []string) error {
+func runFilterCommand(shared *Colog, args []string) error {
 	filter := ContainerFilter{}
 	format := "table"
+	fullIDs := false
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -356,12 +748,16 @@ OPTIONS:
     --image <pattern>     Filter by image name pattern
     --image-id <id>       Filter by image ID
     --status <status>     Filter by container status
+    --host <label>        Filter by Docker host label (see COLOG_DOCKER_HOSTS)
+    --label <key=value>   Filter by container label; repeat to require several
     --format <format>     Output format: table, json (default: table)
+    --full-ids            Show full container IDs in the table
     --help, -h           Show this help message
 
 EXAMPLES:
     colog sdk filter --image nginx            # Find nginx containers
     colog sdk filter --name web --status running
+    colog sdk filter --label com.docker.compose.project=myapp
     colog sdk filter --format json`)
 			return nil
 		case "--name":
@@ -384,6 +780,24 @@ EXAMPLES:
 				filter.Status = args[i+1]
 				i++
 			}
+		case "--host":
+			if i+1 < len(args) {
+				filter.Host = args[i+1]
+				i++
+			}
+		case "--label":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--label requires a key=value argument")
+			}
+			if filter.Labels == nil {
+				filter.Labels = make(map[string]string)
+			}
+			if err := addLabelFilter(filter.Labels, args[i+1]); err != nil {
+				return err
+			}
+			i++
+		case "--full-ids":
+			fullIDs = true
 		case "--format":
 			if i+1 < len(args) {
 				format = args[i+1]
@@ -392,12 +806,11 @@ EXAMPLES:
 		}
 	}
 
-	ctx := context.Background()
-	sdk, err := NewColog(ctx)
+	sdk, release, err := openColog(shared)
 	if err != nil {
-		return fmt.Errorf("failed to initialize SDK: %w", err)
+		return err
 	}
-	defer sdk.Close()
+	defer release()
 
 	containers, err := sdk.FilterContainers(filter)
 	if err != nil {
@@ -408,6 +821,7 @@ EXAMPLES:
 		fmt.Println("No containers match the filter criteria")
 		return nil
 	}
+	sdk.AttachHealth(containers)
 
 	switch strings.ToLower(format) {
 	case "json":
@@ -418,32 +832,511 @@ EXAMPLES:
 		fmt.Println(string(jsonData))
 	case "table":
 		fmt.Printf("Found %d containers matching filter:\n\n", len(containers))
-		fmt.Printf("%-12s %-20s %-30s %-15s\n", "ID", "NAME", "IMAGE", "STATUS")
-		fmt.Println(strings.Repeat("-", 80))
+		fmt.Printf("%-12s %-20s %-30s %-15s %s\n", "ID", "NAME", "IMAGE", "STATUS", "HEALTH")
+		fmt.Println(strings.Repeat("-", 90))
 		
 		for _, container := range containers {
 			id := container.ID
-			if len(id) > 12 {
-				id = id[:12]
+			if !fullIDs {
+				id = docker.ShortID(id)
+			}
+			name := docker.Truncate(container.DisplayName(), 20)
+			image := docker.Truncate(container.Image, 30)
+			status := docker.Truncate(container.Status, 15)
+			
+			fmt.Printf("%-12s %-20s %-30s %-15s %s\n", id, name, image, status, container.Health)
+		}
+	default:
+		return fmt.Errorf("unsupported format: %s (supported: table, json)", format)
+	}
+
+	return nil
+}
+
+func runExtractCommand(shared *Colog, args []string) error {
+	if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		printExtractHelp()
+		return nil
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("container ID required")
+	}
+
+	containerID := args[0]
+	pattern := ""
+	aggregate := ""
+	format := "table"
+	options := LogOptions{
+		Tail:       1000,
+		Follow:     false,
+		Timestamps: true,
+	}
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			printExtractHelp()
+			return nil
+		case "--pattern", "-p":
+			if i+1 < len(args) {
+				pattern = args[i+1]
+				i++
 			}
-			name := container.Name
-			if len(name) > 20 {
-				name = name[:17] + "..."
+		case "--agg":
+			if i+1 < len(args) {
+				aggregate = strings.ToLower(args[i+1])
+				i++
 			}
-			image := container.Image
-			if len(image) > 30 {
-				image = image[:27] + "..."
+		case "--tail":
+			if i+1 < len(args) {
+				if tail, err := strconv.Atoi(args[i+1]); err == nil {
+					options.Tail = tail
+					i++
+				}
 			}
-			status := container.Status
-			if len(status) > 15 {
-				status = status[:12] + "..."
+		case "--format":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
 			}
-			
-			fmt.Printf("%-12s %-20s %-30s %-15s\n", id, name, image, status)
 		}
+	}
+
+	if pattern == "" {
+		return fmt.Errorf("--pattern is required")
+	}
+
+	sdk, release, err := openColog(shared)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	container, err := sdk.GetContainerByID(containerID)
+	if err != nil {
+		return fmt.Errorf("container not found: %w", err)
+	}
+
+	stats, err := sdk.ExtractFromLogs(container.ID, pattern, options)
+	if err != nil {
+		return fmt.Errorf("failed to extract values: %w", err)
+	}
+
+	// A single aggregate prints just the number, for use in scripts
+	if aggregate != "" && aggregate != "all" {
+		value, err := stats.Aggregate(aggregate)
+		if err != nil {
+			return err
+		}
+		fmt.Println(strconv.FormatFloat(value, 'f', -1, 64))
+		return nil
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		jsonData, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+	case "table":
+		fmt.Printf("Pattern %s on %s (%s)\n", stats.Pattern, container.DisplayName(), docker.ShortID(container.ID))
+		fmt.Println(strings.Repeat("-", 60))
+		fmt.Printf("%-10s %d of %d lines\n", "matched", stats.Matches, stats.Lines)
+		if stats.Skipped > 0 {
+			fmt.Printf("%-10s %d (capture not numeric)\n", "skipped", stats.Skipped)
+		}
+		fmt.Printf("%-10s %d\n", "count", stats.Count)
+		fmt.Printf("%-10s %g\n", "sum", stats.Sum)
+		fmt.Printf("%-10s %g\n", "min", stats.Min)
+		fmt.Printf("%-10s %g\n", "max", stats.Max)
+		fmt.Printf("%-10s %.3f\n", "avg", stats.Avg)
+	default:
+		return fmt.Errorf("unsupported format: %s (supported: table, json)", format)
+	}
+
+	return nil
+}
+
+func printExtractHelp() {
+	fmt.Println(`Aggregate numeric values captured from log lines
+
+USAGE:
+    colog sdk extract <container_id> --pattern <regex> [OPTIONS]
+
+The first capture group of the pattern (or the whole match if it has no
+groups) is parsed as a number on every matching line.
+
+OPTIONS:
+    --pattern, -p <regex> Regular expression with a numeric capture group (required)
+    --agg <name>          Print a single aggregate: count, sum, min, max, avg (default: all)
+    --tail <n>            Number of log lines to scan (default: 1000)
+    --format <format>     Output format for all aggregates: table, json (default: table)
+    --help, -h            Show this help message
+
+EXAMPLES:
+    colog sdk extract abc123 --pattern 'took (\d+)ms' --agg avg
+    colog sdk extract api --pattern 'status=(\d+)' --agg count
+    colog sdk extract abc123 -p 'size=([\d.]+)' --format json`)
+}
+
+func runHistogramCommand(shared *Colog, args []string) error {
+	if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		printHistogramHelp()
+		return nil
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("container ID required")
+	}
+
+	containerID := args[0]
+	bucket := time.Minute
+	format := "bars"
+	options := LogOptions{
+		Tail:       1000,
+		Follow:     false,
+		Timestamps: true,
+	}
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			printHistogramHelp()
+			return nil
+		case "--bucket":
+			if i+1 < len(args) {
+				size, err := time.ParseDuration(args[i+1])
+				if err != nil || size <= 0 {
+					return fmt.Errorf("invalid bucket size: %s (e.g. 30s, 1m, 1h)", args[i+1])
+				}
+				bucket = size
+				i++
+			}
+		case "--tail":
+			if i+1 < len(args) {
+				if tail, err := strconv.Atoi(args[i+1]); err == nil {
+					options.Tail = tail
+					i++
+				}
+			}
+		case "--format":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		}
+	}
+
+	sdk, release, err := openColog(shared)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	container, err := sdk.GetContainerByID(containerID)
+	if err != nil {
+		return fmt.Errorf("container not found: %w", err)
+	}
+
+	buckets, err := sdk.GetLogHistogram(container.ID, bucket, options)
+	if err != nil {
+		return fmt.Errorf("failed to build histogram: %w", err)
+	}
+
+	if len(buckets) == 0 {
+		fmt.Println("No logs found")
+		return nil
+	}
+
+	switch strings.ToLower(format) {
+	case "csv":
+		fmt.Println("bucket_start,count,errors")
+		for _, b := range buckets {
+			fmt.Printf("%s,%d,%d\n", b.Start.Format(time.RFC3339), b.Count, b.Errors)
+		}
+	case "json":
+		jsonData, err := json.MarshalIndent(buckets, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+	case "bars":
+		printHistogramBars(container, bucket, buckets)
+	default:
+		return fmt.Errorf("unsupported format: %s (supported: bars, csv, json)", format)
+	}
+
+	return nil
+}
+
+// printHistogramBars draws one bar per bucket, with error lines shown as '!'
+func printHistogramBars(container *ContainerInfo, bucket time.Duration, buckets []HistogramBucket) {
+	const barWidth = 50
+
+	peak := 0
+	for _, b := range buckets {
+		if b.Count > peak {
+			peak = b.Count
+		}
+	}
+
+	layout := "15:04:05"
+	if bucket >= 24*time.Hour {
+		layout = "2006-01-02"
+	} else if buckets[len(buckets)-1].Start.Sub(buckets[0].Start) >= 24*time.Hour {
+		layout = "01-02 15:04"
+	}
+
+	fmt.Printf("Log volume for %s (%s) per %s\n", container.DisplayName(), docker.ShortID(container.ID), bucket)
+	fmt.Println(strings.Repeat("-", 80))
+	for _, b := range buckets {
+		width, errorWidth := 0, 0
+		if peak > 0 {
+			width = b.Count * barWidth / peak
+			errorWidth = b.Errors * barWidth / peak
+			if b.Count > 0 && width == 0 {
+				width = 1
+			}
+			if b.Errors > 0 && errorWidth == 0 {
+				errorWidth = 1
+			}
+		}
+		bar := strings.Repeat("!", errorWidth) + strings.Repeat("#", width-errorWidth)
+		fmt.Printf("%-11s %-*s %d", b.Start.Format(layout), barWidth, bar, b.Count)
+		if b.Errors > 0 {
+			fmt.Printf(" (%d errors)", b.Errors)
+		}
+		fmt.Println()
+	}
+}
+
+func printHistogramHelp() {
+	fmt.Println(`Show log volume over time
+
+USAGE:
+    colog sdk histogram <container_id> [OPTIONS]
+
+Counts log lines (and error lines) per time bucket. In the bar chart,
+'!' marks the share of error lines.
+
+OPTIONS:
+    --bucket <duration>   Bucket size, e.g. 10s, 1m, 1h (default: 1m)
+    --tail <n>            Number of log lines to include (default: 1000)
+    --format <format>     Output format: bars, csv, json (default: bars)
+    --help, -h            Show this help message
+
+EXAMPLES:
+    colog sdk histogram abc123 --bucket 1m
+    colog sdk histogram api --bucket 10s --tail 5000
+    colog sdk histogram abc123 --format csv > volume.csv`)
+}
+
+func runStatsCommand(shared *Colog, args []string) error {
+	if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		printStatsHelp()
+		return nil
+	}
+	if len(args) < 1 {
+		return fmt.Errorf("container ID required")
+	}
+
+	containerID := args[0]
+	format := "table"
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			printStatsHelp()
+			return nil
+		case "--format":
+			if i+1 < len(args) {
+				format = args[i+1]
+				i++
+			}
+		}
+	}
+
+	sdk, release, err := openColog(shared)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	container, err := sdk.GetContainerByID(containerID)
+	if err != nil {
+		return fmt.Errorf("container not found: %w", err)
+	}
+
+	stats, err := sdk.GetContainerStats(container.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	switch strings.ToLower(format) {
+	case "json":
+		jsonData, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+	case "table":
+		fmt.Printf("Resource usage of %s (%s)\n", container.DisplayName(), docker.ShortID(container.ID))
+		fmt.Println(strings.Repeat("-", 60))
+		fmt.Printf("%-10s %.2f%%\n", "cpu", stats.CPUPercent)
+		fmt.Printf("%-10s %s / %s (%.2f%%)\n", "memory", formatBytes(stats.MemoryUsage), formatBytes(stats.MemoryLimit), stats.MemoryPercent)
+		fmt.Printf("%-10s rx %s, tx %s\n", "network", formatBytes(stats.NetworkRx), formatBytes(stats.NetworkTx))
 	default:
 		return fmt.Errorf("unsupported format: %s (supported: table, json)", format)
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. 1.5MiB
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func printStatsHelp() {
+	fmt.Println(`Show a container's resource usage
+
+USAGE:
+    colog sdk stats <container_id> [OPTIONS]
+
+Takes a single sample of CPU percentage, memory usage/limit and network I/O
+(bytes received and sent since the container started).
+
+OPTIONS:
+    --format <format>     Output format: table, json (default: table)
+    --help, -h            Show this help message
+
+EXAMPLES:
+    colog sdk stats abc123
+    colog sdk stats abc123 --format json`)
+}
+
+func runSummarizeCommand(shared *Colog, args []string) error {
+	var refs []string
+	format := "table"
+	options := LogOptions{
+		Tail:       1000,
+		Follow:     false,
+		Timestamps: true,
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--help", "-h":
+			printSummarizeHelp()
+			return nil
+		case "--tail":
+			if i+1 < len(args) {
+				if tail, err := strconv.Atoi(args[i+1]); err == nil {
+					options.Tail = tail
+					i++
+				}
+			}
+		case "--since":
+			if i+1 < len(args) {
+				since, err := parseTimeArg(args[i+1], time.Now())
+				if err != nil {
+					return fmt.Errorf("invalid --since: %w", err)
+				}
+				options.Since = since
+				i++
+			}
+		case "--format":
+			if i+1 < len(args) {
+				format = strings.ToLower(args[i+1])
+				i++
+			}
+		default:
+			if !strings.HasPrefix(args[i], "-") {
+				refs = append(refs, args[i])
+			}
+		}
+	}
+	if format != "table" && format != "json" {
+		return fmt.Errorf("unsupported format: %s (supported: table, json)", format)
+	}
+
+	sdk, release, err := openColog(shared)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	report, err := sdk.SummarizeLogs(refs, options)
+	if err != nil {
+		return fmt.Errorf("failed to summarize logs: %w", err)
+	}
+
+	if format == "json" {
+		jsonData, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonData))
+		return nil
+	}
+	writeSummary(os.Stdout, report)
+	return nil
+}
+
+// writeSummary prints a summary as a table with each container's repeated messages
+// below it
+func writeSummary(w io.Writer, report *LogSummary) {
+	if len(report.Containers) == 0 {
+		fmt.Fprintln(w, "No containers found")
+		return
+	}
+
+	fmt.Fprintf(w, "%-25s %7s %7s %8s  %s\n", "CONTAINER", "LINES", "ERRORS", "WARNINGS", "TIME RANGE")
+	fmt.Fprintln(w, strings.Repeat("-", 90))
+	for _, summary := range report.Containers {
+		span := "-"
+		if summary.Lines > 0 {
+			span = fmt.Sprintf("%s - %s", summary.TimeRange.Start.Format("2006-01-02 15:04:05"), summary.TimeRange.End.Format("15:04:05"))
+		}
+		if summary.Error != "" {
+			span = "error: " + summary.Error
+		}
+		fmt.Fprintf(w, "%-25s %7d %7d %8d  %s\n", docker.Truncate(summary.Container.DisplayName(), 25), summary.Lines, summary.Errors, summary.Warnings, span)
+		for _, repeated := range summary.TopMessages {
+			fmt.Fprintf(w, "    %5dx %s\n", repeated.Count, docker.Truncate(repeated.Message, 80))
+		}
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 90))
+	fmt.Fprintf(w, "%d containers, %d errors, %d warnings\n", len(report.Containers), report.Errors, report.Warnings)
+}
+
+func printSummarizeHelp() {
+	fmt.Println(`Triage container logs locally, without any AI service
+
+USAGE:
+    colog sdk summarize [container...] [OPTIONS]
+
+Counts errors and warnings, lists the most repeated messages and shows the
+time range of each container's logs. Containers are IDs, names or name globs;
+without any, every running container is summarized.
+
+OPTIONS:
+    --tail <n>            Number of log lines per container (default: 1000)
+    --since <time>        Only logs since a relative duration (10m) or RFC3339 timestamp
+    --format <format>     Output format: table, json (default: table)
+    --help, -h            Show this help message
+
+EXAMPLES:
+    colog sdk summarize
+    colog sdk summarize 'web-*' --since 1h
+    colog sdk summarize api worker --format json`)
+}
@@ -0,0 +1,207 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+func TestParseTimeArg(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{"10m", now.Add(-10 * time.Minute), false},
+		{"2h30m", now.Add(-150 * time.Minute), false},
+		{"0s", now, false},
+		{"2024-06-01T10:00:00Z", time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), false},
+		{"-5m", time.Time{}, true},
+		{"yesterday", time.Time{}, true},
+		{"10", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTimeArg(tt.value, now)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTimeArg(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTimeArg(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestExportTargets(t *testing.T) {
+	containers := []ContainerInfo{
+		{ID: "aaa111", Name: "web", State: "running", Host: "staging"},
+		{ID: "bbb222", Name: "web", State: "running", Host: "dev"},
+		{ID: "ccc333", Name: "job", State: "exited", Host: "dev"},
+		{ID: "ddd444", Name: "db", State: "paused", Host: "dev"},
+	}
+
+	tests := []struct {
+		refs    []string
+		host    string
+		want    []string
+		wantErr bool
+	}{
+		{nil, "", []string{"aaa111", "bbb222", "ddd444"}, false},
+		{nil, "dev", []string{"bbb222", "ddd444"}, false},
+		{[]string{"job"}, "", []string{"job"}, false},
+		{[]string{"web"}, "dev", []string{"bbb222"}, false},
+		{[]string{"db"}, "staging", nil, true},
+		{nil, "prod", nil, true},
+	}
+
+	for _, tt := range tests {
+		got, err := exportTargets(containers, tt.refs, tt.host)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("exportTargets(%v, %q) error = %v, wantErr %v", tt.refs, tt.host, err, tt.wantErr)
+			continue
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("exportTargets(%v, %q) = %v, want %v", tt.refs, tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestWriteLogs(t *testing.T) {
+	at := time.Date(2025, 3, 4, 11, 59, 1, 0, time.UTC)
+	logs := []docker.LogEntry{
+		{ContainerID: "abc", Timestamp: at, Message: "GET / 200", Stream: "stdout"},
+		{ContainerID: "abc", Timestamp: at.Add(time.Second), Message: "ERROR 接続が拒否されました", Stream: "stderr"},
+	}
+
+	var text bytes.Buffer
+	if err := writeLogs(&text, logs, "text", true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[2025-03-04 11:59:01] GET / 200\n[2025-03-04 11:59:02] ERROR 接続が拒否されました\n"; text.String() != want {
+		t.Errorf("text output = %q, want %q", text.String(), want)
+	}
+
+	var out bytes.Buffer
+	if err := writeLogs(&out, logs, "json", true, nil); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []docker.LogEntry
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("json output doesn't decode: %v\n%s", err, out.String())
+	}
+	if len(decoded) != 2 || decoded[1].Stream != "stderr" || !decoded[1].Timestamp.Equal(logs[1].Timestamp) {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	out.Reset()
+	if err := writeLogs(&out, nil, "json", true, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.TrimSpace(out.String()) != "[]" {
+		t.Errorf("no logs as json = %q, want []", out.String())
+	}
+}
+
+func TestResolveContainers(t *testing.T) {
+	c := newFakeColog(t)
+
+	tests := []struct {
+		refs    []string
+		want    []string
+		wantErr bool
+	}{
+		{[]string{"web"}, []string{"web"}, false},
+		{[]string{"worker", "web", "aaaaaaaaaaaa"}, []string{"worker", "web"}, false}, // short ID of web
+		{[]string{"w*"}, []string{"web", "worker"}, false},
+		{[]string{"cache", "*e*"}, []string{"cache", "web", "worker"}, false},
+		{[]string{"db"}, nil, true},
+		{[]string{"db-*"}, nil, true},
+		{[]string{"[web"}, nil, true},
+	}
+	for _, tt := range tests {
+		containers, err := c.ResolveContainers(tt.refs)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ResolveContainers(%v) error = %v, wantErr %v", tt.refs, err, tt.wantErr)
+			continue
+		}
+		var got []string
+		for _, container := range containers {
+			got = append(got, container.Name)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("ResolveContainers(%v) = %v, want %v", tt.refs, got, tt.want)
+		}
+	}
+}
+
+func TestContainerLookupErrors(t *testing.T) {
+	c := newFakeColog(t)
+	if _, err := c.ResolveContainers([]string{"db"}); !errors.Is(err, ErrContainerNotFound) {
+		t.Errorf("unknown name: error = %v, want ErrContainerNotFound", err)
+	}
+	if _, err := c.GetContainerByName("db"); !errors.Is(err, ErrContainerNotFound) {
+		t.Errorf("GetContainerByName: error = %v, want ErrContainerNotFound", err)
+	}
+
+	containers := []ContainerInfo{{ID: "ab12", Name: "web"}, {ID: "ab34", Name: "worker"}}
+	if _, err := containerByID(containers, "ab"); !errors.Is(err, ErrAmbiguousID) || !strings.Contains(err.Error(), "web, worker") {
+		t.Errorf("shared prefix: error = %v, want ErrAmbiguousID naming both", err)
+	}
+	if container, err := containerByID(containers, "ab3"); err != nil || container.Name != "worker" {
+		t.Errorf("unique prefix: got %v, %v", container.Name, err)
+	}
+
+	socket := &url.Error{Op: "Get", URL: "http://docker/v1.47/containers/json", Err: &net.OpError{Op: "dial", Net: "unix", Err: os.ErrPermission}}
+	if err := dockerError(socket); !errors.Is(err, ErrPermissionDenied) || !errors.Is(err, os.ErrPermission) {
+		t.Errorf("socket permission error classified as %v", err)
+	}
+	if err := dockerError(cerrdefs.ErrNotFound); !errors.Is(err, ErrContainerNotFound) {
+		t.Errorf("daemon not-found error classified as %v", err)
+	}
+	if other := errors.New("boom"); dockerError(other) != other {
+		t.Error("unknown errors should pass through unchanged")
+	}
+}
+
+func TestInterleaveLogs(t *testing.T) {
+	at := func(second int) time.Time { return time.Date(2025, 3, 4, 12, 0, second, 0, time.UTC) }
+	web := ContainerInfo{ID: "aaa", Name: "web"}
+	worker := ContainerInfo{ID: "bbb", Name: "worker"}
+	collections := []ContainerLogCollection{
+		newLogCollection(web, []docker.LogEntry{
+			{Timestamp: at(1), Message: "GET /"},
+			{Message: "  continued"}, // no timestamp: stays after GET /
+			{Timestamp: at(4), Message: "GET /health"},
+		}),
+		newLogCollection(worker, []docker.LogEntry{
+			{Timestamp: at(2), Message: "job started"},
+			{Timestamp: at(3), Message: "job done"},
+		}),
+	}
+
+	var buf bytes.Buffer
+	if err := writeLogs(&buf, interleaveLogs(collections), "text", false, namePrefixes([]ContainerInfo{web, worker})); err != nil {
+		t.Fatal(err)
+	}
+	want := "web    | GET /\n" +
+		"web    |   continued\n" +
+		"worker | job started\n" +
+		"worker | job done\n" +
+		"web    | GET /health\n"
+	if buf.String() != want {
+		t.Errorf("interleaved output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
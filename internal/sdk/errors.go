@@ -0,0 +1,66 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/client"
+)
+
+// Errors returned by the SDK, wrapped with details; test for them with errors.Is
+var (
+	// ErrContainerNotFound means no container matches an ID, name or pattern
+	ErrContainerNotFound = errors.New("container not found")
+	// ErrAmbiguousID means a short ID is the prefix of several containers' IDs
+	ErrAmbiguousID = errors.New("ambiguous container ID")
+	// ErrDockerUnavailable means the Docker daemon can't be reached
+	ErrDockerUnavailable = errors.New("docker unavailable")
+	// ErrPermissionDenied means the Docker daemon refused access, e.g. because the
+	// user can't open its socket
+	ErrPermissionDenied = errors.New("permission denied")
+)
+
+// dockerError wraps an error from Docker with the SDK error it stands for, keeping
+// the original in the chain; errors of no known kind are returned as they are
+func dockerError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, os.ErrPermission), cerrdefs.IsPermissionDenied(err), cerrdefs.IsUnauthorized(err):
+		return fmt.Errorf("%w: %w", ErrPermissionDenied, err)
+	case client.IsErrConnectionFailed(err), cerrdefs.IsUnavailable(err):
+		return fmt.Errorf("%w: %w", ErrDockerUnavailable, err)
+	case cerrdefs.IsNotFound(err):
+		return fmt.Errorf("%w: %w", ErrContainerNotFound, err)
+	}
+	return err
+}
+
+// containerByID finds the container whose ID is id, or starts with it. A prefix of
+// several containers' IDs is ambiguous, and none matching is ErrContainerNotFound.
+func containerByID(containers []ContainerInfo, id string) (ContainerInfo, error) {
+	var matches []ContainerInfo
+	for _, container := range containers {
+		if container.ID == id {
+			return container, nil
+		}
+		if matchesContainerID(container.ID, id) {
+			matches = append(matches, container)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return ContainerInfo{}, fmt.Errorf("%w: %s", ErrContainerNotFound, id)
+	case 1:
+		return matches[0], nil
+	}
+	names := make([]string, len(matches))
+	for i, container := range matches {
+		names[i] = container.DisplayName()
+	}
+	return ContainerInfo{}, fmt.Errorf("%w: %s matches %s", ErrAmbiguousID, id, strings.Join(names, ", "))
+}
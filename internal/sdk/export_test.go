@@ -0,0 +1,436 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+	containertypes "github.com/docker/docker/api/types/container"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// fakeDocker serves canned containers and logs in place of a Docker daemon
+type fakeDocker struct {
+	containers []docker.ContainerSummary
+	logs       map[string][]docker.LogEntry // container ID → logs, oldest first
+	digests    map[string]string            // image ID → repo digest
+	health     map[string]string            // container ID → health status
+	lists      int                          // ListContainerSummaries calls
+}
+
+func (f *fakeDocker) Close() error { return nil }
+
+func (f *fakeDocker) ListContainerSummaries(ctx context.Context, all bool) ([]docker.ContainerSummary, error) {
+	f.lists++
+	return f.containers, nil
+}
+
+func (f *fakeDocker) GetLogs(ctx context.Context, containerID string, opts docker.LogStreamOptions) ([]docker.LogEntry, error) {
+	// Like the daemon, accept names too
+	for _, container := range f.containers {
+		if container.Names[0] == "/"+containerID {
+			containerID = container.ID
+		}
+	}
+	logs, ok := f.logs[containerID]
+	if !ok {
+		return nil, fmt.Errorf("no such container: %s", containerID)
+	}
+	var window []docker.LogEntry
+	for _, entry := range logs {
+		if (opts.Since.IsZero() || !entry.Timestamp.Before(opts.Since)) && (opts.Until.IsZero() || entry.Timestamp.Before(opts.Until)) {
+			window = append(window, entry)
+		}
+	}
+	if opts.Tail >= 0 && len(window) > opts.Tail {
+		window = window[len(window)-opts.Tail:]
+	}
+	return window, nil
+}
+
+func (f *fakeDocker) List(ctx context.Context) ([]docker.Container, error) {
+	var containers []docker.Container
+	for _, summary := range f.containers {
+		containers = append(containers, docker.Container{ID: summary.ID, Name: docker.SummaryName(summary.Summary), Image: summary.Image})
+	}
+	return containers, nil
+}
+
+func (f *fakeDocker) Stream(ctx context.Context, containerID string, opts docker.LogStreamOptions) (<-chan docker.LogEntry, error) {
+	logs, err := f.GetLogs(ctx, containerID, opts)
+	if err != nil {
+		return nil, err
+	}
+	logCh := make(chan docker.LogEntry, len(logs))
+	for _, entry := range logs {
+		logCh <- entry
+	}
+	close(logCh)
+	return logCh, nil
+}
+
+func (f *fakeDocker) GetContainerStats(ctx context.Context, containerID string) (*docker.ContainerStats, error) {
+	return &docker.ContainerStats{ContainerID: containerID}, nil
+}
+
+func (f *fakeDocker) Health(ctx context.Context, containerID string) (string, error) {
+	return f.health[containerID], nil
+}
+
+func (f *fakeDocker) ImageDigest(ctx context.Context, containerID, image, imageID string) (string, error) {
+	return f.digests[imageID], nil
+}
+
+const (
+	webID    = "aaaaaaaaaaaa1111111111111111111111111111111111111111111111111111"
+	workerID = "bbbbbbbbbbbb2222222222222222222222222222222222222222222222222222"
+	cacheID  = "cccccccccccc3333333333333333333333333333333333333333333333333333"
+)
+
+// newFakeColog returns a Colog over three containers: web (mixed levels, tview tags,
+// non-ASCII), worker (one line) and cache (no logs), with the export clock pinned
+func newFakeColog(t *testing.T) *Colog {
+	t.Helper()
+	generatedAt := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return generatedAt }
+	t.Cleanup(func() { timeNow = time.Now })
+
+	at := func(second int) time.Time {
+		return time.Date(2025, 3, 4, 11, 59, second, 0, time.UTC)
+	}
+	summary := func(id, name, image, imageID string) docker.ContainerSummary {
+		return docker.ContainerSummary{Summary: containertypes.Summary{
+			ID:      id,
+			Names:   []string{"/" + name},
+			Image:   image,
+			ImageID: imageID,
+			Status:  "Up 5 minutes",
+			State:   "running",
+			Created: generatedAt.Add(-time.Hour).Unix(),
+		}}
+	}
+
+	return &Colog{
+		ctx: context.Background(),
+		dockerService: &fakeDocker{
+			containers: []docker.ContainerSummary{
+				summary(webID, "web", "nginx:1.27", "sha256:web"),
+				summary(workerID, "worker", "acme/worker:2", "sha256:worker"),
+				summary(cacheID, "cache", "nginx:1.27", "sha256:web"),
+			},
+			logs: map[string][]docker.LogEntry{
+				webID: {
+					{ContainerID: webID, Timestamp: at(1), Message: "GET / 200", Stream: "stdout"},
+					{ContainerID: webID, Timestamp: at(2), Message: "[red]not a color[-] [::b]", Stream: "stdout"},
+					{ContainerID: webID, Timestamp: at(3), Message: "WARN slow response from café ☕", Stream: "stdout"},
+					{ContainerID: webID, Timestamp: at(4), Message: `{"level":"error","msg":"upstream timed out"}`, Stream: "stderr"},
+					{ContainerID: webID, Timestamp: at(5), Message: "ERROR connection refused", Stream: "stderr"},
+				},
+				workerID: {
+					{ContainerID: workerID, Timestamp: at(0), Message: "job 42 done", Stream: "stdout"},
+				},
+				cacheID: {},
+			},
+			digests: map[string]string{"sha256:web": "nginx@sha256:0123"},
+			health:  map[string]string{webID: "unhealthy"},
+		},
+	}
+}
+
+// checkGolden compares got with testdata/name, rewriting the file with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run go test -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch (run go test -update if the change is intended)\ngot:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestExportLogsAsJSONGolden(t *testing.T) {
+	c := newFakeColog(t)
+	got, err := c.ExportLogsAsJSON([]string{webID, workerID, cacheID}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "export.json", got)
+
+	// The schema other tools consume
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(got), &decoded); err != nil {
+		t.Fatalf("export is not valid JSON: %v", err)
+	}
+	for _, key := range []string{"generated_at", "containers", "summary"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("export lacks %q", key)
+		}
+	}
+}
+
+func TestExportLogsAsMarkdownGolden(t *testing.T) {
+	c := newFakeColog(t)
+	got, err := c.ExportLogsAsMarkdown([]string{webID, workerID, cacheID}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "export.md", got)
+
+	// One section per container, messages verbatim
+	if n := strings.Count(got, "## Container: "); n != 3 {
+		t.Errorf("got %d container sections, want 3", n)
+	}
+	for _, message := range []string{"[red]not a color[-] [::b]", "café ☕"} {
+		if !strings.Contains(got, message) {
+			t.Errorf("markdown lost %q", message)
+		}
+	}
+}
+
+func TestListContainersHealth(t *testing.T) {
+	c := newFakeColog(t)
+	containers, err := c.ListRunningContainers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, container := range containers {
+		if container.Health != "" {
+			t.Errorf("%s: listing looked up Health %q", container.Name, container.Health)
+		}
+	}
+
+	// Exports look it up for the containers they export
+	output, err := c.ExportLogsForLLM([]string{webID}, LogOptions{Tail: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := output.Containers[0].Container.Health; got != "unhealthy" {
+		t.Errorf("exported Health = %q, want unhealthy", got)
+	}
+
+	c.AttachHealth(containers)
+	for _, container := range containers {
+		want := ""
+		if container.ID == webID {
+			want = "unhealthy"
+		}
+		if container.Health != want {
+			t.Errorf("%s: Health = %q, want %q", container.Name, container.Health, want)
+		}
+	}
+}
+
+func TestExportSummary(t *testing.T) {
+	c := newFakeColog(t)
+	output, err := c.ExportLogsForLLM([]string{webID, workerID, cacheID}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	summary := output.Summary
+	if summary.TotalContainers != 3 || summary.TotalLogs != 6 {
+		t.Errorf("totals = %d containers, %d logs; want 3, 6", summary.TotalContainers, summary.TotalLogs)
+	}
+	if summary.ErrorCount != 2 || summary.WarnCount != 1 {
+		t.Errorf("counts = %d errors, %d warnings; want 2, 1", summary.ErrorCount, summary.WarnCount)
+	}
+	wantStart := time.Date(2025, 3, 4, 11, 59, 0, 0, time.UTC)
+	wantEnd := time.Date(2025, 3, 4, 11, 59, 5, 0, time.UTC)
+	if !summary.TimeRange.Start.Equal(wantStart) || !summary.TimeRange.End.Equal(wantEnd) {
+		t.Errorf("time range = %v to %v, want %v to %v", summary.TimeRange.Start, summary.TimeRange.End, wantStart, wantEnd)
+	}
+	// nginx runs two of the three containers
+	if len(summary.TopImages) != 2 || summary.TopImages[0] != "nginx:1.27" || summary.TopImages[1] != "acme/worker:2" {
+		t.Errorf("top images = %v", summary.TopImages)
+	}
+
+	for _, collection := range output.Containers {
+		if collection.Container.ID == cacheID && (collection.LogCount != 0 || !collection.TimeRange.Start.IsZero()) {
+			t.Errorf("empty container: %d logs, range %v", collection.LogCount, collection.TimeRange)
+		}
+	}
+}
+
+func TestExportSingleContainer(t *testing.T) {
+	c := newFakeColog(t)
+	output, err := c.ExportLogsForLLM([]string{"worker"}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output.Containers) != 1 {
+		t.Fatalf("got %d containers, want 1", len(output.Containers))
+	}
+	worker := output.Containers[0]
+	if worker.Container.Name != "worker" || worker.LogCount != 1 || worker.Container.ImageDigest != "" {
+		t.Errorf("worker = %+v", worker.Container)
+	}
+	if output.Summary.ErrorCount != 0 || len(output.Summary.TopImages) != 1 {
+		t.Errorf("summary = %+v", output.Summary)
+	}
+}
+
+func TestExportNoLogs(t *testing.T) {
+	c := newFakeColog(t)
+	output, err := c.ExportLogsForLLM([]string{cacheID}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Summary.TotalLogs != 0 || !output.Summary.TimeRange.Start.IsZero() {
+		t.Errorf("summary = %+v", output.Summary)
+	}
+
+	markdown, err := c.ExportLogsAsMarkdown([]string{cacheID}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(markdown, "**Time Range:**") {
+		t.Error("markdown shows a time range without logs")
+	}
+}
+
+func TestExportLogsAsHTMLGolden(t *testing.T) {
+	c := newFakeColog(t)
+	c.dockerService.(*fakeDocker).logs[workerID] = append(c.dockerService.(*fakeDocker).logs[workerID],
+		docker.LogEntry{ContainerID: workerID, Timestamp: time.Date(2025, 3, 4, 11, 59, 1, 0, time.UTC), Message: `<script>alert("x")</script>`, Stream: "stdout"})
+	got, err := c.ExportLogsAsHTML([]string{webID, workerID, cacheID}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "export.html", got)
+
+	if n := strings.Count(got, "<details"); n != 3 {
+		t.Errorf("got %d container sections, want 3", n)
+	}
+	if n := strings.Count(got, `class="line error"`); n != 2 {
+		t.Errorf("got %d highlighted error lines, want 2", n)
+	}
+	if strings.Contains(got, "<script>") || !strings.Contains(got, "&lt;script&gt;") {
+		t.Error("log messages are not HTML-escaped")
+	}
+	if strings.Contains(got, "<link") || !strings.Contains(got, "<style>") {
+		t.Error("CSS is not inlined")
+	}
+}
+
+func TestExportMarkdownTemplate(t *testing.T) {
+	c := newFakeColog(t)
+	tmpl, err := ParseMarkdownTemplate(
+		"# Incident report\nSeverity: {{if .Summary.ErrorCount}}high{{else}}low{{end}}\nImages: {{join .Summary.TopImages \", \"}}",
+		"_Exported {{len .Containers}} containers_",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetMarkdownTemplate(tmpl)
+
+	got, err := c.ExportLogsAsMarkdown([]string{webID, workerID}, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got, "# Incident report\nSeverity: high\nImages: ") {
+		t.Errorf("export does not start with the custom header:\n%s", got)
+	}
+	if strings.Contains(got, "# Docker Container Logs Analysis") {
+		t.Error("custom header kept the default summary block")
+	}
+	if !strings.Contains(got, "## Container: web") || !strings.HasSuffix(got, "_Exported 2 containers_\n") {
+		t.Errorf("export lacks the container sections or the footer:\n%s", got)
+	}
+
+	// Sinks use the Colog's template too
+	var buf bytes.Buffer
+	sink, _ := NewFormatSink(&buf, "markdown")
+	if err := c.ExportToSink([]string{webID, workerID}, LogOptions{Tail: 100}, sink); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != got {
+		t.Errorf("markdown sink differs from the templated export:\n%s", buf.String())
+	}
+
+	if _, err := ParseMarkdownTemplate("{{.Summary", ""); err == nil {
+		t.Error("malformed header template accepted")
+	}
+}
+
+func TestExportMarkdownLineNumbers(t *testing.T) {
+	c := newFakeColog(t)
+	options := LogOptions{Tail: 100, LineNumbers: true}
+	got, err := c.ExportLogsAsMarkdown([]string{webID, workerID}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"1 | [2025-03-04 11:59:01] stdout: GET / 200\n",
+		"5 | [2025-03-04 11:59:05] stderr: ERROR connection refused\n",
+		"1 | [2025-03-04 11:59:00] stdout: job 42 done\n", // numbering restarts per container
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("markdown lacks %q:\n%s", line, got)
+		}
+	}
+
+	var buf bytes.Buffer
+	sink, _ := NewFormatSink(&buf, "markdown")
+	if err := c.ExportToSink([]string{webID, workerID}, options, sink); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != got {
+		t.Errorf("markdown sink ignores line numbers:\n%s", buf.String())
+	}
+}
+
+func TestMultipleContainerLogsKeepErrorsApart(t *testing.T) {
+	c := newFakeColog(t)
+	ids := []string{webID, "ghost"}
+
+	logs, errs, err := c.GetMultipleContainerLogs(context.Background(), ids, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := logs["ghost"]; ok || errs["ghost"] == nil || len(logs[webID]) != 5 || errs[webID] != nil {
+		t.Errorf("logs %v, errors %v; want web's logs and ghost's error kept apart", logs, errs)
+	}
+
+	output, err := c.ExportLogsForLLM(ids, LogOptions{Tail: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ghost := output.Containers[1]
+	if ghost.Container.ID != "ghost" || ghost.Error == "" || len(ghost.Logs) != 0 || output.Summary.TotalLogs != 5 {
+		t.Errorf("ghost exported as %+v, %d lines in total; want an error and no log lines", ghost, output.Summary.TotalLogs)
+	}
+	markdown, _ := c.ExportLogsAsMarkdown(ids, LogOptions{Tail: 100})
+	if !strings.Contains(markdown, "- **Error:** failed to get recent logs") || strings.Count(markdown, "### Logs") != 1 {
+		t.Errorf("markdown should note ghost's error outside the logs:\n%s", markdown)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	logs, errs, err = c.GetMultipleContainerLogs(ctx, ids, LogOptions{Tail: 100})
+	if !errors.Is(err, context.Canceled) || len(logs) != 0 || !errors.Is(errs[webID], context.Canceled) {
+		t.Errorf("after the deadline: logs %v, errors %v, err %v", logs, errs, err)
+	}
+}
@@ -0,0 +1,136 @@
+package sdk
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+// htmlReport is a self-contained page: all CSS is inline so the file still renders
+// when emailed or opened offline. Containers are collapsible <details> sections.
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Docker Container Logs Report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1 { font-size: 1.5em; }
+table.summary { border-collapse: collapse; margin-bottom: 1.5em; }
+table.summary th, table.summary td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; }
+table.summary th { background: #f6f8fa; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin-bottom: 1em; }
+summary { cursor: pointer; padding: 8px 12px; background: #f6f8fa; font-weight: 600; }
+summary .counts { font-weight: normal; color: #57606a; }
+ul.meta { margin: 8px 12px; padding-left: 1.2em; color: #57606a; }
+pre { margin: 0; padding: 8px 12px; overflow-x: auto; font-size: 12px; line-height: 1.5; }
+.line { display: block; white-space: pre-wrap; }
+.ts { color: #8c959f; }
+.error { background: #ffebe9; color: #a40e26; }
+.warn { background: #fff8c5; color: #7d4e00; }
+.empty { color: #8c959f; font-style: italic; }
+.health-healthy { color: #1a7f37; }
+.health-unhealthy { color: #cf222e; font-weight: 600; }
+.health-starting { color: #9a6700; }
+</style>
+</head>
+<body>
+<h1>Docker Container Logs Report</h1>
+<table class="summary">
+<tr><th>Generated</th><td>{{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}</td></tr>
+<tr><th>Containers</th><td>{{.Summary.TotalContainers}}</td></tr>
+<tr><th>Log entries</th><td>{{.Summary.TotalLogs}}</td></tr>
+<tr><th>Errors</th><td>{{.Summary.ErrorCount}}</td></tr>
+<tr><th>Warnings</th><td>{{.Summary.WarnCount}}</td></tr>
+{{- if .Summary.TopImages}}
+<tr><th>Top images</th><td>{{join .Summary.TopImages ", "}}</td></tr>
+{{- end}}
+{{- if not .Summary.TimeRange.Start.IsZero}}
+<tr><th>Time range</th><td>{{.Summary.TimeRange.Start.Format "2006-01-02 15:04:05"}} to {{.Summary.TimeRange.End.Format "2006-01-02 15:04:05"}}</td></tr>
+{{- end}}
+</table>
+{{range .Containers}}
+<details{{if .Errors}} open{{end}}>
+<summary>{{.Container.DisplayName}} <span class="counts">({{.Container.Image}}, {{len .Lines}} lines{{if .Errors}}, {{.Errors}} errors{{end}})</span></summary>
+<ul class="meta">
+<li>ID: {{.Container.ID}}</li>
+{{- if .Container.ImageID}}
+<li>Image ID: {{.Container.ImageID}}</li>
+{{- end}}
+{{- if .Container.ImageDigest}}
+<li>Image digest: {{.Container.ImageDigest}}</li>
+{{- end}}
+<li>Status: {{.Container.Status}}</li>
+{{- if .Container.Health}}
+<li>Health: <span class="health-{{.Container.Health}}">{{.Container.Health}}</span></li>
+{{- end}}
+</ul>
+<pre>
+{{- range .Lines}}<span class="line{{if .Class}} {{.Class}}{{end}}"><span class="ts">{{.Timestamp}}</span> {{.Message}}</span>{{else}}<span class="empty">{{with .Error}}Logs unavailable: {{.}}{{else}}No log entries{{end}}</span>{{end -}}
+</pre>
+</details>
+{{end}}
+</body>
+</html>
+`))
+
+// htmlContainer is one container section of the HTML report
+type htmlContainer struct {
+	Container ContainerInfo
+	Lines     []htmlLine
+	Errors    int
+	Error     string // why the logs couldn't be read
+}
+
+type htmlLine struct {
+	Timestamp string
+	Message   string
+	Class     string // "error", "warn" or "" to highlight the line by level
+}
+
+// ExportLogsAsHTML exports logs as a self-contained HTML report with the summary at
+// the top, one collapsible section per container and error lines highlighted
+func (c *Colog) ExportLogsAsHTML(containerIDs []string, options LogOptions) (string, error) {
+	output, err := c.ExportLogsForLLM(containerIDs, options)
+	if err != nil {
+		return "", err
+	}
+	return renderHTML(output)
+}
+
+func renderHTML(output *LogsOutput) (string, error) {
+	matcher := docker.NewLevelMatcher()
+	containers := make([]htmlContainer, 0, len(output.Containers))
+	for _, collection := range output.Containers {
+		section := htmlContainer{Container: collection.Container, Error: collection.Error}
+		for _, entry := range collection.Logs {
+			line := htmlLine{
+				Timestamp: entry.Timestamp.Format("2006-01-02 15:04:05"),
+				Message:   entry.Message,
+			}
+			switch matcher.MatchEntry(entry) {
+			case docker.LevelError:
+				line.Class = "error"
+				section.Errors++
+			case docker.LevelWarn:
+				line.Class = "warn"
+			}
+			section.Lines = append(section.Lines, line)
+		}
+		containers = append(containers, section)
+	}
+
+	var page strings.Builder
+	err := htmlReport.Execute(&page, struct {
+		*LogsOutput
+		Containers []htmlContainer
+	}{output, containers})
+	if err != nil {
+		return "", fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return page.String(), nil
+}
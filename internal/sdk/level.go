@@ -0,0 +1,52 @@
+package sdk
+
+import (
+	"fmt"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+// logFields names the JSON level/message/timestamp fields (see docker.NewLogFields)
+var logFields = docker.NewLogFields()
+
+// DetectLevel parses the level of a structured log message: JSON lines with a
+// level field, logfmt level=... pairs, or a leading "[ERROR]"/"WARN:" prefix.
+// ok is false when the message carries no recognizable level. The TUI colors
+// lines by the same rules (see docker.DetectLevel).
+func DetectLevel(message string) (level docker.LogLevel, ok bool) {
+	return docker.DetectLevel(message, logFields)
+}
+
+// DetectEntryLevel is DetectLevel for a log entry: syslog lines use their severity
+func DetectEntryLevel(entry docker.LogEntry) (level docker.LogLevel, ok bool) {
+	return docker.DetectEntryLevel(entry, logFields)
+}
+
+// filterByLevel keeps entries at or above minLevel. Entries without a detectable
+// level count as info, or are dropped when dropUnleveled is set.
+func filterByLevel(logs []docker.LogEntry, minLevel string, dropUnleveled bool) ([]docker.LogEntry, error) {
+	if minLevel == "" && !dropUnleveled {
+		return logs, nil
+	}
+
+	threshold := docker.LevelDebug
+	if minLevel != "" {
+		var ok bool
+		threshold, ok = docker.ParseLogLevel(minLevel)
+		if !ok {
+			return nil, fmt.Errorf("invalid min level %q (supported: debug, info, warn, error)", minLevel)
+		}
+	}
+
+	var filtered []docker.LogEntry
+	for _, entry := range logs {
+		level, ok := DetectEntryLevel(entry)
+		if !ok && dropUnleveled {
+			continue
+		}
+		if level >= threshold {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
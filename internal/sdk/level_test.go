@@ -0,0 +1,70 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+)
+
+func TestDetectLevel(t *testing.T) {
+	tests := []struct {
+		message string
+		want    docker.LogLevel
+		wantOK  bool
+	}{
+		{"[ERROR] connection refused", docker.LevelError, true},
+		{"WARN: disk almost full", docker.LevelWarn, true},
+		{"2025-01-01T10:00:00Z INFO server started", docker.LevelInfo, true},
+		{"<debug> cache hit", docker.LevelDebug, true},
+		{`time=2025-01-01 level=warn msg="slow query"`, docker.LevelWarn, true},
+		{`ts=1 lvl="error" msg=boom`, docker.LevelError, true},
+		{`{"level":"error","msg":"boom"}`, docker.LevelError, true},
+		{`{"severity":"WARNING","message":"retrying"}`, docker.LevelWarn, true},
+		{"[FATAL] out of memory", docker.LevelError, true},
+		{"GET /health 200", docker.LevelInfo, false},
+		{"an error happened somewhere", docker.LevelInfo, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := DetectLevel(tt.message)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("DetectLevel(%q) = %v, %v; want %v, %v", tt.message, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFilterByLevel(t *testing.T) {
+	logs := logsWith("[ERROR] boom", "level=warn slow", "plain line", "[DEBUG] noisy")
+
+	tests := []struct {
+		name          string
+		minLevel      string
+		dropUnleveled bool
+		want          int
+	}{
+		{"no filter", "", false, 4},
+		{"error only", "error", false, 1},
+		{"warn and above", "warn", false, 2},
+		{"unleveled counts as info", "info", false, 3},
+		{"drop unleveled", "info", true, 2},
+		{"drop unleveled without threshold", "", true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := filterByLevel(logs, tt.minLevel, tt.dropUnleveled)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(filtered) != tt.want {
+				t.Errorf("got %d entries, want %d", len(filtered), tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterByLevelRejectsUnknownLevel(t *testing.T) {
+	if _, err := filterByLevel(nil, "loud", false); err == nil {
+		t.Error("expected an error for an unknown level")
+	}
+}
@@ -3,8 +3,12 @@ package sdk
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"path"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -13,23 +17,53 @@ import (
 
 // Colog provides programmatic access to Docker container logs and information
 type Colog struct {
-	dockerService *docker.DockerService
+	dockerService dockerBackend
 	ctx           context.Context
+	ownsService   bool // whether Close closes dockerService
+
+	markdownTemplate *MarkdownTemplate // custom markdown header/footer; nil = default
+}
+
+// dockerBackend is the part of docker.DockerService the SDK uses, so tests can
+// substitute a fake
+type dockerBackend interface {
+	Close() error
+	ListContainerSummaries(ctx context.Context, all bool) ([]docker.ContainerSummary, error)
+	GetLogs(ctx context.Context, containerID string, opts docker.LogStreamOptions) ([]docker.LogEntry, error)
+	docker.LogSource
+	GetContainerStats(ctx context.Context, containerID string) (*docker.ContainerStats, error)
+	ImageDigest(ctx context.Context, containerID, image, imageID string) (string, error)
+	Health(ctx context.Context, containerID string) (string, error)
 }
 
+// timeNow stamps exports; tests pin it
+var timeNow = time.Now
+
 // ContainerInfo represents detailed container information
 type ContainerInfo struct {
-	ID        string            `json:"id"`
-	Name      string            `json:"name"`
-	Image     string            `json:"image"`
-	ImageID   string            `json:"image_id"`
-	Status    string            `json:"status"`
-	State     string            `json:"state"`
-	Created   time.Time         `json:"created"`
-	Labels    map[string]string `json:"labels"`
-	Ports     []PortMapping     `json:"ports"`
-	Mounts    []MountInfo       `json:"mounts"`
-	NetworkID string            `json:"network_id"`
+	ID          string            `json:"id"`
+	Name        string            `json:"name"`
+	Image       string            `json:"image"`
+	ImageID     string            `json:"image_id"`
+	ImageDigest string            `json:"image_digest,omitempty"` // repo digest, filled in exports
+	Status      string            `json:"status"`
+	State       string            `json:"state"`
+	Health      string            `json:"health,omitempty"` // healthy, unhealthy or starting; empty without a healthcheck
+	Created     time.Time         `json:"created"`
+	Labels      map[string]string `json:"labels"`
+	Ports       []PortMapping     `json:"ports"`
+	Mounts      []MountInfo       `json:"mounts"`
+	NetworkID   string            `json:"network_id"`
+	Host        string            `json:"host,omitempty"` // Docker host label, set with COLOG_DOCKER_HOSTS
+}
+
+// DisplayName is the container name, prefixed with its host label ("staging/web")
+// when several Docker hosts are watched
+func (c ContainerInfo) DisplayName() string {
+	if c.Host == "" {
+		return c.Name
+	}
+	return c.Host + "/" + c.Name
 }
 
 // PortMapping represents container port information
@@ -56,6 +90,15 @@ type LogOptions struct {
 	Since      time.Time `json:"since"`
 	Until      time.Time `json:"until"`
 	Timestamps bool      `json:"timestamps"`
+	// MinLevel drops entries below this level ("debug", "info", "warn", "error").
+	// Levels are detected from JSON, logfmt and "[ERROR]"-style prefixes; entries
+	// without one count as info.
+	MinLevel string `json:"min_level,omitempty"`
+	// DropUnleveled drops entries whose level can't be detected
+	DropUnleveled bool `json:"drop_unleveled,omitempty"`
+	// LineNumbers numbers each container's lines in markdown exports and labels
+	// them with their stream, so analyses can refer to "line 42"
+	LineNumbers bool `json:"line_numbers,omitempty"`
 }
 
 // ContainerFilter defines criteria for filtering containers
@@ -66,6 +109,7 @@ type ContainerFilter struct {
 	Status   string            `json:"status"`
 	Labels   map[string]string `json:"labels"`
 	Networks []string          `json:"networks"`
+	Host     string            `json:"host,omitempty"` // exact Docker host label
 }
 
 // LogsOutput represents formatted logs for LLM consumption
@@ -81,6 +125,8 @@ type ContainerLogCollection struct {
 	LogCount  int           `json:"log_count"`
 	Logs      []docker.LogEntry    `json:"logs"`
 	TimeRange TimeRange     `json:"time_range"`
+	// Error says why the container's logs couldn't be read; Logs is empty then
+	Error string `json:"error,omitempty"`
 }
 
 // TimeRange represents the time span of logs
@@ -96,23 +142,41 @@ type LogsSummary struct {
 	TimeRange       TimeRange `json:"time_range"`
 	TopImages       []string  `json:"top_images"`
 	ErrorCount      int       `json:"error_count"`
+	WarnCount       int       `json:"warn_count"`
 }
 
 // NewColog creates a new Colog SDK instance
 func NewColog(ctx context.Context) (*Colog, error) {
 	dockerService, err := docker.NewDockerService()
 	if err != nil {
+		if err = dockerError(err); !errors.Is(err, ErrPermissionDenied) && !errors.Is(err, ErrDockerUnavailable) {
+			err = fmt.Errorf("%w: %w", ErrDockerUnavailable, err)
+		}
 		return nil, fmt.Errorf("failed to initialize Docker service: %w", err)
 	}
 
 	return &Colog{
 		dockerService: dockerService,
 		ctx:           ctx,
+		ownsService:   true,
 	}, nil
 }
 
+// NewCologWithDockerService creates a Colog over an already connected Docker
+// service, skipping the endpoint discovery NewColog does. The service stays owned by
+// the caller: Close leaves it open.
+func NewCologWithDockerService(ctx context.Context, dockerService *docker.DockerService) *Colog {
+	return &Colog{
+		dockerService: dockerService,
+		ctx:           ctx,
+	}
+}
+
 // Close releases Colog resources
 func (c *Colog) Close() error {
+	if !c.ownsService {
+		return nil
+	}
 	return c.dockerService.Close()
 }
 
@@ -139,23 +203,71 @@ func (c *Colog) GetContainerByName(name string) (*ContainerInfo, error) {
 		}
 	}
 
-	return nil, fmt.Errorf("container with name '%s' not found", name)
+	return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, name)
 }
 
-// GetContainerByID finds a container by ID (full or short)
-func (c *Colog) GetContainerByID(id string) (*ContainerInfo, error) {
+// ResolveContainers finds the containers refs refer to, each an ID (full or short),
+// a name or a name glob such as "web-*", in the order given and without
+// duplicates. A ref matching no container is ErrContainerNotFound, and a short ID
+// matching several is ErrAmbiguousID.
+func (c *Colog) ResolveContainers(refs []string) ([]ContainerInfo, error) {
 	containers, err := c.ListAllContainers()
 	if err != nil {
 		return nil, err
 	}
 
-	for _, container := range containers {
-		if container.ID == id || strings.HasPrefix(container.ID, id) {
-			return &container, nil
+	var resolved []ContainerInfo
+	seen := make(map[string]bool)
+	add := func(container ContainerInfo) {
+		if !seen[container.ID] {
+			seen[container.ID] = true
+			resolved = append(resolved, container)
+		}
+	}
+	for _, ref := range refs {
+		if !strings.ContainsAny(ref, "*?[") {
+			container, exists := containerByName(containers, ref)
+			if !exists {
+				if container, err = containerByID(containers, ref); err != nil {
+					return nil, err
+				}
+			}
+			add(container)
+			continue
+		}
+
+		matched := false
+		for _, container := range containers {
+			nameMatch, err := path.Match(ref, container.Name)
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s': %w", ref, err)
+			}
+			displayMatch, _ := path.Match(ref, container.DisplayName())
+			if nameMatch || displayMatch {
+				add(container)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("%w: %s", ErrContainerNotFound, ref)
 		}
 	}
+	return resolved, nil
+}
+
+// GetContainerByID finds a container by ID (full or short). A short ID several
+// containers' IDs start with is ErrAmbiguousID.
+func (c *Colog) GetContainerByID(id string) (*ContainerInfo, error) {
+	containers, err := c.ListAllContainers()
+	if err != nil {
+		return nil, err
+	}
 
-	return nil, fmt.Errorf("container with ID '%s' not found", id)
+	container, err := containerByID(containers, id)
+	if err != nil {
+		return nil, err
+	}
+	return &container, nil
 }
 
 // FilterContainers filters containers based on criteria
@@ -185,50 +297,60 @@ func (c *Colog) GetContainersByImageID(imageID string) ([]ContainerInfo, error)
 	return c.FilterContainers(ContainerFilter{ImageID: imageID})
 }
 
+// GetContainerStats takes a one-shot CPU, memory and network I/O sample of a container
+func (c *Colog) GetContainerStats(containerID string) (*docker.ContainerStats, error) {
+	return c.dockerService.GetContainerStats(c.ctx, containerID)
+}
+
 // GetContainerLogs retrieves logs from a specific container
 func (c *Colog) GetContainerLogs(containerID string, options LogOptions) ([]docker.LogEntry, error) {
+	return c.containerLogs(c.ctx, containerID, options)
+}
+
+// containerLogs is GetContainerLogs reading under ctx
+func (c *Colog) containerLogs(ctx context.Context, containerID string, options LogOptions) ([]docker.LogEntry, error) {
 	if options.Follow {
 		// For streaming logs, use the channel-based approach
-		return c.getStreamingLogs(containerID, options)
+		return c.getStreamingLogs(ctx, containerID, options)
 	}
 
-	// For non-streaming logs, use the more reliable GetRecentLogs method
+	// For non-streaming logs, read once; Docker applies the tail and time window
 	tail := options.Tail
 	if tail <= 0 {
 		tail = 100 // Default to 100 if not specified
 	}
 
-	logs, err := c.dockerService.GetRecentLogs(c.ctx, containerID, tail)
+	logs, err := c.dockerService.GetLogs(ctx, containerID, docker.LogStreamOptions{
+		Tail:       tail,
+		Since:      options.Since,
+		Until:      options.Until,
+		Timestamps: true,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get recent logs: %w", err)
+		return nil, fmt.Errorf("failed to get recent logs: %w", dockerError(err))
 	}
 
-	// Apply time-based filters
-	var filteredLogs []docker.LogEntry
-	for _, entry := range logs {
-		if !options.Since.IsZero() && entry.Timestamp.Before(options.Since) {
-			continue
-		}
-		if !options.Until.IsZero() && entry.Timestamp.After(options.Until) {
-			continue
-		}
-		filteredLogs = append(filteredLogs, entry)
-	}
-
-	return filteredLogs, nil
+	return filterByLevel(logs, options.MinLevel, options.DropUnleveled)
 }
 
 // getStreamingLogs handles the streaming/following case
-func (c *Colog) getStreamingLogs(containerID string, options LogOptions) ([]docker.LogEntry, error) {
-	logCh := make(chan docker.LogEntry, 1000)
-	logs := make([]docker.LogEntry, 0)
+func (c *Colog) getStreamingLogs(ctx context.Context, containerID string, options LogOptions) ([]docker.LogEntry, error) {
+	if _, err := filterByLevel(nil, options.MinLevel, false); err != nil {
+		return nil, err
+	}
 
-	// Create a context for log streaming
-	ctx := c.ctx
+	logs := make([]docker.LogEntry, 0)
 
-	err := c.dockerService.StreamLogs(ctx, containerID, logCh)
+	// Docker applies the tail and time window, so only the level filter is left here
+	opts := docker.DefaultLogStreamOptions()
+	opts.Since = options.Since
+	opts.Until = options.Until
+	if options.Tail > 0 {
+		opts.Tail = options.Tail
+	}
+	logCh, err := c.dockerService.Stream(ctx, containerID, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to stream logs: %w", err)
+		return nil, fmt.Errorf("failed to stream logs: %w", dockerError(err))
 	}
 
 	// Collect logs
@@ -241,11 +363,7 @@ func (c *Colog) getStreamingLogs(containerID string, options LogOptions) ([]dock
 				return logs, nil
 			}
 
-			// Apply filters
-			if !options.Since.IsZero() && entry.Timestamp.Before(options.Since) {
-				continue
-			}
-			if !options.Until.IsZero() && entry.Timestamp.After(options.Until) {
+			if kept, _ := filterByLevel([]docker.LogEntry{entry}, options.MinLevel, options.DropUnleveled); len(kept) == 0 {
 				continue
 			}
 
@@ -263,31 +381,43 @@ func (c *Colog) getStreamingLogs(containerID string, options LogOptions) ([]dock
 	}
 }
 
-// GetMultipleContainerLogs retrieves logs from multiple containers
-func (c *Colog) GetMultipleContainerLogs(containerIDs []string, options LogOptions) (map[string][]docker.LogEntry, error) {
-	result := make(map[string][]docker.LogEntry)
-	
+// ProbeStream tells a wedged follow stream from a quiet container, for streams that
+// delivered nothing for a while: it reports docker.StreamStalled when the container
+// logged lines after lastSeen (the last line the stream delivered), and
+// docker.StreamQuiet when it didn't
+func (c *Colog) ProbeStream(containerID string, lastSeen time.Time) (docker.StreamState, error) {
+	return docker.ProbeStall(c.ctx, c.dockerService, containerID, lastSeen)
+}
+
+// GetMultipleContainerLogs retrieves logs from multiple containers, one after another,
+// all within ctx: its deadline bounds the whole batch, not each container. Containers
+// whose logs can't be read are left out of the logs and get their error in errs
+// instead. When ctx ends, the containers not read yet fail with its error, which is
+// also returned alongside the logs read so far.
+func (c *Colog) GetMultipleContainerLogs(ctx context.Context, containerIDs []string, options LogOptions) (logs map[string][]docker.LogEntry, errs map[string]error, err error) {
+	logs = make(map[string][]docker.LogEntry)
+	errs = make(map[string]error)
+
 	for _, containerID := range containerIDs {
-		logs, err := c.GetContainerLogs(containerID, options)
+		if err := ctx.Err(); err != nil {
+			errs[containerID] = err
+			continue
+		}
+		entries, err := c.containerLogs(ctx, containerID, options)
 		if err != nil {
-			// Log error but continue with other containers
-			result[containerID] = []docker.LogEntry{{
-				ContainerID: containerID,
-				Timestamp:   time.Now(),
-				Message:     fmt.Sprintf("Error retrieving logs: %v", err),
-				Stream:      "error",
-			}}
+			errs[containerID] = err
 			continue
 		}
-		result[containerID] = logs
+		logs[containerID] = entries
 	}
 
-	return result, nil
+	return logs, errs, ctx.Err()
 }
 
-// ExportLogsForLLM formats logs for LLM consumption
+// ExportLogsForLLM formats logs for LLM consumption. Containers whose logs can't be
+// read are exported without logs, with the reason in their Error.
 func (c *Colog) ExportLogsForLLM(containerIDs []string, options LogOptions) (*LogsOutput, error) {
-	logsMap, err := c.GetMultipleContainerLogs(containerIDs, options)
+	logsMap, errs, err := c.GetMultipleContainerLogs(c.ctx, containerIDs, options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve logs: %w", err)
 	}
@@ -296,57 +426,92 @@ func (c *Colog) ExportLogsForLLM(containerIDs []string, options LogOptions) (*Lo
 	if err != nil {
 		return nil, fmt.Errorf("failed to list containers: %w", err)
 	}
+	c.attachExportDetails(containers, containerIDs)
 
-	// Create container lookup map
-	containerLookup := make(map[string]ContainerInfo)
-	for _, container := range containers {
-		containerLookup[container.ID] = container
+	return buildLogsOutput(logsMap, errs, containers), nil
+}
+
+// buildLogsOutput attaches container metadata to collected logs and computes the summary.
+// Containers in errs get a collection with the error instead of logs. Map keys may be
+// short IDs, full IDs or names.
+func buildLogsOutput(logsMap map[string][]docker.LogEntry, errs map[string]error, containers []ContainerInfo) *LogsOutput {
+	// Map order is random; sort so exports are stable
+	containerIDs := make([]string, 0, len(logsMap)+len(errs))
+	for containerID := range logsMap {
+		containerIDs = append(containerIDs, containerID)
+	}
+	for containerID := range errs {
+		if _, read := logsMap[containerID]; !read {
+			containerIDs = append(containerIDs, containerID)
+		}
+	}
+	sort.Strings(containerIDs)
+
+	collections := make([]ContainerLogCollection, 0, len(containerIDs))
+	for _, containerID := range containerIDs {
+		collection := newLogCollection(resolveContainer(containers, containerID), logsMap[containerID])
+		if err := errs[containerID]; err != nil {
+			collection.Error = err.Error()
+		}
+		collections = append(collections, collection)
 	}
+	return newLogsOutput(collections)
+}
 
+// resolveContainer finds the container ref refers to, or a minimal placeholder
+func resolveContainer(containers []ContainerInfo, ref string) ContainerInfo {
+	container, exists := findContainer(containers, ref)
+	if !exists {
+		// Create minimal container info if not found
+		container = ContainerInfo{
+			ID:   ref,
+			Name: "unknown",
+		}
+	}
+	return container
+}
+
+// newLogCollection bundles a container's logs with its metadata and time range
+func newLogCollection(container ContainerInfo, logs []docker.LogEntry) ContainerLogCollection {
+	var timeRange TimeRange
+	if len(logs) > 0 {
+		timeRange.Start = logs[0].Timestamp
+		timeRange.End = logs[len(logs)-1].Timestamp
+	}
+	return ContainerLogCollection{
+		Container: container,
+		LogCount:  len(logs),
+		Logs:      logs,
+		TimeRange: timeRange,
+	}
+}
+
+// newLogsOutput wraps collections in an export and computes its summary
+func newLogsOutput(collections []ContainerLogCollection) *LogsOutput {
 	output := &LogsOutput{
-		GeneratedAt: time.Now(),
-		Containers:  make([]ContainerLogCollection, 0),
+		GeneratedAt: timeNow(),
+		Containers:  collections,
 	}
 
 	var allLogs []docker.LogEntry
 	imageCount := make(map[string]int)
 	errorCount := 0
+	warnCount := 0
+	matcher := docker.NewLevelMatcher()
 
-	for containerID, logs := range logsMap {
-		container, exists := containerLookup[containerID]
-		if !exists {
-			// Create minimal container info if not found
-			container = ContainerInfo{
-				ID:   containerID,
-				Name: "unknown",
-			}
-		}
-
-		var timeRange TimeRange
-		if len(logs) > 0 {
-			timeRange.Start = logs[0].Timestamp
-			timeRange.End = logs[len(logs)-1].Timestamp
-		}
-
-		// Count errors in logs
-		for _, log := range logs {
-			if strings.Contains(strings.ToLower(log.Message), "error") ||
-				strings.Contains(strings.ToLower(log.Message), "exception") ||
-				strings.Contains(strings.ToLower(log.Message), "fail") {
+	for _, collection := range collections {
+		// Count errors and warnings, preferring a structured level over keywords
+		for _, log := range collection.Logs {
+			switch matcher.MatchEntry(log) {
+			case docker.LevelError:
 				errorCount++
+			case docker.LevelWarn:
+				warnCount++
 			}
 		}
 
-		collection := ContainerLogCollection{
-			Container: container,
-			LogCount:  len(logs),
-			Logs:      logs,
-			TimeRange: timeRange,
-		}
-
-		output.Containers = append(output.Containers, collection)
-		allLogs = append(allLogs, logs...)
-		imageCount[container.Image]++
+		allLogs = append(allLogs, collection.Logs...)
+		imageCount[collection.Container.Image]++
 	}
 
 	// Generate summary
@@ -370,7 +535,10 @@ func (c *Colog) ExportLogsForLLM(containerIDs []string, options LogOptions) (*Lo
 		imageInfos = append(imageInfos, imageInfo{image, count})
 	}
 	sort.Slice(imageInfos, func(i, j int) bool {
-		return imageInfos[i].count > imageInfos[j].count
+		if imageInfos[i].count != imageInfos[j].count {
+			return imageInfos[i].count > imageInfos[j].count
+		}
+		return imageInfos[i].name < imageInfos[j].name
 	})
 
 	topImages := make([]string, 0)
@@ -387,9 +555,10 @@ func (c *Colog) ExportLogsForLLM(containerIDs []string, options LogOptions) (*Lo
 		TimeRange:       overallTimeRange,
 		TopImages:       topImages,
 		ErrorCount:      errorCount,
+		WarnCount:       warnCount,
 	}
 
-	return output, nil
+	return output
 }
 
 // ExportLogsAsJSON exports logs as JSON string
@@ -398,7 +567,10 @@ func (c *Colog) ExportLogsAsJSON(containerIDs []string, options LogOptions) (str
 	if err != nil {
 		return "", err
 	}
+	return renderJSON(output)
+}
 
+func renderJSON(output *LogsOutput) (string, error) {
 	jsonData, err := json.MarshalIndent(output, "", "  ")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal JSON: %w", err)
@@ -407,20 +579,95 @@ func (c *Colog) ExportLogsAsJSON(containerIDs []string, options LogOptions) (str
 	return string(jsonData), nil
 }
 
+// JSONLRecord is one log line of a JSON Lines export, with its container's metadata
+type JSONLRecord struct {
+	ContainerID   string    `json:"container_id"`
+	ContainerName string    `json:"container_name"`
+	Host          string    `json:"host,omitempty"`
+	Image         string    `json:"image"`
+	Timestamp     time.Time `json:"timestamp"`
+	Message       string    `json:"message"`
+	Stream        string    `json:"stream"`
+}
+
+// ExportLogsAsJSONL exports logs as JSON Lines: one JSON object per log line
+func (c *Colog) ExportLogsAsJSONL(containerIDs []string, options LogOptions) (string, error) {
+	var output strings.Builder
+	if err := c.WriteLogsAsJSONL(&output, containerIDs, options); err != nil {
+		return "", err
+	}
+	return output.String(), nil
+}
+
+// WriteLogsAsJSONL writes logs as JSON Lines to w one container at a time, so only a
+// single container's logs are held in memory
+func (c *Colog) WriteLogsAsJSONL(w io.Writer, containerIDs []string, options LogOptions) error {
+	return c.ExportToSink(containerIDs, options, NewJSONLSink(w))
+}
+
+func jsonlRecord(container ContainerInfo, entry docker.LogEntry) JSONLRecord {
+	return JSONLRecord{
+		ContainerID:   container.ID,
+		ContainerName: container.Name,
+		Host:          container.Host,
+		Image:         container.Image,
+		Timestamp:     entry.Timestamp,
+		Message:       entry.Message,
+		Stream:        entry.Stream,
+	}
+}
+
 // ExportLogsAsMarkdown exports logs as markdown string for LLM consumption
 func (c *Colog) ExportLogsAsMarkdown(containerIDs []string, options LogOptions) (string, error) {
 	output, err := c.ExportLogsForLLM(containerIDs, options)
 	if err != nil {
 		return "", err
 	}
+	return renderMarkdown(output, markdownOptions{template: c.markdownTemplate, lineNumbers: options.LineNumbers})
+}
+
+// markdownOptions are the settings of a markdown export beyond its data
+type markdownOptions struct {
+	template    *MarkdownTemplate // custom header and footer; nil = default
+	lineNumbers bool              // number lines and label their stream
+}
 
+// renderMarkdown renders the export as markdown, with the template's header and
+// footer when set
+func renderMarkdown(output *LogsOutput, opts markdownOptions) (string, error) {
 	var md strings.Builder
-	
+	tmpl := opts.template
+
+	if tmpl != nil && tmpl.Header != nil {
+		header, err := executeTemplate(tmpl.Header, output)
+		if err != nil {
+			return "", err
+		}
+		md.WriteString(header)
+	} else {
+		writeMarkdownSummary(&md, output)
+	}
+	md.WriteString("\n---\n\n")
+	writeMarkdownContainers(&md, output, opts.lineNumbers)
+
+	if tmpl != nil && tmpl.Footer != nil {
+		footer, err := executeTemplate(tmpl.Footer, output)
+		if err != nil {
+			return "", err
+		}
+		md.WriteString(footer)
+	}
+	return md.String(), nil
+}
+
+// writeMarkdownSummary writes the default header: the title and the export summary
+func writeMarkdownSummary(md *strings.Builder, output *LogsOutput) {
 	md.WriteString("# Docker Container Logs Analysis\n\n")
 	md.WriteString(fmt.Sprintf("**Generated:** %s\n", output.GeneratedAt.Format("2006-01-02 15:04:05 MST")))
 	md.WriteString(fmt.Sprintf("**Total Containers:** %d\n", output.Summary.TotalContainers))
 	md.WriteString(fmt.Sprintf("**Total Log Entries:** %d\n", output.Summary.TotalLogs))
 	md.WriteString(fmt.Sprintf("**Error Count:** %d\n", output.Summary.ErrorCount))
+	md.WriteString(fmt.Sprintf("**Warning Count:** %d\n", output.Summary.WarnCount))
 	
 	if len(output.Summary.TopImages) > 0 {
 		md.WriteString(fmt.Sprintf("**Top Images:** %s\n", strings.Join(output.Summary.TopImages, ", ")))
@@ -431,15 +678,34 @@ func (c *Colog) ExportLogsAsMarkdown(containerIDs []string, options LogOptions)
 			output.Summary.TimeRange.Start.Format("2006-01-02 15:04:05"),
 			output.Summary.TimeRange.End.Format("2006-01-02 15:04:05")))
 	}
-	
-	md.WriteString("\n---\n\n")
+}
 
+// writeMarkdownContainers writes a section with metadata and logs per container.
+// Numbered lines start at 1 in each container and carry their stream:
+// "  42 | [2025-03-04 11:59:01] stderr: connection refused".
+func writeMarkdownContainers(md *strings.Builder, output *LogsOutput, lineNumbers bool) {
 	for _, collection := range output.Containers {
-		md.WriteString(fmt.Sprintf("## Container: %s\n\n", collection.Container.Name))
+		md.WriteString(fmt.Sprintf("## Container: %s\n\n", collection.Container.DisplayName()))
 		md.WriteString(fmt.Sprintf("- **ID:** %s\n", collection.Container.ID))
+		if collection.Container.Host != "" {
+			md.WriteString(fmt.Sprintf("- **Host:** %s\n", collection.Container.Host))
+		}
 		md.WriteString(fmt.Sprintf("- **Image:** %s\n", collection.Container.Image))
+		if collection.Container.ImageID != "" {
+			md.WriteString(fmt.Sprintf("- **Image ID:** %s\n", collection.Container.ImageID))
+		}
+		if collection.Container.ImageDigest != "" {
+			md.WriteString(fmt.Sprintf("- **Image Digest:** %s\n", collection.Container.ImageDigest))
+		}
 		md.WriteString(fmt.Sprintf("- **Status:** %s\n", collection.Container.Status))
+		if collection.Container.Health != "" {
+			md.WriteString(fmt.Sprintf("- **Health:** %s\n", collection.Container.Health))
+		}
 		md.WriteString(fmt.Sprintf("- **Log Entries:** %d\n", collection.LogCount))
+		if collection.Error != "" {
+			md.WriteString(fmt.Sprintf("- **Error:** %s\n\n", collection.Error))
+			continue
+		}
 		
 		if !collection.TimeRange.Start.IsZero() {
 			md.WriteString(fmt.Sprintf("- **Log Time Range:** %s to %s\n", 
@@ -448,38 +714,152 @@ func (c *Colog) ExportLogsAsMarkdown(containerIDs []string, options LogOptions)
 		}
 		
 		md.WriteString("\n### Logs\n\n```\n")
-		for _, log := range collection.Logs {
+		numberWidth := len(strconv.Itoa(len(collection.Logs)))
+		for i, log := range collection.Logs {
 			timestamp := log.Timestamp.Format("2006-01-02 15:04:05")
+			if lineNumbers {
+				md.WriteString(fmt.Sprintf("%*d | [%s] %s: %s\n", numberWidth, i+1, timestamp, log.Stream, log.Message))
+				continue
+			}
 			md.WriteString(fmt.Sprintf("[%s] %s\n", timestamp, log.Message))
 		}
 		md.WriteString("```\n\n")
 	}
-
-	return md.String(), nil
 }
 
 // Helper methods
 
+// attachExportDetails fills in ImageDigest and Health for the containers refs refer
+// to, so an export records exactly which image version produced the logs and how
+// the container was doing. Lookup failures leave them empty.
+func (c *Colog) attachExportDetails(containers []ContainerInfo, refs []string) {
+	digests := make(map[string]string) // image ID → digest, shared by its containers
+	for _, ref := range refs {
+		for i := range containers {
+			container := &containers[i]
+			if !matchesContainerID(container.ID, ref) && container.Name != ref {
+				continue
+			}
+			digest, seen := digests[container.ImageID]
+			if !seen {
+				digest, _ = c.dockerService.ImageDigest(c.ctx, container.ID, container.Image, container.ImageID)
+				digests[container.ImageID] = digest
+			}
+			container.ImageDigest = digest
+			c.attachHealth(container)
+			break
+		}
+	}
+}
+
 func (c *Colog) listContainers(all bool) ([]ContainerInfo, error) {
-	// This is a simplified version - in a full implementation, you'd need to expand
-	// the DockerService to provide detailed container information
-	containers, err := c.dockerService.ListRunningContainers(c.ctx)
+	summaries, err := c.dockerService.ListContainerSummaries(c.ctx, all)
 	if err != nil {
-		return nil, err
+		return nil, dockerError(err)
 	}
 
 	var result []ContainerInfo
+	for _, summary := range summaries {
+		result = append(result, containerInfoFromSummary(summary))
+	}
+
+	return result, nil
+}
+
+// AttachHealth fills in Health for the running containers. Listings leave it empty,
+// since Docker's list doesn't carry it and each lookup is a round trip.
+func (c *Colog) AttachHealth(containers []ContainerInfo) {
+	for i := range containers {
+		c.attachHealth(&containers[i])
+	}
+}
+
+// attachHealth looks up a running container's health status; failures leave it empty
+func (c *Colog) attachHealth(container *ContainerInfo) {
+	if container.State == "running" {
+		container.Health, _ = c.dockerService.Health(c.ctx, container.ID)
+	}
+}
+
+// containerInfoFromSummary maps a Docker container list entry to ContainerInfo
+func containerInfoFromSummary(summary docker.ContainerSummary) ContainerInfo {
+	info := ContainerInfo{
+		ID:      summary.ID,
+		Name:    docker.SummaryName(summary.Summary),
+		Host:    summary.Host,
+		Image:   summary.Image,
+		ImageID: summary.ImageID,
+		Status:  summary.Status,
+		State:   string(summary.State),
+		Created: time.Unix(summary.Created, 0),
+		Labels:  summary.Labels,
+		Ports:   []PortMapping{},
+		Mounts:  []MountInfo{},
+	}
+	if info.Labels == nil {
+		info.Labels = map[string]string{}
+	}
+
+	for _, port := range summary.Ports {
+		info.Ports = append(info.Ports, PortMapping{
+			ContainerPort: int(port.PrivatePort),
+			HostPort:      int(port.PublicPort),
+			Type:          port.Type,
+			HostIP:        port.IP,
+		})
+	}
+	for _, mount := range summary.Mounts {
+		info.Mounts = append(info.Mounts, MountInfo{
+			Type:        string(mount.Type),
+			Source:      mount.Source,
+			Destination: mount.Destination,
+			Mode:        mount.Mode,
+			RW:          mount.RW,
+		})
+	}
+
+	// With several networks, report the first by name so the choice is stable
+	if summary.NetworkSettings != nil && len(summary.NetworkSettings.Networks) > 0 {
+		names := make([]string, 0, len(summary.NetworkSettings.Networks))
+		for name := range summary.NetworkSettings.Networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if endpoint := summary.NetworkSettings.Networks[names[0]]; endpoint != nil {
+			info.NetworkID = endpoint.NetworkID
+		}
+	}
+
+	return info
+}
+
+// matchesContainerID reports whether ref refers to the container with the given ID.
+// Either side may be a short (12-char) or full ID, so matching is by prefix.
+func matchesContainerID(containerID, ref string) bool {
+	if ref == "" || containerID == "" {
+		return false
+	}
+	return strings.HasPrefix(containerID, ref) || strings.HasPrefix(ref, containerID)
+}
+
+// containerByName looks a container up by name
+func containerByName(containers []ContainerInfo, name string) (ContainerInfo, bool) {
 	for _, container := range containers {
-		info := ContainerInfo{
-			ID:     container.ID,
-			Name:   container.Name,
-			Image:  container.Image,
-			Status: container.Status,
+		if container.Name == name {
+			return container, true
 		}
-		result = append(result, info)
 	}
+	return ContainerInfo{}, false
+}
 
-	return result, nil
+// findContainer looks a container up by full ID, short ID or name
+func findContainer(containers []ContainerInfo, ref string) (ContainerInfo, bool) {
+	for _, container := range containers {
+		if matchesContainerID(container.ID, ref) || container.Name == ref {
+			return container, true
+		}
+	}
+	return ContainerInfo{}, false
 }
 
 func (c *Colog) matchesFilter(container ContainerInfo, filter ContainerFilter) bool {
@@ -495,6 +875,9 @@ func (c *Colog) matchesFilter(container ContainerInfo, filter ContainerFilter) b
 	if filter.Status != "" && !strings.Contains(container.Status, filter.Status) {
 		return false
 	}
+	if filter.Host != "" && container.Host != filter.Host {
+		return false
+	}
 	
 	// Label matching
 	for key, value := range filter.Labels {
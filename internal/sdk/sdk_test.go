@@ -0,0 +1,220 @@
+package sdk
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/berkantay/colog/v2/internal/docker"
+	containertypes "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+)
+
+const fullID = "4f66ad9a0b2e1c3d5e7f9a1b3c5d7e9f0a2b4c6d8e0f1a3b5c7d9e1f3a5b7c9d"
+
+func TestMatchesContainerID(t *testing.T) {
+	tests := []struct {
+		name        string
+		containerID string
+		ref         string
+		want        bool
+	}{
+		{"full matches full", fullID, fullID, true},
+		{"short ref matches full ID", fullID, fullID[:12], true},
+		{"full ref matches stored short ID", fullID[:12], fullID, true},
+		{"partial prefix matches", fullID, fullID[:5], true},
+		{"different ID", fullID, "deadbeef", false},
+		{"empty ref never matches", fullID, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesContainerID(tt.containerID, tt.ref); got != tt.want {
+				t.Errorf("matchesContainerID(%q, %q) = %v, want %v", tt.containerID, tt.ref, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildLogsOutputAttachesMetadataForShortAndFullIDs(t *testing.T) {
+	containers := []ContainerInfo{
+		{ID: fullID, Name: "web", Image: "nginx:latest"},
+	}
+	entry := docker.LogEntry{ContainerID: fullID, Timestamp: time.Now(), Message: "started"}
+
+	for _, ref := range []string{fullID, fullID[:12], "web"} {
+		output := buildLogsOutput(map[string][]docker.LogEntry{ref: {entry}}, nil, containers)
+
+		if len(output.Containers) != 1 {
+			t.Fatalf("ref %q: got %d collections, want 1", ref, len(output.Containers))
+		}
+		got := output.Containers[0].Container
+		if got.Name != "web" || got.Image != "nginx:latest" || got.ID != fullID {
+			t.Errorf("ref %q: got container %+v, want metadata for web", ref, got)
+		}
+	}
+}
+
+func TestBuildLogsOutputUnknownContainer(t *testing.T) {
+	output := buildLogsOutput(map[string][]docker.LogEntry{"missing": nil}, nil, nil)
+
+	if got := output.Containers[0].Container; got.Name != "unknown" || got.ID != "missing" {
+		t.Errorf("got container %+v, want unknown placeholder", got)
+	}
+}
+
+func TestBuildLogsOutputCountsStructuredLevels(t *testing.T) {
+	logs := logsWith(
+		`{"level":"error","msg":"db down"}`,
+		`{"level":"info","msg":"retry after failure budget reset"}`,
+		`{"severity":"warning","message":"slow query"}`,
+		"plain exception in handler",
+		"all good",
+	)
+
+	output := buildLogsOutput(map[string][]docker.LogEntry{"web": logs}, nil, nil)
+	if output.Summary.ErrorCount != 2 {
+		t.Errorf("ErrorCount = %d, want 2", output.Summary.ErrorCount)
+	}
+	if output.Summary.WarnCount != 1 {
+		t.Errorf("WarnCount = %d, want 1", output.Summary.WarnCount)
+	}
+}
+
+func TestContainerInfoFromSummary(t *testing.T) {
+	summary := docker.ContainerSummary{Host: "staging", Summary: containertypes.Summary{
+		ID:      fullID,
+		Names:   []string{"/web"},
+		Image:   "nginx:latest",
+		ImageID: "sha256:abc123",
+		Status:  "Up 2 minutes",
+		State:   "running",
+		Created: 1700000000,
+		Labels:  map[string]string{"app": "web"},
+		Ports:   []containertypes.Port{{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"}},
+		Mounts:  []containertypes.MountPoint{{Type: "bind", Source: "/srv", Destination: "/usr/share/nginx/html", RW: true}},
+		NetworkSettings: &containertypes.NetworkSettingsSummary{
+			Networks: map[string]*network.EndpointSettings{
+				"frontend": {NetworkID: "net-frontend"},
+				"backend":  {NetworkID: "net-backend"},
+			},
+		},
+	}}
+
+	info := containerInfoFromSummary(summary)
+	if info.Name != "web" || info.ImageID != "sha256:abc123" || info.State != "running" || info.Host != "staging" {
+		t.Errorf("got %+v", info)
+	}
+	if !info.Created.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Created = %v", info.Created)
+	}
+	if len(info.Ports) != 1 || info.Ports[0] != (PortMapping{ContainerPort: 80, HostPort: 8080, Type: "tcp", HostIP: "0.0.0.0"}) {
+		t.Errorf("Ports = %+v", info.Ports)
+	}
+	if len(info.Mounts) != 1 || info.Mounts[0].Destination != "/usr/share/nginx/html" || !info.Mounts[0].RW {
+		t.Errorf("Mounts = %+v", info.Mounts)
+	}
+	if info.NetworkID != "net-backend" {
+		t.Errorf("NetworkID = %q, want the first network by name", info.NetworkID)
+	}
+
+	sdk := &Colog{}
+	if !sdk.matchesFilter(info, ContainerFilter{Labels: map[string]string{"app": "web"}, ImageID: "abc123"}) {
+		t.Error("label and image ID filter should match")
+	}
+}
+
+func TestJSONLRecordFields(t *testing.T) {
+	container := ContainerInfo{ID: fullID, Name: "web", Image: "nginx:latest"}
+	entry := docker.LogEntry{
+		ContainerID: fullID,
+		Timestamp:   time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message:     "GET / 200",
+		Stream:      "stdout",
+	}
+
+	line, err := json.Marshal(jsonlRecord(container, entry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"container_id":"` + fullID + `","container_name":"web","image":"nginx:latest","timestamp":"2025-01-02T03:04:05Z","message":"GET / 200","stream":"stdout"}`
+	if string(line) != want {
+		t.Errorf("got %s\nwant %s", line, want)
+	}
+}
+
+func TestFilterByHost(t *testing.T) {
+	c := &Colog{}
+	local := ContainerInfo{ID: fullID, Name: "web", Host: "local"}
+	staging := ContainerInfo{ID: "def456", Name: "web", Host: "staging"}
+
+	if !c.matchesFilter(staging, ContainerFilter{Host: "staging"}) {
+		t.Error("staging container should match host staging")
+	}
+	if c.matchesFilter(local, ContainerFilter{Host: "staging"}) {
+		t.Error("local container should not match host staging")
+	}
+
+	filtered := filterByHost([]ContainerInfo{local, staging}, "staging")
+	if len(filtered) != 1 || filtered[0].DisplayName() != "staging/web" {
+		t.Errorf("filterByHost = %+v, want only staging/web", filtered)
+	}
+	if got := filterByHost([]ContainerInfo{local, staging}, ""); len(got) != 2 {
+		t.Errorf("empty host kept %d containers, want 2", len(got))
+	}
+}
+
+func TestAddLabelFilter(t *testing.T) {
+	labels := make(map[string]string)
+	for _, arg := range []string{"com.docker.compose.project=myapp", "tier=web=frontend", "empty="} {
+		if err := addLabelFilter(labels, arg); err != nil {
+			t.Errorf("addLabelFilter(%q): %v", arg, err)
+		}
+	}
+	want := map[string]string{"com.docker.compose.project": "myapp", "tier": "web=frontend", "empty": ""}
+	for key, value := range want {
+		if labels[key] != value {
+			t.Errorf("labels[%q] = %q, want %q", key, labels[key], value)
+		}
+	}
+
+	for _, arg := range []string{"myapp", "=myapp", ""} {
+		if err := addLabelFilter(labels, arg); err == nil {
+			t.Errorf("addLabelFilter(%q) accepted an invalid pair", arg)
+		}
+	}
+
+	project := ContainerInfo{Labels: map[string]string{"com.docker.compose.project": "myapp", "tier": "web=frontend", "empty": ""}}
+	other := ContainerInfo{Labels: map[string]string{"com.docker.compose.project": "other"}}
+	c := &Colog{}
+	if !c.matchesFilter(project, ContainerFilter{Labels: labels}) || c.matchesFilter(other, ContainerFilter{Labels: labels}) {
+		t.Error("label filter matched the wrong containers")
+	}
+}
+
+func TestContainerInfoFromSummaryWithoutNames(t *testing.T) {
+	info := containerInfoFromSummary(docker.ContainerSummary{Summary: containertypes.Summary{
+		ID:    fullID,
+		Image: "nginx:latest",
+		State: "running",
+	}})
+	if info.Name != fullID[:12] {
+		t.Errorf("Name = %q, want the short ID", info.Name)
+	}
+}
+
+func TestGetContainerLogsTimeWindow(t *testing.T) {
+	c := newFakeColog(t)
+	logs, err := c.GetContainerLogs("web", LogOptions{
+		Tail:  2,
+		Since: time.Date(2025, 3, 4, 11, 59, 2, 0, time.UTC),
+		Until: time.Date(2025, 3, 4, 11, 59, 5, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The window holds seconds 2-4; the tail keeps its last two lines
+	if len(logs) != 2 || logs[0].Timestamp.Second() != 3 || logs[1].Timestamp.Second() != 4 {
+		t.Errorf("got %+v, want the lines of seconds 3 and 4", logs)
+	}
+}
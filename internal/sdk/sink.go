@@ -0,0 +1,207 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExportSink is the destination of an export. ExportToSink writes one collection
+// per container; Close finishes the export (sinks that render a whole document,
+// such as JSON or HTML, write it then). Sinks writing to an io.Writer don't close it.
+type ExportSink interface {
+	Write(collection ContainerLogCollection) error
+	Close() error
+}
+
+// ExportToSink exports the containers' logs to sink one container at a time, so
+// streaming sinks hold only a single container's logs in memory. Containers are
+// written in the order of their sorted references, like ExportLogsForLLM; sink is
+// not closed.
+func (c *Colog) ExportToSink(containerIDs []string, options LogOptions, sink ExportSink) error {
+	containers, err := c.ListAllContainers()
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+	return c.ExportContainersToSink(containers, containerIDs, options, sink)
+}
+
+// ExportContainersToSink is ExportToSink with the container list already fetched
+// (from ListAllContainers), resolving containerIDs against it rather than listing
+// the containers again
+func (c *Colog) ExportContainersToSink(containers []ContainerInfo, containerIDs []string, options LogOptions, sink ExportSink) error {
+	c.attachExportDetails(containers, containerIDs)
+	if configurable, ok := sink.(markdownConfigurable); ok {
+		configurable.setMarkdownOptions(markdownOptions{template: c.markdownTemplate, lineNumbers: options.LineNumbers})
+	}
+
+	refs := make([]string, 0, len(containerIDs))
+	seen := make(map[string]bool)
+	for _, ref := range containerIDs {
+		if !seen[ref] {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	sort.Strings(refs)
+
+	for _, ref := range refs {
+		if err := c.ctx.Err(); err != nil {
+			return fmt.Errorf("failed to retrieve logs: %w", err)
+		}
+		logs, err := c.GetContainerLogs(ref, options)
+		collection := newLogCollection(resolveContainer(containers, ref), logs)
+		if err != nil {
+			collection.Error = err.Error()
+		}
+		if err := sink.Write(collection); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewFormatSink returns a sink writing the export to w in format: jsonl streams each
+// container's lines as they come; json, markdown (md) and html render one document
+// on Close
+func NewFormatSink(w io.Writer, format string) (ExportSink, error) {
+	switch strings.ToLower(format) {
+	case "jsonl":
+		return NewJSONLSink(w), nil
+	case "json", "markdown", "md", "html":
+		return &documentSink{w: w, format: strings.ToLower(format)}, nil
+	}
+	return nil, fmt.Errorf("unsupported format: %s (supported: json, jsonl, markdown, html)", format)
+}
+
+// jsonlSink writes one JSONLRecord per log line
+type jsonlSink struct {
+	encoder *json.Encoder
+}
+
+// NewJSONLSink returns a sink writing each log line to w as a JSON object
+func NewJSONLSink(w io.Writer) ExportSink {
+	return &jsonlSink{encoder: json.NewEncoder(w)}
+}
+
+func (s *jsonlSink) Write(collection ContainerLogCollection) error {
+	for _, entry := range collection.Logs {
+		if err := s.encoder.Encode(jsonlRecord(collection.Container, entry)); err != nil {
+			return fmt.Errorf("failed to write JSONL: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *jsonlSink) Close() error {
+	return nil
+}
+
+// documentSink collects the collections and renders them as one JSON, markdown or
+// HTML document, with its summary, on Close
+type documentSink struct {
+	w           io.Writer
+	format      string
+	collections []ContainerLogCollection
+	markdown    markdownOptions
+}
+
+func (s *documentSink) setMarkdownOptions(opts markdownOptions) {
+	s.markdown = opts
+}
+
+func (s *documentSink) Write(collection ContainerLogCollection) error {
+	s.collections = append(s.collections, collection)
+	return nil
+}
+
+func (s *documentSink) Close() error {
+	output := newLogsOutput(s.collections)
+
+	var document string
+	var err error
+	switch s.format {
+	case "json":
+		document, err = renderJSON(output)
+	case "html":
+		document, err = renderHTML(output)
+	default:
+		document, err = renderMarkdown(output, s.markdown)
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(document, "\n") {
+		document += "\n"
+	}
+	if _, err := io.WriteString(s.w, document); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+	return nil
+}
+
+// dirSink writes each container to its own file in a directory
+type dirSink struct {
+	dir      string
+	format   string
+	ext      string
+	markdown markdownOptions
+}
+
+func (s *dirSink) setMarkdownOptions(opts markdownOptions) {
+	s.markdown = opts
+}
+
+// NewDirSink returns a sink writing each container's export to its own file in dir,
+// named after the container ("web.jsonl", "staging_api.md"), in format
+func NewDirSink(dir, format string) (ExportSink, error) {
+	format = strings.ToLower(format)
+	if _, err := NewFormatSink(io.Discard, format); err != nil {
+		return nil, err
+	}
+	ext := format
+	if format == "markdown" {
+		ext = "md"
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return &dirSink{dir: dir, format: format, ext: ext}, nil
+}
+
+func (s *dirSink) Write(collection ContainerLogCollection) error {
+	name := strings.ReplaceAll(collection.Container.DisplayName(), "/", "_")
+	if name == "" || name == "unknown" {
+		name = collection.Container.ID
+	}
+	path := filepath.Join(s.dir, name+"."+s.ext)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	sink, _ := NewFormatSink(file, s.format)
+	if configurable, ok := sink.(markdownConfigurable); ok {
+		configurable.setMarkdownOptions(s.markdown)
+	}
+	if err := sink.Write(collection); err != nil {
+		file.Close()
+		return err
+	}
+	if err := sink.Close(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s *dirSink) Close() error {
+	return nil
+}
@@ -0,0 +1,153 @@
+package sdk
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatSinkMatchesExports(t *testing.T) {
+	c := newFakeColog(t)
+	ids := []string{cacheID, webID, workerID} // sorted like ExportLogsForLLM does
+
+	exports := map[string]func([]string, LogOptions) (string, error){
+		"json":     c.ExportLogsAsJSON,
+		"markdown": c.ExportLogsAsMarkdown,
+		"html":     c.ExportLogsAsHTML,
+	}
+	for format, export := range exports {
+		want, err := export(ids, LogOptions{Tail: 100})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		sink, err := NewFormatSink(&buf, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ExportToSink(ids, LogOptions{Tail: 100}, sink); err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.TrimSuffix(buf.String(), "\n"); got != strings.TrimSuffix(want, "\n") {
+			t.Errorf("%s sink differs from the %s export:\n%s", format, format, got)
+		}
+	}
+
+	if _, err := NewFormatSink(io.Discard, "yaml"); err == nil {
+		t.Error("unsupported format accepted")
+	}
+}
+
+func TestJSONLSinkStreamsRecords(t *testing.T) {
+	c := newFakeColog(t)
+	var buf bytes.Buffer
+	if err := c.ExportToSink([]string{workerID, webID, webID}, LogOptions{Tail: 100}, NewJSONLSink(&buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("got %d records, want 6 (duplicate refs export once)", len(lines))
+	}
+	var first JSONLRecord
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if first.ContainerName != "web" || first.Message != "GET / 200" {
+		t.Errorf("first record = %+v", first)
+	}
+}
+
+func TestDirSinkWritesFilePerContainer(t *testing.T) {
+	c := newFakeColog(t)
+	dir := filepath.Join(t.TempDir(), "logs")
+	sink, err := NewDirSink(dir, "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ExportToSink([]string{webID, workerID, cacheID}, LogOptions{Tail: 100}, sink); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"web", "worker", "cache"} {
+		data, err := os.ReadFile(filepath.Join(dir, name+".md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "## Container: "+name) || strings.Count(string(data), "## Container: ") != 1 {
+			t.Errorf("%s.md is not a single-container export:\n%s", name, data)
+		}
+	}
+}
+
+func TestExportSinkCompressesGz(t *testing.T) {
+	c := newFakeColog(t)
+	path := filepath.Join(t.TempDir(), "logs.jsonl.gz")
+	sink, closeOutput, err := exportSink("jsonl", path, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ExportToSink([]string{workerID}, LogOptions{Tail: 100}, sink); err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := closeOutput(); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("output is not gzip: %v", err)
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"message":"job 42 done"`) {
+		t.Errorf("decompressed output = %s", data)
+	}
+}
+
+func TestExportContainersToSinkListsOnce(t *testing.T) {
+	c := newFakeColog(t)
+	backend := c.dockerService.(*fakeDocker)
+
+	containers, err := c.ListAllContainers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs, err := exportTargets(containers, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := c.ExportContainersToSink(containers, refs, LogOptions{Tail: 100}, NewJSONLSink(&buf)); err != nil {
+		t.Fatal(err)
+	}
+
+	if backend.lists != 1 {
+		t.Errorf("export listed containers %d times, want 1", backend.lists)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 6 {
+		t.Errorf("exported %d lines, want 6", n)
+	}
+}
@@ -0,0 +1,68 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// MarkdownTemplate customizes the markdown export around the container sections, so
+// teams can add their own boilerplate (ticket links, authors, severity prompts).
+// Header replaces the "# Docker Container Logs Analysis" summary block; Footer is
+// appended after the last container. Both are text/template templates executed
+// with the export's *LogsOutput; a nil template keeps the default.
+type MarkdownTemplate struct {
+	Header *template.Template
+	Footer *template.Template
+}
+
+// markdownFuncs are available to header and footer templates
+var markdownFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// ParseMarkdownTemplate parses header and footer templates; an empty string keeps
+// the default for that part. For example:
+//
+//	# Incident {{.Summary.ErrorCount}} errors across {{.Summary.TotalContainers}} containers
+//	Ticket: https://tracker.example.com/INC-
+func ParseMarkdownTemplate(header, footer string) (*MarkdownTemplate, error) {
+	tmpl := &MarkdownTemplate{}
+	var err error
+	if header != "" {
+		if tmpl.Header, err = template.New("header").Funcs(markdownFuncs).Parse(header); err != nil {
+			return nil, fmt.Errorf("invalid header template: %w", err)
+		}
+	}
+	if footer != "" {
+		if tmpl.Footer, err = template.New("footer").Funcs(markdownFuncs).Parse(footer); err != nil {
+			return nil, fmt.Errorf("invalid footer template: %w", err)
+		}
+	}
+	return tmpl, nil
+}
+
+// SetMarkdownTemplate makes markdown exports, including those written through
+// sinks, use tmpl; nil restores the default layout
+func (c *Colog) SetMarkdownTemplate(tmpl *MarkdownTemplate) {
+	c.markdownTemplate = tmpl
+}
+
+// executeTemplate renders tmpl with output, ending it with a newline
+func executeTemplate(tmpl *template.Template, output *LogsOutput) (string, error) {
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, output); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", tmpl.Name(), err)
+	}
+	text := buf.String()
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	return text, nil
+}
+
+// markdownConfigurable is implemented by sinks that render markdown, so ExportToSink
+// can hand them the Colog's template and the export's line numbering
+type markdownConfigurable interface {
+	setMarkdownOptions(opts markdownOptions)
+}
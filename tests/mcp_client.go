@@ -211,6 +211,14 @@ func (c *MCPClient) GetContainerLogs(containerID string, tail int) ([]LogEntry,
 	return logs, nil
 }
 
+// shortID shortens a container ID for logging without cutting a character in two
+func shortID(id string) string {
+	if runes := []rune(id); len(runes) > 12 {
+		return string(runes[:12])
+	}
+	return id
+}
+
 // Helper function to check if MCP server is running
 func isMCPServerRunning(baseURL string) bool {
 	client := &http.Client{Timeout: 5 * time.Second}
@@ -342,7 +350,7 @@ func TestMCPListContainers(t *testing.T) {
 
 	t.Logf("✓ Found %d containers", len(containers))
 	for i, container := range containers {
-		t.Logf("  Container %d: %s (%s) - %s", i+1, container.Name, container.ID[:12], container.Status)
+		t.Logf("  Container %d: %s (%s) - %s", i+1, container.Name, shortID(container.ID), container.Status)
 		
 		// Test getting logs for this container
 		if container.Status == "running" {